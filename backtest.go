@@ -0,0 +1,473 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Date layouts of historical endpoints
+const (
+	layoutDate     = "2006-01-02"
+	layoutDateTime = "2006-01-02 15:04:05"
+)
+
+// BacktestConfig for create new backtest
+type BacktestConfig struct {
+	Asset              objects.BacktestAsset      // default stock
+	Symbols            []string                   // instruments to replay
+	From               time.Time                  // first date of history
+	To                 time.Time                  // last date of history
+	Period             *objects.StockCandlePeriod // intraday period, nil - daily candles
+	InitialCash        float64                    // starting cash
+	Slippage           float64                    // fraction of price, 0.001 = 10 bps
+	CommissionPerOrder float64                    // fixed fee per fill
+	CommissionPerShare float64                    // fee per unit
+	CommissionPercent  float64                    // fraction of notional, 0.001 = 10 bps
+	CorporateActions   bool                       // replay as-traded prices with splits and dividends (stock only)
+	// SurvivorshipBiasFree - load daily bars from the v4 survivorship bias free endpoint
+	// when regular history is empty (delisted companies). It costs one request per weekday,
+	// ~260 a year per symbol, so set Config.RateLimiter of the client for long ranges
+	SurvivorshipBiasFree bool
+	Store                *TimeSeriesStore // bars are read through local store, only missing dates are loaded
+}
+
+// BacktestStrategy - receives every bar in time order, orders are filled on the next bar open
+type BacktestStrategy interface {
+	OnBar(broker *BacktestBroker, bar objects.Bar)
+}
+
+// BacktestStrategyFunc - adapter to use ordinary function as strategy
+type BacktestStrategyFunc func(broker *BacktestBroker, bar objects.Bar)
+
+// OnBar ...
+func (f BacktestStrategyFunc) OnBar(broker *BacktestBroker, bar objects.Bar) {
+	f(broker, bar)
+}
+
+// Backtest - event-driven backtesting engine on top of historical endpoints
+type Backtest struct {
+	Client *APIClient
+	Config BacktestConfig
+}
+
+// BacktestBroker - simulated account available to strategy
+type BacktestBroker struct {
+	cfg       BacktestConfig
+	now       time.Time
+	cash      float64
+	positions map[string]float64
+	prices    map[string]float64
+	pending   map[string][]backtestOrder
+	result    *objects.BacktestResult
+}
+
+type backtestOrder struct {
+	side     objects.BacktestOrderSide
+	quantity float64
+}
+
+type backtestAction struct {
+	date     time.Time
+	split    float64 // numerator / denominator, 0 - no split
+	dividend float64 // per share cash
+}
+
+// NewBacktest creates a new backtest
+func NewBacktest(client *APIClient, cfg BacktestConfig) (*Backtest, error) {
+	if client == nil {
+		return nil, errors.New("Error empty api client")
+	}
+
+	if len(cfg.Symbols) == 0 {
+		return nil, errors.New("Error empty symbol list")
+	}
+
+	if !cfg.To.After(cfg.From) {
+		return nil, errors.New("Error invalid period: to must be after from")
+	}
+
+	if len(cfg.Asset) == 0 {
+		cfg.Asset = objects.BacktestAssetStock
+	}
+
+	if cfg.Asset != objects.BacktestAssetStock {
+		cfg.CorporateActions = false
+		cfg.SurvivorshipBiasFree = false
+	}
+
+	return &Backtest{Client: client, Config: cfg}, nil
+}
+
+// Run - load history and replay it through strategy
+func (b *Backtest) Run(strategy BacktestStrategy) (*objects.BacktestResult, error) {
+	var barList []objects.Bar
+	actions := make(map[string][]backtestAction)
+	for _, symbol := range b.Config.Symbols {
+		bars, err := b.bars(symbol)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load bars for %s", symbol)
+		}
+
+		if b.Config.CorporateActions {
+			actions[symbol], err = b.actions(symbol)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error load corporate actions for %s", symbol)
+			}
+
+			bars = unadjustSplits(bars, actions[symbol])
+		}
+
+		barList = append(barList, bars...)
+	}
+
+	order := make(map[string]int, len(b.Config.Symbols))
+	for i, symbol := range b.Config.Symbols {
+		order[symbol] = i
+	}
+
+	sort.SliceStable(barList, func(i, j int) bool {
+		if !barList[i].Date.Equal(barList[j].Date) {
+			return barList[i].Date.Before(barList[j].Date)
+		}
+
+		return order[barList[i].Symbol] < order[barList[j].Symbol]
+	})
+
+	return b.replay(barList, actions, strategy), nil
+}
+
+// Replay - run strategy over already loaded bars (no corporate actions)
+func (b *Backtest) Replay(barList []objects.Bar, strategy BacktestStrategy) *objects.BacktestResult {
+	barList = append([]objects.Bar(nil), barList...)
+	sort.SliceStable(barList, func(i, j int) bool {
+		return barList[i].Date.Before(barList[j].Date)
+	})
+
+	return b.replay(barList, nil, strategy)
+}
+
+func (b *Backtest) replay(barList []objects.Bar, actions map[string][]backtestAction, strategy BacktestStrategy) *objects.BacktestResult {
+	broker := &BacktestBroker{
+		cfg:       b.Config,
+		cash:      b.Config.InitialCash,
+		positions: make(map[string]float64),
+		prices:    make(map[string]float64),
+		pending:   make(map[string][]backtestOrder),
+		result:    &objects.BacktestResult{InitialCash: b.Config.InitialCash},
+	}
+
+	for i, bar := range barList {
+		broker.now = bar.Date
+		broker.applyActions(bar, actions)
+		broker.fill(bar)
+		broker.prices[bar.Symbol] = bar.Close
+		strategy.OnBar(broker, bar)
+
+		if i+1 == len(barList) || !barList[i+1].Date.Equal(bar.Date) {
+			broker.result.EquityCurve = append(broker.result.EquityCurve, objects.BacktestEquityPoint{
+				Date:   bar.Date,
+				Cash:   broker.cash,
+				Equity: broker.Equity(),
+			})
+		}
+	}
+
+	broker.summarize()
+
+	return broker.result
+}
+
+// Buy - market order filled on the next bar open
+func (br *BacktestBroker) Buy(symbol string, quantity float64) {
+	br.order(symbol, objects.BacktestOrderSideBuy, quantity)
+}
+
+// Sell - market order filled on the next bar open, short selling is not supported
+func (br *BacktestBroker) Sell(symbol string, quantity float64) {
+	br.order(symbol, objects.BacktestOrderSideSell, quantity)
+}
+
+// Time - time of current bar
+func (br *BacktestBroker) Time() time.Time {
+	return br.now
+}
+
+// Cash - available cash
+func (br *BacktestBroker) Cash() float64 {
+	return br.cash
+}
+
+// Position - quantity held
+func (br *BacktestBroker) Position(symbol string) float64 {
+	return br.positions[symbol]
+}
+
+// Equity - cash plus positions marked at last close
+func (br *BacktestBroker) Equity() float64 {
+	equity := br.cash
+	for symbol, quantity := range br.positions {
+		equity += quantity * br.prices[symbol]
+	}
+
+	return equity
+}
+
+func (br *BacktestBroker) order(symbol string, side objects.BacktestOrderSide, quantity float64) {
+	if quantity <= 0 {
+		return
+	}
+
+	br.pending[symbol] = append(br.pending[symbol], backtestOrder{side: side, quantity: quantity})
+}
+
+func (br *BacktestBroker) applyActions(bar objects.Bar, actions map[string][]backtestAction) {
+	list := actions[bar.Symbol]
+	for len(list) > 0 && !list[0].date.After(bar.Date) {
+		action := list[0]
+		list = list[1:]
+
+		if action.split > 0 {
+			br.positions[bar.Symbol] *= action.split
+			for i := range br.pending[bar.Symbol] {
+				br.pending[bar.Symbol][i].quantity *= action.split
+			}
+		}
+
+		if action.dividend > 0 && br.positions[bar.Symbol] > 0 {
+			cash := br.positions[bar.Symbol] * action.dividend
+			br.cash += cash
+			br.result.Dividends += cash
+		}
+	}
+
+	if actions != nil {
+		actions[bar.Symbol] = list
+	}
+}
+
+func (br *BacktestBroker) fill(bar objects.Bar) {
+	orders := br.pending[bar.Symbol]
+	if len(orders) == 0 {
+		return
+	}
+
+	delete(br.pending, bar.Symbol)
+	for _, o := range orders {
+		price := bar.Open
+		if o.side == objects.BacktestOrderSideBuy {
+			price *= 1 + br.cfg.Slippage
+		} else {
+			price *= 1 - br.cfg.Slippage
+		}
+
+		trade := objects.BacktestTrade{
+			Date:     bar.Date,
+			Symbol:   bar.Symbol,
+			Side:     o.side,
+			Quantity: o.quantity,
+			Price:    price,
+			Commission: br.cfg.CommissionPerOrder +
+				br.cfg.CommissionPerShare*o.quantity +
+				br.cfg.CommissionPercent*o.quantity*price,
+		}
+
+		switch o.side {
+		case objects.BacktestOrderSideBuy:
+			cost := trade.Quantity*trade.Price + trade.Commission
+			if cost > br.cash {
+				br.result.RejectedList = append(br.result.RejectedList, trade)
+				continue
+			}
+
+			br.cash -= cost
+			br.positions[bar.Symbol] += trade.Quantity
+		case objects.BacktestOrderSideSell:
+			if trade.Quantity > br.positions[bar.Symbol] {
+				br.result.RejectedList = append(br.result.RejectedList, trade)
+				continue
+			}
+
+			br.cash += trade.Quantity*trade.Price - trade.Commission
+			br.positions[bar.Symbol] -= trade.Quantity
+			if br.positions[bar.Symbol] == 0 {
+				delete(br.positions, bar.Symbol)
+			}
+		}
+
+		br.result.Commissions += trade.Commission
+		br.result.Trades = append(br.result.Trades, trade)
+	}
+}
+
+func (br *BacktestBroker) summarize() {
+	result := br.result
+	result.FinalEquity = result.InitialCash
+	if len(result.EquityCurve) > 0 {
+		result.FinalEquity = result.EquityCurve[len(result.EquityCurve)-1].Equity
+	}
+
+	if result.InitialCash != 0 {
+		result.TotalReturn = result.FinalEquity/result.InitialCash - 1
+	}
+
+	peak := math.Inf(-1)
+	for _, point := range result.EquityCurve {
+		peak = math.Max(peak, point.Equity)
+		if peak > 0 {
+			result.MaxDrawdown = math.Max(result.MaxDrawdown, 1-point.Equity/peak)
+		}
+	}
+}
+
+// bars - load history of one symbol in ascending order
+func (b *Backtest) bars(symbol string) (bars []objects.Bar, err error) {
+	cfg := b.Config
+	resolution := objects.Resolution1Day
+	if cfg.Period != nil {
+		resolution = objects.Resolution(*cfg.Period)
+	}
+
+	req := objects.RequestCandles{Symbol: symbol, Resolution: resolution, From: cfg.From, To: cfg.To}
+	if cfg.Store != nil {
+		bars, err = cfg.Store.History(req)
+	} else {
+		bars, err = History(b.Client, req)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bars) == 0 && cfg.Period == nil && cfg.SurvivorshipBiasFree {
+		bars, err = b.survivorshipBiasFreeBars(symbol)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(bars, func(i, j int) bool {
+		return bars[i].Date.Before(bars[j].Date)
+	})
+
+	return bars, nil
+}
+
+// survivorshipBiasFreeBars - bars of weekdays, days without data are skipped and other errors returned
+func (b *Backtest) survivorshipBiasFreeBars(symbol string) (bars []objects.Bar, err error) {
+	for day := b.Config.From; !day.After(b.Config.To); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		sBias, err := b.Client.Stock.SurvivorshipBiasFree(symbol, day)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load survivorship bias free %s %s", symbol, day.Format(layoutDate))
+		}
+
+		if sBias == nil || sBias.Close == 0 {
+			continue
+		}
+
+		bars = append(bars, objects.Bar{
+			Symbol: symbol,
+			Date:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+			Open:   sBias.Open,
+			High:   sBias.High,
+			Low:    sBias.Low,
+			Close:  sBias.Close,
+			Volume: sBias.Volume,
+		})
+	}
+
+	return bars, nil
+}
+
+// actions - splits and dividends of one symbol in ascending order
+func (b *Backtest) actions(symbol string) ([]backtestAction, error) {
+	var actions []backtestAction
+
+	splits, err := b.Client.Stock.Splits(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if splits != nil {
+		for _, s := range splits.Historical {
+			date, err := parseBarDate(s.Date)
+			if err != nil || s.Numerator <= 0 || s.Denominator <= 0 {
+				continue
+			}
+
+			actions = append(actions, backtestAction{date: date, split: s.Numerator / s.Denominator})
+		}
+	}
+
+	dividends, err := b.Client.Stock.Dividends(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if dividends != nil {
+		for _, d := range dividends.Historical {
+			date, err := parseBarDate(d.Date)
+			if err != nil || d.Dividend <= 0 {
+				continue
+			}
+
+			actions = append(actions, backtestAction{date: date, dividend: d.Dividend})
+		}
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].date.Before(actions[j].date)
+	})
+
+	return actions, nil
+}
+
+// unadjustSplits - convert split-adjusted prices into as-traded prices
+func unadjustSplits(bars []objects.Bar, actions []backtestAction) []objects.Bar {
+	for i := range bars {
+		ratio := 1.0
+		for _, action := range actions {
+			if action.split > 0 && action.date.After(bars[i].Date) {
+				ratio *= action.split
+			}
+		}
+
+		if ratio == 1 {
+			continue
+		}
+
+		bars[i].Open *= ratio
+		bars[i].High *= ratio
+		bars[i].Low *= ratio
+		bars[i].Close *= ratio
+		bars[i].Volume /= ratio
+	}
+
+	return bars
+}
+
+// Universe - index members on date, including companies removed since then
+func (b *Backtest) Universe(index objects.Index, date time.Time) ([]string, error) {
+	membership, err := b.Client.Stock.IndexMembership(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership.Members(date), nil
+}
+
+// parseBarDate - parse date of daily and intraday candles
+func parseBarDate(s string) (time.Time, error) {
+	if len(s) > len(layoutDate) {
+		return time.Parse(layoutDateTime, s)
+	}
+
+	return time.Parse(layoutDate, s)
+}
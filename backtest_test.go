@@ -0,0 +1,131 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestBacktestRun(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-price-full/AAPL": `{"symbol":"AAPL","historical":[
+			{"date":"2020-01-06","open":100,"high":111,"low":99,"close":110,"volume":1000},
+			{"date":"2020-01-03","open":100,"high":101,"low":99,"close":100,"volume":1000},
+			{"date":"2020-01-02","open":100,"high":101,"low":99,"close":100,"volume":1000},
+			{"date":"2020-01-01","open":100,"high":101,"low":99,"close":100,"volume":1000}]}`,
+		"/v3/historical-price-full/stock_split/AAPL":    `{"symbol":"AAPL","historical":[{"date":"2020-01-03","numerator":2,"denominator":1}]}`,
+		"/v3/historical-price-full/stock_dividend/AAPL": `{"symbol":"AAPL","historical":[{"date":"2020-01-06","dividend":1}]}`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	backtest, err := NewBacktest(APIClient, BacktestConfig{
+		Symbols:            []string{"AAPL"},
+		From:               time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:                 time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC),
+		InitialCash:        10000,
+		CommissionPerOrder: 1,
+		CorporateActions:   true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err := backtest.Run(BacktestStrategyFunc(func(broker *BacktestBroker, bar objects.Bar) {
+		if broker.Position(bar.Symbol) == 0 {
+			broker.Buy(bar.Symbol, 10)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(result.Trades) != 1 || result.Trades[0].Price != 200 {
+		t.Fatalf("unexpected trades: %+v", result.Trades)
+	}
+
+	if result.Dividends != 20 {
+		t.Fatalf("unexpected dividends: %v", result.Dividends)
+	}
+
+	if len(result.EquityCurve) != 4 || math.Abs(result.FinalEquity-10219) > 1e-9 {
+		t.Fatalf("unexpected final equity: %v", result.FinalEquity)
+	}
+}
+
+func TestBacktestReplaySlippage(t *testing.T) {
+	backtest, err := NewBacktest(&APIClient{}, BacktestConfig{
+		Symbols:     []string{"BTCUSD"},
+		From:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC),
+		InitialCash: 1000,
+		Slippage:    0.01,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	bars := []objects.Bar{
+		{Symbol: "BTCUSD", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Open: 100, Close: 100},
+		{Symbol: "BTCUSD", Date: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Open: 100, Close: 80},
+		{Symbol: "BTCUSD", Date: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), Open: 100, Close: 100},
+	}
+
+	result := backtest.Replay(bars, BacktestStrategyFunc(func(broker *BacktestBroker, bar objects.Bar) {
+		switch broker.Position(bar.Symbol) {
+		case 0:
+			broker.Buy(bar.Symbol, 20)
+			broker.Buy(bar.Symbol, 5)
+		default:
+			broker.Sell(bar.Symbol, 5)
+		}
+	}))
+
+	if len(result.RejectedList) != 1 {
+		t.Fatalf("expected one rejected order, got %+v", result.RejectedList)
+	}
+
+	if result.Trades[0].Price != 101 || result.Trades[1].Price != 99 {
+		t.Fatalf("unexpected fill prices: %+v", result.Trades)
+	}
+
+	if result.MaxDrawdown <= 0 {
+		t.Fatalf("expected drawdown, got %v", result.MaxDrawdown)
+	}
+}
+
+func TestBacktestSurvivorshipBiasFree(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-price-full/DEAD":            `{}`,
+		"/v4/historical-price-full/DEAD/2020-01-02": `{"symbol":"DEAD","open":1,"high":3,"low":1,"close":2,"volume":10}`,
+		"/v4/historical-price-full/DEAD/2020-01-03": `[]`,
+		"/v3/historical-price-full/BAD":             `{}`,
+		"/v4/historical-price-full/BAD/2020-01-02":  `not json`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	backtest, err := NewBacktest(APIClient, BacktestConfig{
+		Symbols:              []string{"DEAD"},
+		From:                 time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:                   time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC),
+		SurvivorshipBiasFree: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Days without data are 404 or empty list
+	bars, err := backtest.bars("DEAD")
+	if err != nil || len(bars) != 1 || bars[0].Close != 2 {
+		t.Fatalf("unexpected survivorship bias free bars: %+v %v", bars, err)
+	}
+
+	if _, err := backtest.bars("BAD"); err == nil {
+		t.Fatal("expected survivorship bias free error")
+	}
+}
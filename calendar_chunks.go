@@ -0,0 +1,24 @@
+package fmpcloud
+
+import "time"
+
+// loadCalendarChunks - calendar endpoint limited to maxDays called for each part of from..to
+func loadCalendarChunks[T any](from, to time.Time, maxDays int, load func(from, to *time.Time) ([]T, error)) ([]T, error) {
+	var list []T
+	for start := from; !start.After(to); start = start.AddDate(0, 0, maxDays+1) {
+		end := start.AddDate(0, 0, maxDays)
+		if end.After(to) {
+			end = to
+		}
+
+		from, to := start, end
+		cList, err := load(&from, &to)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, cList...)
+	}
+
+	return list, nil
+}
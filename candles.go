@@ -0,0 +1,226 @@
+package fmpcloud
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Url const for request
+const (
+	urlAPICandles      = "/v3/historical-chart/%s/%s"
+	urlAPIDailyCandles = "/v3/historical-price-full/%s"
+)
+
+// candleMaxDays - days of one request, historical chart endpoints cap bars per call
+var candleMaxDays = map[objects.Resolution]int{
+	objects.Resolution1Min:  3,
+	objects.Resolution5Min:  10,
+	objects.Resolution15Min: 30,
+	objects.Resolution30Min: 60,
+	objects.Resolution1Hour: 90,
+	objects.Resolution4Hour: 365,
+	objects.Resolution1Day:  1825,
+}
+
+// candleDefaultLocation - exchange time zone of intraday stock candles
+const candleDefaultLocation = "America/New_York"
+
+// Candles - candles of any instrument as stock, crypto or forex candle type, newest first.
+// Long ranges are split into requests by resolution.
+func Candles[T objects.CandleTypes](client *APIClient, req objects.RequestCandles) ([]T, error) {
+	return loadTypedCandles[T](client.Stock.Client, req)
+}
+
+// History - bars of any instrument oldest first. Intraday candle dates are wall clock of
+// req.Location, America/New_York by default, daily bars are dates at midnight UTC by default.
+func History(client *APIClient, req objects.RequestCandles) ([]objects.Bar, error) {
+	location, err := candleLocation(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cList, err := loadCandles(client.Stock.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return candleBars(req.Symbol, cList, location)
+}
+
+// candleRequest - request of candles with optional range of stock, crypto and forex candle requests
+func candleRequest(symbol string, resolution objects.Resolution, from, to *time.Time) objects.RequestCandles {
+	req := objects.RequestCandles{Symbol: symbol, Resolution: resolution}
+	if from != nil {
+		req.From = *from
+	}
+
+	if to != nil {
+		req.To = *to
+	}
+
+	return req
+}
+
+// candleLocation - time zone of candle dates of request
+func candleLocation(req objects.RequestCandles) (*time.Location, error) {
+	if req.Location != nil {
+		return req.Location, nil
+	}
+
+	if req.Resolution == objects.Resolution1Day {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(candleDefaultLocation)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load exchange time zone")
+	}
+
+	return location, nil
+}
+
+// loadTypedCandles - candles of request as stock, crypto or forex candle type, newest first
+func loadTypedCandles[T objects.CandleTypes](client *HTTPClient, req objects.RequestCandles) ([]T, error) {
+	cList, err := loadCandles(client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]T, len(cList))
+	for i, c := range cList {
+		switch v := any(&list[i]).(type) {
+		case *objects.StockCandle:
+			*v = c
+		case *objects.CryptoCandle:
+			*v = objects.CryptoCandle{Date: c.Date, Open: c.Open, Low: c.Low, High: c.High, Close: c.Close, Volume: int64(c.Volume)}
+		case *objects.ForexCandle:
+			*v = objects.ForexCandle(c)
+		}
+	}
+
+	return list, nil
+}
+
+// candleBars - bars oldest first, candle dates are wall clock of location
+func candleBars(symbol string, cList []objects.StockCandle, location *time.Location) ([]objects.Bar, error) {
+	bars := make([]objects.Bar, 0, len(cList))
+	for _, c := range cList {
+		date, err := parseBarDate(c.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		if location != time.UTC {
+			date = time.Date(date.Year(), date.Month(), date.Day(), date.Hour(), date.Minute(), date.Second(), 0, location)
+		}
+
+		bars = append(bars, objects.Bar{Symbol: symbol, Date: date, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close, Volume: c.Volume})
+	}
+
+	sort.SliceStable(bars, func(i, j int) bool {
+		return bars[i].Date.Before(bars[j].Date)
+	})
+
+	return bars, nil
+}
+
+// loadCandles - candles of chunks without duplicates of chunk bounds, newest first
+func loadCandles(client *HTTPClient, req objects.RequestCandles) ([]objects.StockCandle, error) {
+	if len(req.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	maxDays, ok := candleMaxDays[req.Resolution]
+	if !ok {
+		return nil, errors.Errorf("Error unknown resolution %s", req.Resolution)
+	}
+
+	load := func(from, to *time.Time) ([]objects.StockCandle, error) {
+		return loadCandleChunk(client, req.Symbol, req.Resolution, from, to)
+	}
+
+	var (
+		cList []objects.StockCandle
+		err   error
+	)
+
+	if req.From.IsZero() {
+		// Full history up to To
+		var to *time.Time
+		if !req.To.IsZero() {
+			to = &req.To
+		}
+
+		cList, err = load(nil, to)
+	} else {
+		to := req.To
+		if to.IsZero() {
+			to = time.Now()
+		}
+
+		cList, err = loadCalendarChunks(req.From, to, maxDays, load)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error load %s candles of %s", req.Resolution, req.Symbol)
+	}
+
+	seen := make(map[string]bool, len(cList))
+	list := make([]objects.StockCandle, 0, len(cList))
+	for _, c := range cList {
+		if !seen[c.Date] {
+			seen[c.Date] = true
+			list = append(list, c)
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date > list[j].Date })
+
+	return list, nil
+}
+
+func loadCandleChunk(client *HTTPClient, symbol string, resolution objects.Resolution, from, to *time.Time) ([]objects.StockCandle, error) {
+	reqParam := make(map[string]string)
+	if from != nil {
+		reqParam["from"] = from.Format(layoutDate)
+	}
+
+	if to != nil {
+		reqParam["to"] = to.Format(layoutDate)
+	}
+
+	if resolution != objects.Resolution1Day {
+		data, err := client.Get(fmt.Sprintf(urlAPICandles, resolution, symbol), reqParam)
+		if err != nil {
+			return nil, err
+		}
+
+		var cList []objects.StockCandle
+		if err := jsoniter.Unmarshal(data.Body(), &cList); err != nil {
+			return nil, err
+		}
+
+		return cList, nil
+	}
+
+	data, err := client.Get(fmt.Sprintf(urlAPIDailyCandles, symbol), reqParam)
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty object when symbol has no history
+	var daily struct {
+		Historical []objects.StockCandle `json:"historical"`
+	}
+
+	if err := jsoniter.Unmarshal(data.Body(), &daily); err != nil {
+		return nil, err
+	}
+
+	return daily.Historical, nil
+}
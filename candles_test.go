@@ -0,0 +1,90 @@
+package fmpcloud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestCandles(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-chart/1min/EURUSD?from=2021-01-01&to=2021-01-04": `[{"date":"2021-01-04 00:00:00","close":1.2},{"date":"2021-01-01 00:01:00","close":1.1}]`,
+		"/v3/historical-chart/1min/EURUSD?from=2021-01-05&to=2021-01-06": `[{"date":"2021-01-06 00:00:00","close":1.4},{"date":"2021-01-04 00:00:00","close":1.2}]`,
+		"/v3/historical-chart/1min/EURUSD":                               `[]`,
+		"/v3/historical-price-full/BTCUSD":                               `{"symbol":"BTCUSD","historical":[{"date":"2021-01-02","close":2,"volume":12.7},{"date":"2021-01-01","close":1,"volume":10}]}`,
+		"/v3/historical-price-full/BTCUSD?to=2021-01-01":                 `{"symbol":"BTCUSD","historical":[{"date":"2021-01-01","close":1,"volume":10}]}`,
+		"/v3/historical-price-full/NONE":                                 `{}`,
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-29&to=2021-11-29":  `[{"date":"2021-11-29 10:30:00","close":2},{"date":"2021-11-29 09:30:00","close":1}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	req := objects.RequestCandles{
+		Symbol:     "EURUSD",
+		Resolution: objects.Resolution1Min,
+		From:       time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2021, 1, 6, 0, 0, 0, 0, time.UTC),
+	}
+
+	fList, err := Candles[objects.ForexCandle](APIClient, req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(fList) != 3 || fList[0].Date != "2021-01-06 00:00:00" || fList[2].Close != 1.1 {
+		t.Fatalf("unexpected split range candles: %+v", fList)
+	}
+
+	bars, err := History(APIClient, objects.RequestCandles{Symbol: "BTCUSD", Resolution: objects.Resolution1Day,
+		From: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(bars) != 2 || bars[0].Close != 1 || bars[1].Volume != 12.7 || bars[1].Symbol != "BTCUSD" {
+		t.Fatalf("unexpected daily bars: %+v", bars)
+	}
+
+	cList, err := Candles[objects.CryptoCandle](APIClient, objects.RequestCandles{Symbol: "BTCUSD", Resolution: objects.Resolution1Day})
+	if err != nil || len(cList) != 2 || cList[0].Volume != 12 {
+		t.Fatalf("unexpected crypto candles: %+v %v", cList, err)
+	}
+
+	// History without From ends at To
+	if cList, err := Candles[objects.CryptoCandle](APIClient, objects.RequestCandles{Symbol: "BTCUSD", Resolution: objects.Resolution1Day,
+		To: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}); err != nil || len(cList) != 1 || cList[0].Date != "2021-01-01" {
+		t.Fatalf("unexpected crypto candles until to: %+v %v", cList, err)
+	}
+
+	if bars, err := History(APIClient, objects.RequestCandles{Symbol: "NONE", Resolution: objects.Resolution1Day}); err != nil || len(bars) != 0 {
+		t.Fatalf("expected empty history: %+v %v", bars, err)
+	}
+
+	// Intraday dates are exchange wall clock
+	session := time.Date(2021, 11, 29, 0, 0, 0, 0, time.UTC)
+	bars, err = History(APIClient, objects.RequestCandles{Symbol: "AAPL", Resolution: objects.Resolution1Hour, From: session, To: session})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(bars) != 2 || bars[0].Date.Location().String() != "America/New_York" || bars[0].Date.UTC() != time.Date(2021, 11, 29, 14, 30, 0, 0, time.UTC) {
+		t.Fatalf("unexpected intraday bars: %+v", bars)
+	}
+
+	bars, err = History(APIClient, objects.RequestCandles{Symbol: "AAPL", Resolution: objects.Resolution1Hour, From: session, To: session, Location: time.UTC})
+	if err != nil || len(bars) != 2 || bars[0].Date != time.Date(2021, 11, 29, 9, 30, 0, 0, time.UTC) {
+		t.Fatalf("unexpected UTC intraday bars: %+v %v", bars, err)
+	}
+
+	// Candles of stock client are loaded same as generic candles
+	sList, err := APIClient.Stock.Candles(objects.RequestStockCandleList{Symbol: "AAPL", Period: objects.StockCandlePeriod1Hour, From: &session, To: &session})
+	if err != nil || len(sList) != 2 || sList[0].Date != "2021-11-29 10:30:00" {
+		t.Fatalf("unexpected stock candles: %+v %v", sList, err)
+	}
+
+	if _, err := History(APIClient, objects.RequestCandles{Symbol: "BTCUSD", Resolution: "2day"}); err == nil {
+		t.Fatal("expected unknown resolution error")
+	}
+}
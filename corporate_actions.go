@@ -0,0 +1,527 @@
+package fmpcloud
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Corporate actions defaults
+const (
+	corporateCalendarMaxDays         = 90
+	corporateDefaultChangeTolerance  = 0.01
+	corporateDefaultSpecialMultiple  = 2
+	corporateSpecialSampleSize       = 4 // previous regular dividends compared with dividend
+	corporateFrequencySampleSize     = 8 // latest gaps between regular dividends for cadence
+	corporateDefaultProjectionMonths = 12
+	corporateSuspendedPeriods        = 2 // missed regular dividends after which dividend is not projected
+)
+
+// CorporateActionsConfig for create corporate actions service
+type CorporateActionsConfig struct {
+	Watchlist        []string
+	ChangeTolerance  float64 // relative change of regular dividend reported as cut or increase, default 0.01
+	SpecialMultiple  float64 // dividend above multiple of recent regular dividends is special, default 2
+	ProjectionMonths int     // projected dividends horizon, default 12
+}
+
+// CorporateActions - dividends and splits of calendar and watchlist histories
+type CorporateActions struct {
+	Client *APIClient
+	Config CorporateActionsConfig
+
+	now func() time.Time
+}
+
+// NewCorporateActions ...
+func NewCorporateActions(client *APIClient, cfg CorporateActionsConfig) *CorporateActions {
+	if cfg.ChangeTolerance <= 0 {
+		cfg.ChangeTolerance = corporateDefaultChangeTolerance
+	}
+
+	if cfg.SpecialMultiple <= 0 {
+		cfg.SpecialMultiple = corporateDefaultSpecialMultiple
+	}
+
+	if cfg.ProjectionMonths <= 0 {
+		cfg.ProjectionMonths = corporateDefaultProjectionMonths
+	}
+
+	return &CorporateActions{Client: client, Config: cfg, now: time.Now}
+}
+
+// History - dividends and splits of symbol sorted by ex date, dividends classified as regular or special
+func (c *CorporateActions) History(symbol string) ([]objects.CorporateAction, error) {
+	dList, err := c.Client.Stock.Dividends(symbol)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error load dividends of %s", symbol)
+	}
+
+	sList, err := c.Client.Stock.Splits(symbol)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error load splits of %s", symbol)
+	}
+
+	var list []objects.CorporateAction
+	if dList != nil {
+		for _, d := range dList.Historical {
+			list = append(list, objects.CorporateAction{
+				Symbol: symbol, Kind: objects.CorporateActionDividend, ExDate: normalizeActionDate(d.Date),
+				RecordDate: normalizeActionDate(d.RecordDate), PaymentDate: normalizeActionDate(d.PaymentDate),
+				DeclarationDate: normalizeActionDate(d.DeclarationDate), Dividend: d.Dividend, AdjDividend: d.AdjDividend,
+			})
+		}
+	}
+
+	if sList != nil {
+		for _, s := range sList.Historical {
+			list = append(list, objects.CorporateAction{
+				Symbol: symbol, Kind: objects.CorporateActionSplit, ExDate: normalizeActionDate(s.Date),
+				Numerator: s.Numerator, Denominator: s.Denominator,
+			})
+		}
+	}
+
+	return ClassifyDividends(list, c.Config.SpecialMultiple, c.Config.ChangeTolerance), nil
+}
+
+// Calendar - actions between from and to of calendar endpoints, watchlist histories
+// and projected watchlist dividends, sorted by ex date and symbol
+func (c *CorporateActions) Calendar(from, to time.Time) ([]objects.CorporateAction, error) {
+	dividends, err := loadCalendarChunks(from, to, corporateCalendarMaxDays, c.Client.CompanyValuation.DividendCalendar)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load dividend calendar")
+	}
+
+	splits, err := loadCalendarChunks(from, to, corporateCalendarMaxDays, c.Client.CompanyValuation.SplitCalendar)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load split calendar")
+	}
+
+	index := make(map[string]objects.CorporateAction)
+	add := func(a objects.CorporateAction) {
+		key := corporateActionKey(a)
+		// Watchlist histories carry special flags, calendar rows only fill missing actions
+		if _, ok := index[key]; !ok || !a.Projected {
+			index[key] = a
+		}
+	}
+
+	for _, d := range dividends {
+		add(objects.CorporateAction{
+			Symbol: d.Symbol, Kind: objects.CorporateActionDividend, ExDate: normalizeActionDate(d.Date),
+			RecordDate: normalizeActionDate(d.RecordDate), PaymentDate: normalizeActionDate(d.PaymentDate),
+			DeclarationDate: normalizeActionDate(d.DeclarationDate), Dividend: d.Dividend, AdjDividend: d.AdjDividend,
+		})
+	}
+
+	for _, s := range splits {
+		add(objects.CorporateAction{
+			Symbol: s.Symbol, Kind: objects.CorporateActionSplit, ExDate: normalizeActionDate(s.Date),
+			Numerator: s.Numerator, Denominator: s.Denominator,
+		})
+	}
+
+	fromDate, toDate := from.Format(layoutDate), to.Format(layoutDate)
+	for _, symbol := range c.Config.Watchlist {
+		history, err := c.History(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range history {
+			if a.ExDate >= fromDate && a.ExDate <= toDate {
+				add(a)
+			}
+		}
+
+		for _, a := range ProjectDividends(history, from, to) {
+			if _, ok := index[corporateActionKey(a)]; !ok {
+				add(a)
+			}
+		}
+	}
+
+	list := make([]objects.CorporateAction, 0, len(index))
+	for _, a := range index {
+		list = append(list, a)
+	}
+
+	sortCorporateActions(list)
+
+	return list, nil
+}
+
+// Projections - announced and projected dividends of watchlist from now to ProjectionMonths ahead,
+// sorted by ex date and symbol
+func (c *CorporateActions) Projections() ([]objects.CorporateAction, error) {
+	from := c.now()
+	to := from.AddDate(0, c.Config.ProjectionMonths, 0)
+	fromDate, toDate := from.Format(layoutDate), to.Format(layoutDate)
+
+	var list []objects.CorporateAction
+	for _, symbol := range c.Config.Watchlist {
+		history, err := c.History(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		announced := make(map[string]bool)
+		for _, a := range history {
+			if a.Kind == objects.CorporateActionDividend && a.ExDate >= fromDate && a.ExDate <= toDate {
+				announced[corporateActionKey(a)] = true
+				list = append(list, a)
+			}
+		}
+
+		for _, a := range ProjectDividends(history, from, to) {
+			if !announced[corporateActionKey(a)] {
+				list = append(list, a)
+			}
+		}
+	}
+
+	sortCorporateActions(list)
+
+	return list, nil
+}
+
+// Yields - trailing and forward dividend yield of watchlist at current price
+func (c *CorporateActions) Yields() ([]objects.DividendYield, error) {
+	if len(c.Config.Watchlist) == 0 {
+		return nil, nil
+	}
+
+	qList, err := c.Client.Stock.BatchQuote(c.Config.Watchlist)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load watchlist quotes")
+	}
+
+	prices := make(map[string]float64, len(qList))
+	for _, q := range qList {
+		prices[q.Symbol] = q.Price
+	}
+
+	list := make([]objects.DividendYield, 0, len(c.Config.Watchlist))
+	for _, symbol := range c.Config.Watchlist {
+		history, err := c.History(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, NewDividendYield(symbol, history, prices[symbol], c.now()))
+	}
+
+	return list, nil
+}
+
+// Alerts - dividend cuts, increases and special dividends of watchlist with ex date on or after since
+func (c *CorporateActions) Alerts(since time.Time) ([]objects.DividendAlert, error) {
+	sinceDate := since.Format(layoutDate)
+
+	var list []objects.DividendAlert
+	for _, symbol := range c.Config.Watchlist {
+		history, err := c.History(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, alert := range DividendAlerts(history, c.Config.ChangeTolerance) {
+			if alert.ExDate >= sinceDate {
+				list = append(list, alert)
+			}
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].ExDate < list[j].ExDate })
+
+	return list, nil
+}
+
+// ClassifyDividends - sort actions by ex date and flag special dividends: amount above multiple
+// of median of recent regular dividends, or off cadence with amount different from regular one
+func ClassifyDividends(list []objects.CorporateAction, multiple float64, tolerance float64) []objects.CorporateAction {
+	result := make([]objects.CorporateAction, len(list))
+	copy(result, list)
+	sortCorporateActions(result)
+
+	var amounts []float64
+	var dates []time.Time
+	for i := range result {
+		a := &result[i]
+		if a.Kind != objects.CorporateActionDividend || a.Projected {
+			continue
+		}
+
+		amount := dividendAmount(*a)
+		exDate, err := time.Parse(layoutDate, a.ExDate)
+		if err != nil || amount <= 0 {
+			continue
+		}
+
+		if n := len(amounts); n >= 2 {
+			recent := median(amounts[max(0, n-corporateSpecialSampleSize):])
+			offCadence := false
+			if gap := medianGapDays(dates); gap > 0 {
+				offCadence = exDate.Sub(dates[n-1]).Hours()/24 < gap/2 && relativeChange(amount, recent) > tolerance
+			}
+
+			if amount > multiple*recent || offCadence {
+				a.Special = true
+				continue
+			}
+		}
+
+		amounts = append(amounts, amount)
+		dates = append(dates, exDate)
+	}
+
+	return result
+}
+
+// DividendFrequency - regular dividends per year from median gap of latest regular dividends
+func DividendFrequency(list []objects.CorporateAction) int {
+	var dates []time.Time
+	for _, a := range regularDividends(list) {
+		if date, err := time.Parse(layoutDate, a.ExDate); err == nil {
+			dates = append(dates, date)
+		}
+	}
+
+	if len(dates) > corporateFrequencySampleSize+1 {
+		dates = dates[len(dates)-corporateFrequencySampleSize-1:]
+	}
+
+	gap := medianGapDays(dates)
+	switch {
+	case gap <= 0:
+		return 0
+	case gap < 45:
+		return 12
+	case gap < 135:
+		return 4
+	case gap < 270:
+		return 2
+	}
+
+	return 1
+}
+
+// ProjectDividends - expected regular dividends between from and to continuing cadence of latest dividend,
+// nothing is projected when latest dividend is older than two periods before from
+func ProjectDividends(list []objects.CorporateAction, from, to time.Time) []objects.CorporateAction {
+	frequency := DividendFrequency(list)
+	regular := regularDividends(list)
+	if frequency == 0 || len(regular) == 0 {
+		return nil
+	}
+
+	last := regular[len(regular)-1]
+	lastDate, err := time.Parse(layoutDate, last.ExDate)
+	if err != nil {
+		return nil
+	}
+
+	step := 12 / frequency
+	if lastDate.AddDate(0, corporateSuspendedPeriods*step, 0).Before(from) {
+		return nil
+	}
+
+	// Typical days from ex date to payment of latest dividends
+	var paymentDays []float64
+	for _, a := range regular[max(0, len(regular)-corporateSpecialSampleSize):] {
+		exDate, err1 := time.Parse(layoutDate, a.ExDate)
+		payDate, err2 := time.Parse(layoutDate, a.PaymentDate)
+		if err1 == nil && err2 == nil {
+			paymentDays = append(paymentDays, payDate.Sub(exDate).Hours()/24)
+		}
+	}
+
+	var projected []objects.CorporateAction
+	for n := 1; ; n++ {
+		exDate := lastDate.AddDate(0, n*step, 0)
+		if exDate.After(to) {
+			break
+		}
+
+		if exDate.Before(from) {
+			continue
+		}
+
+		a := objects.CorporateAction{
+			Symbol:      last.Symbol,
+			Kind:        objects.CorporateActionDividend,
+			ExDate:      exDate.Format(layoutDate),
+			Dividend:    last.Dividend,
+			AdjDividend: last.AdjDividend,
+			Projected:   true,
+		}
+
+		if len(paymentDays) > 0 {
+			a.PaymentDate = exDate.AddDate(0, 0, int(median(paymentDays)+0.5)).Format(layoutDate)
+		}
+
+		projected = append(projected, a)
+	}
+
+	return projected
+}
+
+// NewDividendYield - yields of classified history at price, trailing year ends at asOf
+func NewDividendYield(symbol string, list []objects.CorporateAction, price float64, asOf time.Time) objects.DividendYield {
+	y := objects.DividendYield{Symbol: symbol, Price: price, Frequency: DividendFrequency(list)}
+
+	from, to := asOf.AddDate(-1, 0, 0).Format(layoutDate), asOf.Format(layoutDate)
+	for _, a := range list {
+		if a.Kind != objects.CorporateActionDividend || a.Projected || a.ExDate <= from || a.ExDate > to {
+			continue
+		}
+
+		if a.Special {
+			y.SpecialDividend += dividendAmount(a)
+		} else {
+			y.TrailingDividend += dividendAmount(a)
+		}
+	}
+
+	if regular := regularDividends(list); len(regular) > 0 {
+		y.ForwardDividend = dividendAmount(regular[len(regular)-1]) * float64(y.Frequency)
+	}
+
+	y.TrailingYield = floatRatio(y.TrailingDividend, price)
+	y.ForwardYield = floatRatio(y.ForwardDividend, price)
+
+	return y
+}
+
+// DividendAlerts - cuts and increases of regular dividends against previous regular dividend
+// and special dividends, split adjusted amounts are compared
+func DividendAlerts(list []objects.CorporateAction, tolerance float64) []objects.DividendAlert {
+	var alerts []objects.DividendAlert
+	previous := 0.0
+	for _, a := range list {
+		if a.Kind != objects.CorporateActionDividend || a.Projected {
+			continue
+		}
+
+		amount := dividendAmount(a)
+		alert := objects.DividendAlert{
+			Symbol: a.Symbol, ExDate: a.ExDate, DeclarationDate: a.DeclarationDate,
+			Dividend: amount, PreviousDividend: previous,
+		}
+
+		if previous > 0 {
+			alert.Change = amount/previous - 1
+		}
+
+		switch {
+		case a.Special:
+			alert.Kind = objects.DividendAlertSpecial
+			alerts = append(alerts, alert)
+			continue
+		case previous > 0 && alert.Change < -tolerance:
+			alert.Kind = objects.DividendAlertCut
+			alerts = append(alerts, alert)
+		case previous > 0 && alert.Change > tolerance:
+			alert.Kind = objects.DividendAlertIncrease
+			alerts = append(alerts, alert)
+		}
+
+		previous = amount
+	}
+
+	return alerts
+}
+
+func regularDividends(list []objects.CorporateAction) []objects.CorporateAction {
+	var regular []objects.CorporateAction
+	for _, a := range list {
+		if a.Kind == objects.CorporateActionDividend && !a.Special && !a.Projected && dividendAmount(a) > 0 {
+			regular = append(regular, a)
+		}
+	}
+
+	return regular
+}
+
+// dividendAmount - split adjusted dividend, declared dividend when adjusted is missing
+func dividendAmount(a objects.CorporateAction) float64 {
+	if a.AdjDividend > 0 {
+		return a.AdjDividend
+	}
+
+	return a.Dividend
+}
+
+// normalizeActionDate - date part of date or date time, empty when missing
+func normalizeActionDate(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > len(layoutDate) {
+		s = s[:len(layoutDate)]
+	}
+
+	if _, err := time.Parse(layoutDate, s); err != nil {
+		return ""
+	}
+
+	return s
+}
+
+func corporateActionKey(a objects.CorporateAction) string {
+	return strings.ToUpper(a.Symbol) + "|" + string(a.Kind) + "|" + a.ExDate
+}
+
+func sortCorporateActions(list []objects.CorporateAction) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].ExDate != list[j].ExDate {
+			return list[i].ExDate < list[j].ExDate
+		}
+
+		return list[i].Symbol < list[j].Symbol
+	})
+}
+
+func medianGapDays(dates []time.Time) float64 {
+	if len(dates) < 2 {
+		return 0
+	}
+
+	gaps := make([]float64, 0, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		gaps = append(gaps, dates[i].Sub(dates[i-1]).Hours()/24)
+	}
+
+	return median(gaps)
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func relativeChange(value, base float64) float64 {
+	if base == 0 {
+		return 0
+	}
+
+	if value > base {
+		return value/base - 1
+	}
+
+	return 1 - value/base
+}
@@ -0,0 +1,96 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestCorporateActions(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-price-full/stock_dividend/AAA": `{"symbol":"AAA","historical":[
+			{"date":"2021-05-10","adjDividend":0.25,"dividend":0.25,"paymentDate":"2021-05-30","declarationDate":"2021-04-20"},
+			{"date":"2021-02-10","adjDividend":0.25,"dividend":0.25,"paymentDate":"2021-03-02","declarationDate":"2021-01-20"},
+			{"date":"2020-12-15","adjDividend":2,"dividend":2,"paymentDate":"2020-12-30"},
+			{"date":"2020-11-10","adjDividend":0.5,"dividend":0.5,"paymentDate":"2020-11-30"},
+			{"date":"2020-08-10","adjDividend":0.5,"dividend":0.5,"paymentDate":"2020-08-30"},
+			{"date":"2020-05-10","adjDividend":0.5,"dividend":0.5,"recordDate":"2020-05-11 00:00:00","paymentDate":"2020-05-30"},
+			{"date":"2020-02-10","adjDividend":0.5,"dividend":0.5,"paymentDate":"2020-03-01"}]}`,
+		"/v3/historical-price-full/stock_split/AAA": `{"symbol":"AAA","historical":[]}`,
+		"/v3/quote/AAA":               `[{"symbol":"AAA","price":20}]`,
+		"/v3/stock_dividend_calendar": `[{"date":"2021-06-15","symbol":"BBB","dividend":0.1,"adjDividend":0.1,"recordDate":"","paymentDate":"2021-07-01"}]`,
+		"/v3/stock_split_calendar":    `[{"date":"2021-07-01","symbol":"CCC","numerator":2,"denominator":1}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	service := NewCorporateActions(APIClient, CorporateActionsConfig{Watchlist: []string{"AAA"}})
+	service.now = func() time.Time { return time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	history, err := service.History("AAA")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(history) != 7 || !history[4].Special || history[5].Special || history[1].RecordDate != "2020-05-11" {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	if frequency := DividendFrequency(history); frequency != 4 {
+		t.Fatalf("expected quarterly dividends, got %d", frequency)
+	}
+
+	yieldList, err := service.Yields()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	y := yieldList[0]
+	if len(yieldList) != 1 || math.Abs(y.TrailingYield-0.075) > 1e-9 || math.Abs(y.ForwardYield-0.05) > 1e-9 || y.SpecialDividend != 2 {
+		t.Fatalf("unexpected yields: %+v", yieldList)
+	}
+
+	alertList, err := service.Alerts(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(alertList) != 2 || alertList[0].Kind != objects.DividendAlertSpecial ||
+		alertList[1].Kind != objects.DividendAlertCut || alertList[1].Change != -0.5 {
+		t.Fatalf("unexpected alerts: %+v", alertList)
+	}
+
+	calendar, err := service.Calendar(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(calendar) != 4 || calendar[0].Symbol != "BBB" || calendar[0].RecordDate != "" || calendar[1].Kind != objects.CorporateActionSplit {
+		t.Fatalf("unexpected calendar: %+v", calendar)
+	}
+
+	projected := calendar[2]
+	if !projected.Projected || projected.ExDate != "2021-08-10" || projected.PaymentDate != "2021-08-30" || projected.Dividend != 0.25 ||
+		calendar[3].ExDate != "2021-11-10" {
+		t.Fatalf("unexpected projections: %+v", calendar[2:])
+	}
+
+	// Horizon of ProjectionMonths from now
+	service.Config.ProjectionMonths = 6
+	projections, err := service.Projections()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(projections) != 2 || projections[0].ExDate != "2021-08-10" || projections[1].ExDate != "2021-11-10" || !projections[1].Projected {
+		t.Fatalf("unexpected projections: %+v", projections)
+	}
+
+	// Two missed quarterly dividends, dividend presumably suspended
+	if stale := ProjectDividends(history, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)); len(stale) != 0 {
+		t.Fatalf("expected no projections after suspended dividend, got %+v", stale)
+	}
+}
@@ -0,0 +1,195 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// COT analytics defaults
+const (
+	cotDefaultLookback         = 156 // weekly reports, 3 years
+	cotDefaultExtremeThreshold = 10
+)
+
+// COTConfig for build positioning series
+type COTConfig struct {
+	Symbol           string
+	PriceSymbol      string  // futures or commodity symbol for Stock.DailySpecificPeriod, empty - no prices
+	Lookback         int     // reports in COT index range, default 156
+	ExtremeThreshold float64 // COT index distance from 0 or 100 flagged as extreme, default 10
+}
+
+// LoadCOTSeries - fetch reports of contract and optional prices
+func LoadCOTSeries(client *APIClient, cfg COTConfig) (*objects.COTSeries, error) {
+	if len(cfg.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	reportList, err := client.AlternativeData.COTReportListBySymbol(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load COT report")
+	}
+
+	series := BuildCOTSeries(reportList, cfg)
+	if len(cfg.PriceSymbol) == 0 || len(series.PointList) == 0 {
+		return series, nil
+	}
+
+	from, err := parseBarDate(series.PointList[0].Date)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parseBarDate(series.PointList[len(series.PointList)-1].Date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report date may fall on holiday, take a week of prices before it
+	cList, err := client.Stock.DailySpecificPeriod(cfg.PriceSymbol, from.AddDate(0, 0, -7), to)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load prices")
+	}
+
+	var barList []objects.Bar
+	if cList != nil {
+		for _, c := range cList.Historical {
+			date, err := parseBarDate(c.Date)
+			if err != nil {
+				return nil, err
+			}
+
+			barList = append(barList, objects.Bar{Symbol: cfg.PriceSymbol, Date: date, Close: c.Close})
+		}
+	}
+
+	AlignCOTPrices(series, barList)
+	series.PriceSymbol = cfg.PriceSymbol
+
+	return series, nil
+}
+
+// BuildCOTSeries - net positions, week over week changes, COT index and extremes of one contract
+func BuildCOTSeries(reportList []objects.COTReport, cfg COTConfig) *objects.COTSeries {
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = cotDefaultLookback
+	}
+
+	if cfg.ExtremeThreshold <= 0 {
+		cfg.ExtremeThreshold = cotDefaultExtremeThreshold
+	}
+
+	reports := make([]objects.COTReport, len(reportList))
+	copy(reports, reportList)
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Date < reports[j].Date })
+
+	series := &objects.COTSeries{Symbol: cfg.Symbol, PointList: make([]objects.COTPoint, 0, len(reports))}
+	for i, r := range reports {
+		if len(series.Symbol) == 0 {
+			series.Symbol = r.Symbol
+		}
+
+		series.ShortName = r.ShortName
+		series.ContractUnits = r.ContractUnits
+
+		p := objects.COTPoint{
+			Date:               cotDate(r.Date),
+			OpenInterest:       r.OpenInterestAll,
+			CommercialLong:     r.CommPositionsLongAll,
+			CommercialShort:    r.CommPositionsShortAll,
+			CommercialNet:      r.CommPositionsLongAll - r.CommPositionsShortAll,
+			NonCommercialLong:  r.NoncommPositionsLongAll,
+			NonCommercialShort: r.NoncommPositionsShortAll,
+			NonCommercialNet:   r.NoncommPositionsLongAll - r.NoncommPositionsShortAll,
+			NonReportableNet:   r.NonreptPositionsLongAll - r.NonreptPositionsShortAll,
+		}
+
+		if i > 0 {
+			previous := series.PointList[i-1]
+			p.OpenInterestChange = p.OpenInterest - previous.OpenInterest
+			p.CommercialNetChange = p.CommercialNet - previous.CommercialNet
+			p.NonCommercialNetChange = p.NonCommercialNet - previous.NonCommercialNet
+		}
+
+		series.PointList = append(series.PointList, p)
+
+		start := len(series.PointList) - cfg.Lookback
+		if start < 0 {
+			start = 0
+		}
+
+		window := series.PointList[start:]
+		last := &series.PointList[len(series.PointList)-1]
+		last.CommercialIndex = cotIndex(window, func(p objects.COTPoint) float64 { return p.CommercialNet })
+		last.NonCommercialIndex = cotIndex(window, func(p objects.COTPoint) float64 { return p.NonCommercialNet })
+		last.CommercialExtreme = cotExtreme(last.CommercialIndex, cfg.ExtremeThreshold)
+		last.NonCommercialExtreme = cotExtreme(last.NonCommercialIndex, cfg.ExtremeThreshold)
+	}
+
+	return series
+}
+
+// AlignCOTPrices - close on or before each report date and change since previous report
+func AlignCOTPrices(series *objects.COTSeries, barList []objects.Bar) {
+	bars := make([]objects.Bar, len(barList))
+	copy(bars, barList)
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date.Before(bars[j].Date) })
+
+	for i := range series.PointList {
+		p := &series.PointList[i]
+		p.Price, p.PriceChange = 0, 0
+
+		date, err := parseBarDate(p.Date)
+		if err != nil {
+			continue
+		}
+
+		n := sort.Search(len(bars), func(k int) bool { return bars[k].Date.After(date) })
+		if n == 0 {
+			continue
+		}
+
+		p.Price = bars[n-1].Close
+		if i > 0 && series.PointList[i-1].Price != 0 {
+			p.PriceChange = p.Price/series.PointList[i-1].Price - 1
+		}
+	}
+}
+
+// cotIndex - (net - min) / (max - min) * 100 over window, 50 for flat range
+func cotIndex(window []objects.COTPoint, net func(objects.COTPoint) float64) float64 {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, p := range window {
+		low = math.Min(low, net(p))
+		high = math.Max(high, net(p))
+	}
+
+	if high == low {
+		return 50
+	}
+
+	return (net(window[len(window)-1]) - low) / (high - low) * 100
+}
+
+func cotExtreme(index, threshold float64) objects.COTExtreme {
+	switch {
+	case index >= 100-threshold:
+		return objects.COTExtremeLong
+	case index <= threshold:
+		return objects.COTExtremeShort
+	}
+
+	return objects.COTExtremeNone
+}
+
+// cotDate - report date without time
+func cotDate(s string) string {
+	if len(s) > len(layoutDate) {
+		return s[:len(layoutDate)]
+	}
+
+	return s
+}
@@ -0,0 +1,46 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestCOTSeries(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v4/commitment_of_traders_report/GC": `[
+			{"symbol":"GC","date":"2021-01-19 00:00:00","short_name":"Gold","open_interest_all":110,"comm_positions_long_all":30,"comm_positions_short_all":60,"noncomm_positions_long_all":60,"noncomm_positions_short_all":20},
+			{"symbol":"GC","date":"2021-01-05 00:00:00","short_name":"Gold","open_interest_all":100,"comm_positions_long_all":50,"comm_positions_short_all":40,"noncomm_positions_long_all":30,"noncomm_positions_short_all":40},
+			{"symbol":"GC","date":"2021-01-12 00:00:00","short_name":"Gold","open_interest_all":105,"comm_positions_long_all":40,"comm_positions_short_all":50,"noncomm_positions_long_all":45,"noncomm_positions_short_all":30}]`,
+		"/v3/historical-price-full/GCUSD": `{"symbol":"GCUSD","historical":[
+			{"date":"2021-01-19","close":1840},
+			{"date":"2021-01-11","close":1850},
+			{"date":"2021-01-04","close":1900}]}`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	series, err := LoadCOTSeries(APIClient, COTConfig{Symbol: "GC", PriceSymbol: "GCUSD", Lookback: 2})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(series.PointList) != 3 || series.ShortName != "Gold" {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+
+	last := series.PointList[2]
+	if last.Date != "2021-01-19" || last.CommercialNet != -30 || last.CommercialNetChange != -20 || last.NonCommercialNet != 40 || last.OpenInterestChange != 5 {
+		t.Fatalf("unexpected positions: %+v", last)
+	}
+
+	if last.CommercialIndex != 0 || last.CommercialExtreme != objects.COTExtremeShort || last.NonCommercialIndex != 100 || last.NonCommercialExtreme != objects.COTExtremeLong {
+		t.Fatalf("unexpected index: %+v", last)
+	}
+
+	if series.PointList[0].CommercialIndex != 50 || series.PointList[1].Price != 1850 || math.Abs(last.PriceChange-(1840.0/1850-1)) > 1e-12 {
+		t.Fatalf("unexpected prices: %+v", series.PointList)
+	}
+}
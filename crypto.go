@@ -12,7 +12,6 @@ import (
 const (
 	urlAPICryptoSymbols = "/v3/symbol/available-cryptocurrencies"
 	urlAPICryptoQuotes  = "/v3/quotes/crypto"
-	urlAPICryptoCandles = "/v3/historical-chart/%s/%s"
 	urlAPICryptoDaily   = "/v3/historical-price-full/%s"
 )
 
@@ -51,28 +50,10 @@ func (c *Crypto) Quotes() (qList []objects.CryptoQuote, err error) {
 	return qList, nil
 }
 
-// Candles - Historical candles
+// Candles - Historical candles newest first, daily period is loaded from daily candles,
+// same as Candles[objects.CryptoCandle]
 func (c *Crypto) Candles(req objects.RequestCryptoCandleList) (cList []objects.CryptoCandle, err error) {
-	reqParam := make(map[string]string)
-	if req.From != nil {
-		reqParam["from"] = req.From.Format("2006-01-02")
-	}
-
-	if req.To != nil {
-		reqParam["to"] = req.To.Format("2006-01-02")
-	}
-
-	data, err := c.Client.Get(fmt.Sprintf(urlAPICryptoCandles, req.Period, req.Symbol), reqParam)
-	if err != nil {
-		return nil, err
-	}
-
-	err = jsoniter.Unmarshal(data.Body(), &cList)
-	if err != nil {
-		return nil, err
-	}
-
-	return cList, nil
+	return loadTypedCandles[objects.CryptoCandle](c.Client, candleRequest(req.Symbol, objects.Resolution(req.Period), req.From, req.To))
 }
 
 // DailyLine - Daily line
@@ -0,0 +1,315 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Crypto universe defaults
+const (
+	cryptoUniverseTopN        = 20
+	cryptoUniverseConcurrency = 4
+	cryptoUniverseCurrency    = "USD"
+)
+
+// CryptoUniverseConfig for load crypto universe
+type CryptoUniverseConfig struct {
+	TopN         int                  // default 20
+	RankBy       objects.CryptoRankBy // default market cap
+	Currency     string               // quote currency of symbols, default USD
+	MinVolume    float64
+	Exclude      []string // symbols left out of ranking, stablecoins
+	Concurrency  int      // parallel history requests, default 4
+	PartialDates bool     // history keeps dates missing bars of some symbols instead of common dates only
+}
+
+// CryptoUniverse - top cryptos of market snapshot
+type CryptoUniverse struct {
+	Client *APIClient
+	Config CryptoUniverseConfig
+	Assets []objects.CryptoAsset
+}
+
+// LoadCryptoUniverse - rank quotes of currency and keep top N
+func LoadCryptoUniverse(client *APIClient, cfg CryptoUniverseConfig) (*CryptoUniverse, error) {
+	if cfg.TopN <= 0 {
+		cfg.TopN = cryptoUniverseTopN
+	}
+
+	if len(cfg.RankBy) == 0 {
+		cfg.RankBy = objects.CryptoRankByMarketCap
+	}
+
+	if len(cfg.Currency) == 0 {
+		cfg.Currency = cryptoUniverseCurrency
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = cryptoUniverseConcurrency
+	}
+
+	qList, err := client.Crypto.Quotes()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load crypto quotes")
+	}
+
+	return &CryptoUniverse{Client: client, Config: cfg, Assets: RankCryptoAssets(qList, cfg)}, nil
+}
+
+// RankCryptoAssets - top N quotes of config currency with dominance in market cap of all quotes.
+// Quote currency is the last word of name, symbol suffix for quotes without name
+func RankCryptoAssets(qList []objects.CryptoQuote, cfg CryptoUniverseConfig) []objects.CryptoAsset {
+	exclude := make(map[string]bool, len(cfg.Exclude))
+	for _, symbol := range cfg.Exclude {
+		exclude[strings.ToUpper(symbol)] = true
+	}
+
+	currency := strings.ToUpper(cfg.Currency)
+	total := 0.0
+	var list []objects.CryptoAsset
+	for _, q := range qList {
+		if len(currency) > 0 && !cryptoQuotedIn(q, currency) {
+			continue
+		}
+
+		total += q.MarketCap
+		if exclude[q.Symbol] || q.Volume < cfg.MinVolume || q.Price <= 0 {
+			continue
+		}
+
+		list = append(list, objects.CryptoAsset{
+			Symbol:            q.Symbol,
+			Name:              q.Name,
+			Price:             q.Price,
+			MarketCap:         q.MarketCap,
+			Volume:            q.Volume,
+			DollarVolume:      q.Price * q.Volume,
+			ChangesPercentage: q.ChangesPercentage,
+		})
+	}
+
+	key := func(a objects.CryptoAsset) float64 {
+		switch cfg.RankBy {
+		case objects.CryptoRankByVolume:
+			return a.Volume
+		case objects.CryptoRankByDollarVolume:
+			return a.DollarVolume
+		}
+
+		return a.MarketCap
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if key(list[i]) != key(list[j]) {
+			return key(list[i]) > key(list[j])
+		}
+
+		return list[i].Symbol < list[j].Symbol
+	})
+
+	if cfg.TopN > 0 && len(list) > cfg.TopN {
+		list = list[:cfg.TopN]
+	}
+
+	for i := range list {
+		list[i].Rank = i + 1
+		list[i].Dominance = floatRatio(list[i].MarketCap, total)
+	}
+
+	return list
+}
+
+// Symbols - symbols of universe by rank
+func (u *CryptoUniverse) Symbols() []string {
+	symbols := make([]string, 0, len(u.Assets))
+	for _, a := range u.Assets {
+		symbols = append(symbols, a.Symbol)
+	}
+
+	return symbols
+}
+
+// History - bars of universe loaded concurrently and aligned on dates common to all symbols,
+// or on all dates with PartialDates
+func (u *CryptoUniverse) History(period objects.CryptoCandlePeriod, from, to time.Time) (*objects.CryptoUniverseHistory, error) {
+	symbols := u.Symbols()
+	barLists := make([][]objects.Bar, len(symbols))
+	errList := make([]error, len(symbols))
+
+	concurrency := u.Config.Concurrency
+	if concurrency <= 0 {
+		concurrency = cryptoUniverseConcurrency
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(symbols); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				barLists[i], errList[i] = LoadCryptoBars(u.Client, symbols[i], period, from, to)
+			}
+		}()
+	}
+
+	for i := range symbols {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errList {
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load %s bars", symbols[i])
+		}
+	}
+
+	return AlignCryptoBars(period, symbols, barLists, u.Config.PartialDates), nil
+}
+
+// Dominance - market cap share of universe symbols on history dates, supply is taken
+// from snapshot market cap / price
+func (u *CryptoUniverse) Dominance(history *objects.CryptoUniverseHistory) objects.CryptoDominance {
+	supply := make(map[string]float64, len(u.Assets))
+	for _, a := range u.Assets {
+		supply[a.Symbol] = floatRatio(a.MarketCap, a.Price)
+	}
+
+	d := objects.CryptoDominance{Symbols: history.Symbols, Dates: history.Dates}
+	for i := range history.Dates {
+		caps := make([]float64, len(history.Symbols))
+		total := 0.0
+		for j, symbol := range history.Symbols {
+			caps[j] = history.Bars[symbol][i].Close * supply[symbol]
+			total += caps[j]
+		}
+
+		for j := range caps {
+			caps[j] = floatRatio(caps[j], total)
+		}
+
+		d.Shares = append(d.Shares, caps)
+	}
+
+	return d
+}
+
+// LoadCryptoBars - bars of any crypto period oldest first
+func LoadCryptoBars(client *APIClient, symbol string, period objects.CryptoCandlePeriod, from, to time.Time) ([]objects.Bar, error) {
+	return History(client, objects.RequestCandles{Symbol: symbol, Resolution: objects.Resolution(period), From: from, To: to, Location: time.UTC})
+}
+
+// AlignCryptoBars - keep bars of dates present for every symbol and count dropped dates,
+// partial keeps every date with zero bars of missing symbols
+func AlignCryptoBars(period objects.CryptoCandlePeriod, symbols []string, barLists [][]objects.Bar, partial bool) *objects.CryptoUniverseHistory {
+	history := &objects.CryptoUniverseHistory{Period: period, Symbols: symbols, Bars: make(map[string][]objects.Bar, len(symbols))}
+
+	count := make(map[time.Time]int)
+	byDate := make([]map[time.Time]objects.Bar, len(barLists))
+	for i, bars := range barLists {
+		byDate[i] = make(map[time.Time]objects.Bar, len(bars))
+		for _, bar := range bars {
+			if _, ok := byDate[i][bar.Date]; !ok {
+				count[bar.Date]++
+			}
+
+			byDate[i][bar.Date] = bar
+		}
+	}
+
+	for date, n := range count {
+		if n < len(barLists) && !partial {
+			history.DroppedDates++
+			continue
+		}
+
+		history.Dates = append(history.Dates, date)
+	}
+
+	sort.Slice(history.Dates, func(i, j int) bool { return history.Dates[i].Before(history.Dates[j]) })
+
+	for i, symbol := range symbols {
+		bars := make([]objects.Bar, 0, len(history.Dates))
+		for _, date := range history.Dates {
+			bar, ok := byDate[i][date]
+			if !ok {
+				bar = objects.Bar{Date: date}
+			}
+
+			bars = append(bars, bar)
+		}
+
+		history.Bars[symbol] = bars
+	}
+
+	return history
+}
+
+// CryptoCorrelationMatrix - Pearson correlation of log returns of aligned history
+func CryptoCorrelationMatrix(history *objects.CryptoUniverseHistory) objects.CryptoCorrelation {
+	returns := make([][]float64, len(history.Symbols))
+	for i, symbol := range history.Symbols {
+		bars := history.Bars[symbol]
+		for k := 1; k < len(bars); k++ {
+			r := 0.0
+			if bars[k-1].Close > 0 && bars[k].Close > 0 {
+				r = math.Log(bars[k].Close / bars[k-1].Close)
+			}
+
+			returns[i] = append(returns[i], r)
+		}
+	}
+
+	c := objects.CryptoCorrelation{Symbols: history.Symbols, Matrix: make([][]float64, len(history.Symbols))}
+	if len(history.Dates) > 1 {
+		c.Observations = len(history.Dates) - 1
+	}
+
+	for i := range returns {
+		c.Matrix[i] = make([]float64, len(returns))
+		for j := range returns {
+			if i == j {
+				c.Matrix[i][j] = 1
+				continue
+			}
+
+			c.Matrix[i][j] = pearson(returns[i], returns[j])
+		}
+	}
+
+	return c
+}
+
+// pearson - correlation of equal length series, 0 for constant series
+func pearson(a, b []float64) float64 {
+	if len(a) < 2 || len(a) != len(b) {
+		return 0
+	}
+
+	meanA, meanB := floatAverage(a), floatAverage(b)
+	var cov, varA, varB float64
+	for i := range a {
+		cov += (a[i] - meanA) * (b[i] - meanB)
+		varA += (a[i] - meanA) * (a[i] - meanA)
+		varB += (b[i] - meanB) * (b[i] - meanB)
+	}
+
+	return floatRatio(cov, math.Sqrt(varA*varB))
+}
+
+// cryptoQuotedIn - quote currency matches exactly, BTCBUSD named Bitcoin BUSD is not quoted in USD
+func cryptoQuotedIn(q objects.CryptoQuote, currency string) bool {
+	if words := strings.Fields(q.Name); len(words) > 0 {
+		return strings.ToUpper(words[len(words)-1]) == currency
+	}
+
+	return len(q.Symbol) > len(currency) && strings.HasSuffix(q.Symbol, currency)
+}
@@ -0,0 +1,84 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestCryptoUniverse(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/quotes/crypto": `[{"symbol":"BTCUSD","name":"Bitcoin USD","price":100,"marketCap":600,"volume":10},
+			{"symbol":"ETHUSD","name":"Ethereum USD","price":10,"marketCap":300,"volume":50},
+			{"symbol":"USDTUSD","name":"Tether USD","price":1,"marketCap":80,"volume":900},
+			{"symbol":"DOGEUSD","name":"Dogecoin USD","price":0.1,"marketCap":20,"volume":1},
+			{"symbol":"ETHBTC","name":"Ethereum BTC","price":0.1,"marketCap":300,"volume":5},
+			{"symbol":"BTCBUSD","name":"Bitcoin BUSD","price":100,"marketCap":600,"volume":10}]`,
+		"/v3/historical-price-full/BTCUSD": `{"symbol":"BTCUSD","historical":[{"date":"2021-01-04","close":121},{"date":"2021-01-03","close":110},
+			{"date":"2021-01-02","close":100},{"date":"2021-01-01","close":90}]}`,
+		"/v3/historical-price-full/ETHUSD": `{"symbol":"ETHUSD","historical":[{"date":"2021-01-04","close":12.6},{"date":"2021-01-03","close":10.5},
+			{"date":"2021-01-02","close":10},{"date":"2021-01-01","close":9},{"date":"2020-12-31","close":8}]}`,
+		"/v3/historical-chart/4hour/BTCUSD": `[{"date":"2021-01-01 04:00:00","close":2,"volume":7},{"date":"2021-01-01 00:00:00","close":1,"volume":5}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	universe, err := LoadCryptoUniverse(APIClient, CryptoUniverseConfig{TopN: 2, Exclude: []string{"USDTUSD"}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if symbols := universe.Symbols(); len(symbols) != 2 || symbols[0] != "BTCUSD" || universe.Assets[1].Rank != 2 || universe.Assets[0].Dominance != 0.6 {
+		t.Fatalf("unexpected universe: %+v", universe.Assets)
+	}
+
+	byVolume := RankCryptoAssets([]objects.CryptoQuote{{Symbol: "AUSD", Price: 1, Volume: 10}, {Symbol: "BUSD", Price: 5, Volume: 5}},
+		CryptoUniverseConfig{RankBy: objects.CryptoRankByDollarVolume, Currency: "USD"})
+	if byVolume[0].Symbol != "BUSD" {
+		t.Fatalf("unexpected dollar volume ranking: %+v", byVolume)
+	}
+
+	history, err := universe.History(objects.CryptoCandlePeriod1Day, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(history.Dates) != 4 || history.DroppedDates != 1 || history.Bars["BTCUSD"][0].Close != 90 || history.Bars["ETHUSD"][3].Close != 12.6 {
+		t.Fatalf("unexpected aligned history: %+v", history)
+	}
+
+	// Universe built without config loads with default concurrency
+	built := &CryptoUniverse{Client: APIClient, Assets: universe.Assets}
+	if history, err := built.History(objects.CryptoCandlePeriod1Day, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)); err != nil || len(history.Dates) != 4 {
+		t.Fatalf("unexpected history of built universe: %+v %v", history, err)
+	}
+
+	// Partial dates keep ETH bar without BTC bar
+	partial := &CryptoUniverse{Client: APIClient, Config: CryptoUniverseConfig{PartialDates: true}, Assets: universe.Assets}
+	if history, err := partial.History(objects.CryptoCandlePeriod1Day, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)); err != nil || len(history.Dates) != 5 || history.DroppedDates != 0 ||
+		history.Bars["BTCUSD"][0].Close != 0 || history.Bars["ETHUSD"][0].Close != 8 {
+		t.Fatalf("unexpected partial history: %+v %v", history, err)
+	}
+
+	correlation := CryptoCorrelationMatrix(history)
+	if correlation.Observations != 3 || correlation.Matrix[0][0] != 1 || math.Abs(correlation.Matrix[0][1]+0.0878226624) > 1e-9 ||
+		correlation.Matrix[0][1] != correlation.Matrix[1][0] {
+		t.Fatalf("unexpected correlation: %+v", correlation)
+	}
+
+	dominance := universe.Dominance(history)
+	// BTC supply 6, ETH supply 30 on 2021-01-01: 540 / (540 + 270)
+	if len(dominance.Shares) != 4 || math.Abs(dominance.Shares[0][0]-540.0/810) > 1e-9 {
+		t.Fatalf("unexpected dominance: %+v", dominance)
+	}
+
+	bars, err := LoadCryptoBars(APIClient, "BTCUSD", objects.CryptoCandlePeriod4Hour, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(bars) != 2 || bars[0].Close != 1 || bars[1].Volume != 7 {
+		t.Fatalf("unexpected intraday bars: %+v %v", bars, err)
+	}
+}
@@ -0,0 +1,537 @@
+package fmpcloud
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// currencyRateLookbackDays - days before first date loaded for rate of non-trading days
+const currencyRateLookbackDays = 10
+
+// CurrencyConverterConfig for create currency converter
+type CurrencyConverterConfig struct {
+	Crypto bool          // add crypto quotes to currency graph, BTCUSD is BTC in USD
+	MaxAge time.Duration // quotes older than MaxAge are reloaded, 0 loads once
+}
+
+// CurrencyConverter - cross rates derived from graph of forex and crypto pairs
+type CurrencyConverter struct {
+	Client *APIClient
+	Config CurrencyConverterConfig
+
+	mu        sync.RWMutex
+	now       func() time.Time
+	refreshed time.Time
+	graph     map[string]map[string]currencyEdge
+}
+
+// currencyEdge - rate of one currency in its neighbour by pair symbol
+type currencyEdge struct {
+	symbol string
+	invert bool // pair is quoted as neighbour/currency
+	crypto bool
+	bid    float64
+	ask    float64
+	date   string
+}
+
+// NewCurrencyConverter ...
+func NewCurrencyConverter(client *APIClient, cfg CurrencyConverterConfig) *CurrencyConverter {
+	return &CurrencyConverter{
+		Client: client,
+		Config: cfg,
+		now:    time.Now,
+		graph:  make(map[string]map[string]currencyEdge),
+	}
+}
+
+// Refresh - rebuild currency graph from bid/ask pairs, forex quotes and crypto quotes
+func (c *CurrencyConverter) Refresh() error {
+	bList, err := c.Client.Forex.ListSymbolsAndQuotes()
+	if err != nil {
+		return errors.Wrap(err, "Error load forex bid and ask")
+	}
+
+	qList, err := c.Client.Forex.Quotes()
+	if err != nil {
+		return errors.Wrap(err, "Error load forex quotes")
+	}
+
+	graph := make(map[string]map[string]currencyEdge)
+	for _, b := range bList {
+		base, quote, ok := strings.Cut(b.Ticker, "/")
+		bid, errBid := strconv.ParseFloat(b.Bid, 64)
+		ask, errAsk := strconv.ParseFloat(b.Ask, 64)
+		if !ok || errBid != nil || errAsk != nil || bid <= 0 || ask < bid {
+			continue
+		}
+
+		addCurrencyPair(graph, base, quote, currencyEdge{symbol: base + quote, bid: bid, ask: ask, date: b.Date})
+	}
+
+	// Quotes without bid and ask fill pairs missing from bid/ask list
+	for _, q := range qList {
+		base, quote, ok := strings.Cut(q.Name, "/")
+		if !ok && len(q.Symbol) == 6 {
+			base, quote, ok = q.Symbol[:3], q.Symbol[3:], true
+		}
+
+		if !ok || q.Price <= 0 {
+			continue
+		}
+
+		if _, exists := graph[strings.ToUpper(base)][strings.ToUpper(quote)]; !exists {
+			addCurrencyPair(graph, base, quote, currencyEdge{symbol: q.Symbol, bid: q.Price, ask: q.Price, date: currencyQuoteDate(q.Timestamp)})
+		}
+	}
+
+	if c.Config.Crypto {
+		cList, err := c.Client.Crypto.Quotes()
+		if err != nil {
+			return errors.Wrap(err, "Error load crypto quotes")
+		}
+
+		for _, q := range cList {
+			quote := cryptoQuoteCurrency(graph, q.Symbol)
+			if len(quote) == 0 || q.Price <= 0 {
+				continue
+			}
+
+			base := strings.TrimSuffix(q.Symbol, quote)
+			if _, exists := graph[base][quote]; !exists {
+				addCurrencyPair(graph, base, quote, currencyEdge{symbol: q.Symbol, crypto: true, bid: q.Price, ask: q.Price, date: currencyQuoteDate(q.Timestamp)})
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.graph = graph
+	c.refreshed = c.now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Currencies - currencies of graph sorted by code
+func (c *CurrencyConverter) Currencies() ([]string, error) {
+	if err := c.ensure(); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	list := make([]string, 0, len(c.graph))
+	for currency := range c.graph {
+		list = append(list, currency)
+	}
+
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// Rate - current rate of from in to through the shortest path of pairs, USD preferred
+func (c *CurrencyConverter) Rate(from, to string) (objects.CurrencyRate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return objects.CurrencyRate{From: from, To: to, Bid: 1, Ask: 1, Mid: 1, Path: []string{from}}, nil
+	}
+
+	path, edges, err := c.path(from, to)
+	if err != nil {
+		return objects.CurrencyRate{}, err
+	}
+
+	rate := objects.CurrencyRate{From: from, To: to, Bid: 1, Ask: 1, Path: path}
+	for _, e := range edges {
+		rate.Bid *= e.bid
+		rate.Ask *= e.ask
+		if len(rate.Date) == 0 || (len(e.date) > 0 && e.date < rate.Date) {
+			rate.Date = e.date
+		}
+	}
+
+	rate.Mid = (rate.Bid + rate.Ask) / 2
+
+	return rate, nil
+}
+
+// Convert - amount of from sold for to at bid
+func (c *CurrencyConverter) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * rate.Bid, nil
+}
+
+// History - daily close rate of from in to, legs of path are joined by date
+// and a leg without quote on the date uses its previous close
+func (c *CurrencyConverter) History(from, to string, start, end time.Time) (*objects.CurrencyRateHistory, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	path, edges, err := c.path(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]map[string]float64, 0, len(edges))
+	dates := make(map[string]bool)
+	for _, e := range edges {
+		closes, err := c.loadCloses(e, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		for date := range closes {
+			dates[date] = true
+		}
+
+		legs = append(legs, closes)
+	}
+
+	dateList := make([]string, 0, len(dates))
+	for date := range dates {
+		dateList = append(dateList, date)
+	}
+
+	sort.Strings(dateList)
+
+	history := &objects.CurrencyRateHistory{From: from, To: to, Path: path}
+	last := make([]float64, len(legs))
+	for _, date := range dateList {
+		rate := 1.0
+		for i, closes := range legs {
+			if value, ok := closes[date]; ok {
+				last[i] = value
+			}
+
+			rate *= last[i]
+		}
+
+		if rate > 0 {
+			history.Historical = append(history.Historical, objects.CurrencyRatePoint{Date: date, Rate: rate})
+		}
+	}
+
+	return history, nil
+}
+
+// FundamentalsInCurrency - fundamentals with monetary values converted, balance sheet at close rate
+// of period date, income and cash flow at average rate of period. Empty currency converts into currency of company profile. Ratios and share counts are kept,
+// as-reported values stay in reported currency.
+func (c *CurrencyConverter) FundamentalsInCurrency(req objects.RequestFundamentals, currency string) ([]objects.Fundamentals, error) {
+	if len(currency) == 0 {
+		profileList, err := c.Client.Stock.CompanyProfile(req.Symbol)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error load company profile")
+		}
+
+		if len(profileList) == 0 || len(profileList[0].Currency) == 0 {
+			return nil, errors.Errorf("Error unknown currency of %s", req.Symbol)
+		}
+
+		currency = profileList[0].Currency
+	}
+
+	fList, err := c.Client.CompanyValuation.Fundamentals(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.NormalizeFundamentals(fList, currency)
+}
+
+// NormalizeFundamentals - convert fundamentals of any reported currency into currency,
+// balance sheet at close rate of period date, income and cash flow at average rate of period
+func (c *CurrencyConverter) NormalizeFundamentals(fList []objects.Fundamentals, currency string) ([]objects.Fundamentals, error) {
+	currency = strings.ToUpper(currency)
+
+	// Date range of periods by reported currency
+	ranges := make(map[string][2]time.Time)
+	for _, f := range fList {
+		reported := strings.ToUpper(f.ReportedCurrency)
+		date, err := time.Parse(layoutDate, f.Date)
+		if len(reported) == 0 || reported == currency || err != nil {
+			continue
+		}
+
+		r, ok := ranges[reported]
+		if start := fundamentalsPeriodStart(f.Period, date); !ok || start.Before(r[0]) {
+			r[0] = start
+		}
+
+		if !ok || date.After(r[1]) {
+			r[1] = date
+		}
+
+		ranges[reported] = r
+	}
+
+	histories := make(map[string]*objects.CurrencyRateHistory, len(ranges))
+	for reported, r := range ranges {
+		history, err := c.History(reported, currency, r[0].AddDate(0, 0, -currencyRateLookbackDays), r[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load %s rate history", reported)
+		}
+
+		histories[reported] = history
+	}
+
+	list := make([]objects.Fundamentals, 0, len(fList))
+	for _, f := range fList {
+		history, ok := histories[strings.ToUpper(f.ReportedCurrency)]
+		if !ok {
+			list = append(list, f)
+			continue
+		}
+
+		rate, ok := currencyRateOn(history, f.Date)
+		if !ok {
+			return nil, errors.Errorf("Error no %s%s rate on %s", history.From, history.To, f.Date)
+		}
+
+		// Flows at average rate of period, close rate when history has no close in period
+		average := rate
+		if date, err := time.Parse(layoutDate, f.Date); err == nil {
+			if value, ok := currencyRateAverage(history, fundamentalsPeriodStart(f.Period, date).Format(layoutDate), f.Date); ok {
+				average = value
+			}
+		}
+
+		f.ReportedCurrency = currency
+		if f.Income != nil {
+			income := convertStatement(*f.Income, average, currency)
+			f.Income = &income
+		}
+
+		if f.BalanceSheet != nil {
+			balance := convertStatement(*f.BalanceSheet, rate, currency)
+			f.BalanceSheet = &balance
+		}
+
+		if f.CashFlow != nil {
+			cashFlow := convertStatement(*f.CashFlow, average, currency)
+			f.CashFlow = &cashFlow
+		}
+
+		list = append(list, f)
+	}
+
+	return list, nil
+}
+
+// ensure - load graph once or when older than MaxAge
+func (c *CurrencyConverter) ensure() error {
+	c.mu.RLock()
+	refreshed := c.refreshed
+	c.mu.RUnlock()
+
+	if !refreshed.IsZero() && (c.Config.MaxAge == 0 || c.now().Sub(refreshed) < c.Config.MaxAge) {
+		return nil
+	}
+
+	return c.Refresh()
+}
+
+// path - breadth first search of pairs, neighbours visited in code order with USD first
+func (c *CurrencyConverter) path(from, to string) ([]string, []currencyEdge, error) {
+	if err := c.ensure(); err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.graph[from]; !ok {
+		return nil, nil, errors.Errorf("Error unknown currency %s", from)
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 && len(previous[to]) == 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		neighbours := make([]string, 0, len(c.graph[current]))
+		for n := range c.graph[current] {
+			neighbours = append(neighbours, n)
+		}
+
+		sort.Slice(neighbours, func(i, j int) bool {
+			if (neighbours[i] == "USD") != (neighbours[j] == "USD") {
+				return neighbours[i] == "USD"
+			}
+
+			return neighbours[i] < neighbours[j]
+		})
+
+		for _, n := range neighbours {
+			if _, seen := previous[n]; !seen {
+				previous[n] = current
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	if _, ok := previous[to]; !ok {
+		return nil, nil, errors.Errorf("Error no rate of %s in %s", from, to)
+	}
+
+	path := []string{to}
+	for current := to; current != from; current = previous[current] {
+		path = append([]string{previous[current]}, path...)
+	}
+
+	edges := make([]currencyEdge, 0, len(path)-1)
+	for i := 1; i < len(path); i++ {
+		edges = append(edges, c.graph[path[i-1]][path[i]])
+	}
+
+	return path, edges, nil
+}
+
+// loadCloses - daily closes of edge by date
+func (c *CurrencyConverter) loadCloses(e currencyEdge, start, end time.Time) (map[string]float64, error) {
+	closes := make(map[string]float64)
+	add := func(date string, close float64) {
+		if close <= 0 {
+			return
+		}
+
+		if e.invert {
+			close = 1 / close
+		}
+
+		closes[date] = close
+	}
+
+	if e.crypto {
+		cList, err := c.Client.Crypto.DailySpecificPeriod(e.symbol, start, end)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load %s daily candles", e.symbol)
+		}
+
+		if cList != nil {
+			for _, candle := range cList.Historical {
+				add(candle.Date, candle.Close)
+			}
+		}
+
+		return closes, nil
+	}
+
+	fList, err := c.Client.Forex.DailySpecificPeriod(e.symbol, start, end)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error load %s daily candles", e.symbol)
+	}
+
+	if fList != nil {
+		for _, candle := range fList.Historical {
+			add(candle.Date, candle.Close)
+		}
+	}
+
+	return closes, nil
+}
+
+// addCurrencyPair - edges base => quote and quote => base, reverse bid is 1 / ask
+func addCurrencyPair(graph map[string]map[string]currencyEdge, base, quote string, e currencyEdge) {
+	base, quote = strings.ToUpper(strings.TrimSpace(base)), strings.ToUpper(strings.TrimSpace(quote))
+	if len(base) == 0 || len(quote) == 0 || base == quote {
+		return
+	}
+
+	if graph[base] == nil {
+		graph[base] = make(map[string]currencyEdge)
+	}
+
+	if graph[quote] == nil {
+		graph[quote] = make(map[string]currencyEdge)
+	}
+
+	graph[base][quote] = e
+	graph[quote][base] = currencyEdge{symbol: e.symbol, invert: true, crypto: e.crypto, bid: 1 / e.ask, ask: 1 / e.bid, date: e.date}
+}
+
+// cryptoQuoteCurrency - longest currency of graph which ends crypto symbol
+func cryptoQuoteCurrency(graph map[string]map[string]currencyEdge, symbol string) string {
+	quote := ""
+	for currency := range graph {
+		if len(currency) > len(quote) && len(currency) < len(symbol) && strings.HasSuffix(symbol, currency) {
+			quote = currency
+		}
+	}
+
+	return quote
+}
+
+// currencyRateOn - close on date or the last close before it
+func currencyRateOn(history *objects.CurrencyRateHistory, date string) (float64, bool) {
+	i := sort.Search(len(history.Historical), func(i int) bool { return history.Historical[i].Date > date })
+	if i == 0 {
+		return 0, false
+	}
+
+	return history.Historical[i-1].Rate, true
+}
+
+// currencyRateAverage - average of closes after from until to
+func currencyRateAverage(history *objects.CurrencyRateHistory, from, to string) (float64, bool) {
+	var rates []float64
+	for _, p := range history.Historical {
+		if p.Date > from && p.Date <= to {
+			rates = append(rates, p.Rate)
+		}
+	}
+
+	if len(rates) == 0 {
+		return 0, false
+	}
+
+	return floatAverage(rates), true
+}
+
+// fundamentalsPeriodStart - end of previous period, quarters are three months and other periods a year
+func fundamentalsPeriodStart(period string, date time.Time) time.Time {
+	if strings.HasPrefix(strings.ToUpper(period), "Q") {
+		return date.AddDate(0, -3, 0)
+	}
+
+	return date.AddDate(-1, 0, 0)
+}
+
+// convertStatement - copy of statement with monetary float fields multiplied by rate
+func convertStatement[T objects.StatementTypes](statement T, rate float64, currency string) T {
+	rv := reflect.ValueOf(&statement).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := strings.ToLower(screenerFieldName(field))
+		switch {
+		case field.Name == "ReportedCurrency":
+			rv.Field(i).SetString(currency)
+		case field.Type.Kind() == reflect.Float64 && !strings.Contains(name, "ratio") && !strings.Contains(name, "shs"):
+			rv.Field(i).SetFloat(rv.Field(i).Float() * rate)
+		}
+	}
+
+	return statement
+}
+
+func currencyQuoteDate(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+
+	return time.Unix(timestamp, 0).UTC().Format(layoutDate)
+}
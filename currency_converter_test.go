@@ -0,0 +1,77 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestCurrencyConverter(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/fx": `[{"ticker":"EUR/USD","bid":"1.10","ask":"1.12","date":"2021-09-24 10:00:00"},
+			{"ticker":"USD/JPY","bid":"110","ask":"111","date":"2021-09-24 10:00:00"}]`,
+		"/v3/quotes/forex":  `[{"symbol":"USDCNY","name":"USD/CNY","price":6.5,"timestamp":1632477600},{"symbol":"EURUSD","name":"EUR/USD","price":2}]`,
+		"/v3/quotes/crypto": `[{"symbol":"BTCUSD","price":40000,"timestamp":1632477600}]`,
+		"/v3/historical-price-full/USDCNY": `{"symbol":"USDCNY","historical":[
+			{"date":"2021-12-31","close":6.4},{"date":"2021-12-30","close":6.36},{"date":"2020-12-31","close":6.5}]}`,
+		"/v3/historical-price-full/EURUSD": `{"symbol":"EURUSD","historical":[{"date":"2021-09-24","close":1.2},{"date":"2021-09-23","close":1.25}]}`,
+		"/v3/historical-price-full/USDJPY": `{"symbol":"USDJPY","historical":[{"date":"2021-09-24","close":110},{"date":"2021-09-22","close":100}]}`,
+		"/v3/profile/BABA":                 `[{"symbol":"BABA","currency":"USD"}]`,
+		"/v3/income-statement/BABA": `[{"date":"2021-12-31","symbol":"BABA","reportedCurrency":"CNY","period":"Q3","revenue":640,"grossProfitRatio":0.4,"eps":6.4,"weightedAverageShsOut":100},
+			{"date":"2020-12-31","symbol":"BABA","reportedCurrency":"CNY","period":"Q3","revenue":650}]`,
+		"/v3/balance-sheet-statement/BABA": `[{"date":"2021-12-31","symbol":"BABA","reportedCurrency":"CNY","period":"Q3","totalAssets":1280}]`,
+		"/v3/cash-flow-statement/BABA":     `[]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	converter := NewCurrencyConverter(APIClient, CurrencyConverterConfig{Crypto: true})
+
+	rate, err := converter.Rate("eur", "jpy")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(rate.Path) != 3 || rate.Path[1] != "USD" || math.Abs(rate.Bid-121) > 1e-9 || math.Abs(rate.Ask-124.32) > 1e-9 {
+		t.Fatalf("unexpected cross rate: %+v", rate)
+	}
+
+	// Selling USD for EUR gets 1 / ask
+	if amount, err := converter.Convert(112, "USD", "EUR"); err != nil || math.Abs(amount-100) > 1e-9 {
+		t.Fatalf("unexpected conversion: %v %v", amount, err)
+	}
+
+	if rate, err := converter.Rate("BTC", "EUR"); err != nil || math.Abs(rate.Bid-40000/1.12) > 1e-6 || rate.Date != "2021-09-24" {
+		t.Fatalf("unexpected crypto cross rate: %+v %v", rate, err)
+	}
+
+	if _, err := converter.Rate("EUR", "GBP"); err == nil {
+		t.Fatal("expected unknown currency error")
+	}
+
+	history, err := converter.History("EUR", "JPY", time.Date(2021, 9, 22, 0, 0, 0, 0, time.UTC), time.Date(2021, 9, 24, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(history.Historical) != 2 || history.Historical[0].Date != "2021-09-23" || history.Historical[0].Rate != 125 || history.Historical[1].Rate != 132 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	fList, err := converter.FundamentalsInCurrency(objects.RequestFundamentals{Symbol: "BABA"}, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Income at average rate of quarter, balance sheet at close rate
+	average := (1/6.36 + 1/6.4) / 2
+	latest := fList[0]
+	if latest.ReportedCurrency != "USD" || latest.Income.ReportedCurrency != "USD" || math.Abs(latest.Income.Revenue-640*average) > 1e-9 ||
+		latest.Income.GrossProfitRatio != 0.4 || latest.Income.WeightedAverageShsOut != 100 || math.Abs(latest.Income.Eps-6.4*average) > 1e-9 ||
+		math.Abs(latest.BalanceSheet.TotalAssets-200) > 1e-9 || math.Abs(fList[1].Income.Revenue-100) > 1e-9 {
+		t.Fatalf("unexpected normalized fundamentals: %+v %+v", latest.Income, fList[1].Income)
+	}
+}
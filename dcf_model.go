@@ -0,0 +1,377 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// DCF model defaults
+const (
+	dcfDefaultYears          = 5
+	dcfDefaultHistoryYears   = 5
+	dcfDefaultCountry        = "United States"
+	dcfDefaultTerminalGrowth = 0.025
+)
+
+// DCFModelConfig for load new DCF model
+type DCFModelConfig struct {
+	Symbol              string
+	Years               int    // projected years, default 5
+	HistoryYears        int    // annual statements used for historical ratios, default 5
+	Country             string // market risk premium country, default United States
+	UseAnalystEstimates bool   // revenue growth from analyst estimates when scenario has none
+}
+
+// DCFModel - configurable discounted cash flow model
+type DCFModel struct {
+	Config DCFModelConfig
+	Inputs objects.DCFInputs
+}
+
+// LoadDCFModel - fetch statements, estimates, market risk premium and treasury rates
+func LoadDCFModel(client *APIClient, cfg DCFModelConfig) (*DCFModel, error) {
+	if len(cfg.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	if cfg.HistoryYears == 0 {
+		cfg.HistoryYears = dcfDefaultHistoryYears
+	}
+
+	if len(cfg.Country) == 0 {
+		cfg.Country = dcfDefaultCountry
+	}
+
+	inputs := objects.DCFInputs{Symbol: cfg.Symbol}
+	limit := int64(cfg.HistoryYears)
+
+	var err error
+	inputs.IncomeList, err = client.CompanyValuation.IncomeStatement(objects.RequestIncomeStatement{
+		Symbol: cfg.Symbol, Period: objects.CompanyValuationPeriodAnnual, Limit: limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load income statement")
+	}
+
+	inputs.CashFlowList, err = client.CompanyValuation.CashFlowStatement(objects.RequestCashFlowStatement{
+		Symbol: cfg.Symbol, Period: objects.CompanyValuationPeriodAnnual, Limit: limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load cash flow statement")
+	}
+
+	inputs.BalanceSheetList, err = client.CompanyValuation.BalanceSheetStatement(objects.RequestBalanceSheetStatement{
+		Symbol: cfg.Symbol, Period: objects.CompanyValuationPeriodAnnual, Limit: limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load balance sheet statement")
+	}
+
+	if cfg.UseAnalystEstimates {
+		inputs.EstimateList, err = client.CompanyValuation.AnalystEstimates(objects.RequestAnalystEstimates{
+			Symbol: cfg.Symbol, Period: objects.CompanyValuationPeriodAnnual,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "Error load analyst estimates")
+		}
+	}
+
+	premiums, err := client.Economics.MarketRiskPremium()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load market risk premium")
+	}
+
+	for _, p := range premiums {
+		if strings.EqualFold(p.Country, cfg.Country) {
+			inputs.MarketRiskPremium = p.TotalEquityRiskPremium / 100
+		}
+	}
+
+	now := time.Now()
+	rates, err := client.Economics.TreasuryRates(now.AddDate(0, 0, -14), now)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load treasury rates")
+	}
+
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].Date > rates[j].Date
+	})
+
+	if len(rates) > 0 {
+		inputs.RiskFreeRate = rates[0].Year10 / 100
+	}
+
+	profiles, err := client.Stock.CompanyProfile(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load company profile")
+	}
+
+	if len(profiles) > 0 {
+		inputs.Beta = profiles[0].Beta
+		inputs.Price = profiles[0].Price
+		if profiles[0].Price > 0 {
+			inputs.SharesOutstanding = float64(profiles[0].MktCap) / profiles[0].Price
+		}
+	}
+
+	dcf, err := client.CompanyValuation.DiscountedCashFlow(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load discounted cash flow")
+	}
+
+	if len(dcf) > 0 {
+		inputs.FMPDcf = dcf[0].Dcf
+	}
+
+	return &DCFModel{Config: cfg, Inputs: inputs}, nil
+}
+
+// Value - intrinsic value under scenario
+func (m *DCFModel) Value(scenario objects.DCFScenario) (*objects.DCFValuation, error) {
+	in := m.Inputs
+	years := m.Config.Years
+	if years == 0 {
+		years = dcfDefaultYears
+	}
+
+	income := append([]objects.IncomeStatement(nil), in.IncomeList...)
+	sort.SliceStable(income, func(i, j int) bool {
+		return income[i].Date < income[j].Date
+	})
+
+	cashFlow := make(map[string]objects.CashFlowStatement, len(in.CashFlowList))
+	for _, c := range in.CashFlowList {
+		cashFlow[c.Date] = c
+	}
+
+	if len(income) == 0 || income[len(income)-1].Revenue <= 0 {
+		return nil, errors.Errorf("Error no revenue history for %s", in.Symbol)
+	}
+
+	var balance objects.BalanceSheetStatement
+	for _, b := range in.BalanceSheetList {
+		if b.Date > balance.Date {
+			balance = b
+		}
+	}
+
+	latest := income[len(income)-1]
+	a := objects.DCFAssumptions{
+		RiskFreeRate:      in.RiskFreeRate,
+		MarketRiskPremium: in.MarketRiskPremium,
+		Beta:              in.Beta,
+	}
+
+	// Historical ratios
+	var margin, tax, capex, depreciation, revenueChange, workingCapital []float64
+	for i, s := range income {
+		c := cashFlow[s.Date]
+		margin = append(margin, floatRatio(s.OperatingIncome, s.Revenue))
+		capex = append(capex, floatRatio(-c.CapitalExpenditure, s.Revenue))
+		depreciation = append(depreciation, floatRatio(c.DepreciationAndAmortization, s.Revenue))
+		if s.IncomeBeforeTax > 0 {
+			tax = append(tax, math.Max(0, math.Min(0.5, s.IncomeTaxExpense/s.IncomeBeforeTax)))
+		}
+
+		if i > 0 {
+			revenueChange = append(revenueChange, s.Revenue-income[i-1].Revenue)
+			workingCapital = append(workingCapital, -c.ChangeInWorkingCapital)
+		}
+	}
+
+	a.EbitMargin = floatOrDefault(scenario.EbitMargin, floatAverage(margin))
+	a.TaxRate = floatOrDefault(scenario.TaxRate, floatAverage(tax))
+	a.CapexPercent = floatOrDefault(scenario.CapexPercent, floatAverage(capex))
+	a.DepreciationPercent = floatOrDefault(scenario.DepreciationPercent, floatAverage(depreciation))
+	a.WorkingCapitalPercent = floatOrDefault(scenario.WorkingCapitalPercent, floatRatio(floatSum(workingCapital), floatSum(revenueChange)))
+	a.TerminalGrowth = floatOrDefault(scenario.TerminalGrowth, dcfDefaultTerminalGrowth)
+
+	// Cost of capital
+	a.CostOfEquity = a.RiskFreeRate + a.Beta*a.MarketRiskPremium
+	a.CostOfDebt = a.RiskFreeRate
+	if balance.TotalDebt > 0 && latest.InterestExpense > 0 {
+		a.CostOfDebt = latest.InterestExpense / balance.TotalDebt
+	}
+
+	shares := latest.WeightedAverageShsOutDil
+	if shares <= 0 {
+		shares = in.SharesOutstanding
+	}
+
+	if shares <= 0 {
+		return nil, errors.Errorf("Error no shares outstanding for %s", in.Symbol)
+	}
+
+	equity, debt := in.Price*shares, math.Max(balance.TotalDebt, 0)
+	if equity+debt > 0 {
+		a.EquityWeight = equity / (equity + debt)
+		a.DebtWeight = debt / (equity + debt)
+	}
+
+	a.WACC = floatOrDefault(scenario.WACC, a.EquityWeight*a.CostOfEquity+a.DebtWeight*a.CostOfDebt*(1-a.TaxRate))
+
+	// Projection
+	growth := m.growth(scenario, income, years)
+	v := &objects.DCFValuation{
+		Symbol:            in.Symbol,
+		Scenario:          scenario.Name,
+		Assumptions:       a,
+		NetDebt:           balance.TotalDebt - balance.CashAndShortTermInvestments,
+		MinorityInterest:  balance.MinorityInterest,
+		SharesOutstanding: shares,
+		Price:             in.Price,
+		FMPDcf:            in.FMPDcf,
+	}
+
+	prevRevenue := latest.Revenue
+	for i := 0; i < years; i++ {
+		p := objects.DCFProjection{Year: latest.CalendarYear + i + 1, RevenueGrowth: growth[i]}
+		p.Revenue = prevRevenue * (1 + p.RevenueGrowth)
+		p.Ebit = p.Revenue * a.EbitMargin
+		p.Nopat = p.Ebit * (1 - a.TaxRate)
+		p.DepreciationAndAmortization = p.Revenue * a.DepreciationPercent
+		p.CapitalExpenditure = p.Revenue * a.CapexPercent
+		p.ChangeInWorkingCapital = (p.Revenue - prevRevenue) * a.WorkingCapitalPercent
+		p.FreeCashFlow = p.Nopat + p.DepreciationAndAmortization - p.CapitalExpenditure - p.ChangeInWorkingCapital
+		v.Projections = append(v.Projections, p)
+		prevRevenue = p.Revenue
+	}
+
+	if err := discountDCF(v, a.WACC, a.TerminalGrowth); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Sensitivity - intrinsic value per share for grid of WACC and terminal growth
+func (m *DCFModel) Sensitivity(scenario objects.DCFScenario, waccList, growthList []float64) (*objects.DCFSensitivity, error) {
+	base, err := m.Value(scenario)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &objects.DCFSensitivity{WACCList: waccList, TerminalGrowthList: growthList}
+	for _, wacc := range waccList {
+		row, valid := make([]float64, len(growthList)), make([]bool, len(growthList))
+		for j, growth := range growthList {
+			v := *base
+			v.Projections = append([]objects.DCFProjection(nil), base.Projections...)
+			if discountDCF(&v, wacc, growth) == nil {
+				row[j], valid[j] = v.IntrinsicValue, true
+			}
+		}
+
+		s.Values = append(s.Values, row)
+		s.Valid = append(s.Valid, valid)
+	}
+
+	return s, nil
+}
+
+// growth - revenue growth per projected year: scenario, analyst estimates, historical CAGR
+func (m *DCFModel) growth(scenario objects.DCFScenario, income []objects.IncomeStatement, years int) []float64 {
+	first, latest := income[0], income[len(income)-1]
+	cagr := 0.0
+	if n := len(income) - 1; n > 0 && first.Revenue > 0 {
+		cagr = math.Pow(latest.Revenue/first.Revenue, 1/float64(n)) - 1
+	}
+
+	estimates := make(map[int]float64)
+	for _, e := range m.Inputs.EstimateList {
+		if date, err := time.Parse(layoutDate, e.Date); err == nil && e.EstimatedRevenueAvg > 0 {
+			estimates[date.Year()] = e.EstimatedRevenueAvg
+		}
+	}
+
+	growth := make([]float64, years)
+	prevRevenue := latest.Revenue
+	for i := range growth {
+		switch {
+		case len(scenario.RevenueGrowth) > i:
+			growth[i] = scenario.RevenueGrowth[i]
+		case len(scenario.RevenueGrowth) > 0:
+			growth[i] = scenario.RevenueGrowth[len(scenario.RevenueGrowth)-1]
+		case estimates[latest.CalendarYear+i+1] > 0:
+			growth[i] = estimates[latest.CalendarYear+i+1]/prevRevenue - 1
+		default:
+			growth[i] = cagr
+		}
+
+		prevRevenue *= 1 + growth[i]
+	}
+
+	return growth
+}
+
+// discountDCF - present values, terminal value and per share value of projections
+func discountDCF(v *objects.DCFValuation, wacc, terminalGrowth float64) error {
+	if wacc <= terminalGrowth {
+		return errors.Errorf("Error wacc %.4f must be above terminal growth %.4f", wacc, terminalGrowth)
+	}
+
+	v.Assumptions.WACC = wacc
+	v.Assumptions.TerminalGrowth = terminalGrowth
+	v.SumPresentValue = 0
+	for i := range v.Projections {
+		p := &v.Projections[i]
+		p.DiscountFactor = 1 / math.Pow(1+wacc, float64(i+1))
+		p.PresentValue = p.FreeCashFlow * p.DiscountFactor
+		v.SumPresentValue += p.PresentValue
+	}
+
+	if n := len(v.Projections); n > 0 {
+		last := v.Projections[n-1]
+		v.TerminalValue = last.FreeCashFlow * (1 + terminalGrowth) / (wacc - terminalGrowth)
+		v.PresentTerminalValue = v.TerminalValue * last.DiscountFactor
+	}
+
+	v.EnterpriseValue = v.SumPresentValue + v.PresentTerminalValue
+	v.EquityValue = v.EnterpriseValue - v.NetDebt - v.MinorityInterest
+	v.IntrinsicValue = v.EquityValue / v.SharesOutstanding
+	if v.Price > 0 {
+		v.Upside = v.IntrinsicValue/v.Price - 1
+	}
+
+	return nil
+}
+
+// floatOrDefault - value or fallback when missing
+func floatOrDefault(value *float64, fallback float64) float64 {
+	if value != nil {
+		return *value
+	}
+
+	return fallback
+}
+
+// floatRatio - a / b, 0 when b is 0
+func floatRatio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return a / b
+}
+
+func floatSum(list []float64) (total float64) {
+	for _, v := range list {
+		total += v
+	}
+
+	return total
+}
+
+// floatAverage - mean of list, 0 for empty list
+func floatAverage(list []float64) float64 {
+	if len(list) == 0 {
+		return 0
+	}
+
+	return floatSum(list) / float64(len(list))
+}
@@ -0,0 +1,68 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestDCFModel(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/income-statement/TEST": `[
+			{"date":"2021-12-31","symbol":"TEST","calendarYear":"2021","revenue":110,"operatingIncome":22,"incomeBeforeTax":20,"incomeTaxExpense":4,"weightedAverageShsOutDil":10},
+			{"date":"2020-12-31","symbol":"TEST","calendarYear":"2020","revenue":100,"operatingIncome":20,"incomeBeforeTax":20,"incomeTaxExpense":4,"weightedAverageShsOutDil":10}]`,
+		"/v3/cash-flow-statement/TEST": `[
+			{"date":"2021-12-31","symbol":"TEST","calendarYear":"2021","capitalExpenditure":-5.5,"depreciationAndAmortization":5.5,"changeInWorkingCapital":-1},
+			{"date":"2020-12-31","symbol":"TEST","calendarYear":"2020","capitalExpenditure":-5,"depreciationAndAmortization":5,"changeInWorkingCapital":0}]`,
+		"/v3/balance-sheet-statement/TEST": `[{"date":"2021-12-31","symbol":"TEST","calendarYear":"2021","totalDebt":0,"cashAndShortTermInvestments":10}]`,
+		"/v4/market_risk_premium":          `[{"country":"United States","totalEquityRiskPremium":5}]`,
+		"/v4/treasury":                     `[{"date":"2021-12-30","year10":3},{"date":"2021-12-31","year10":4}]`,
+		"/v3/profile/TEST":                 `[{"symbol":"TEST","price":20,"beta":1.2,"mktCap":200}]`,
+		"/v3/discounted-cash-flow/TEST":    `[{"symbol":"TEST","dcf":25}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	model, err := LoadDCFModel(APIClient, DCFModelConfig{Symbol: "TEST", Years: 1})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if model.Inputs.RiskFreeRate != 0.04 || model.Inputs.MarketRiskPremium != 0.05 || model.Inputs.FMPDcf != 25 {
+		t.Fatalf("unexpected inputs: %+v", model.Inputs)
+	}
+
+	valuation, err := model.Value(dcfScenario([]float64{0.1}, 0.1, 0.02))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if a := valuation.Assumptions; math.Abs(a.CostOfEquity-0.1) > 1e-9 || math.Abs(a.WorkingCapitalPercent-0.1) > 1e-9 {
+		t.Fatalf("unexpected assumptions: %+v", a)
+	}
+
+	fcf := 121*0.2*0.8 + 121*0.05 - 121*0.05 - 11*0.1
+	expected := (fcf/1.1 + fcf*1.02/0.08/1.1 + 10) / 10
+	if math.Abs(valuation.IntrinsicValue-expected) > 1e-9 {
+		t.Fatalf("expected intrinsic value %v, got %v", expected, valuation.IntrinsicValue)
+	}
+
+	sensitivity, err := model.Sensitivity(dcfScenario([]float64{0.1}, 0.1, 0.02), []float64{0.08, 0.1}, []float64{0.02, 0.1})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if math.Abs(sensitivity.Values[1][0]-expected) > 1e-9 || sensitivity.Values[1][1] != 0 || sensitivity.Valid[1][1] || !sensitivity.Valid[1][0] || sensitivity.Values[0][0] <= expected {
+		t.Fatalf("unexpected sensitivity: %+v", sensitivity.Values)
+	}
+}
+
+func dcfScenario(growth []float64, wacc, terminalGrowth float64) (s objects.DCFScenario) {
+	s.RevenueGrowth = growth
+	s.WACC = &wacc
+	s.TerminalGrowth = &terminalGrowth
+
+	return s
+}
@@ -0,0 +1,527 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Earnings season defaults
+const (
+	earningsDefaultReactionDays  = 1
+	earningsEpsInlineTolerance   = 0.005 // half cent
+	earningsRevenueInlineRatio   = 0.001
+	earningsTranscriptMaxDayDiff = 10
+)
+
+// EarningsSeasonConfig for create earnings season tracker
+type EarningsSeasonConfig struct {
+	SymbolList    []string // universe, empty - every symbol in earning calendar
+	From          time.Time
+	To            time.Time
+	ReactionDays  int  // trading days after report for price reaction, default 1
+	BulkSurprises bool // missing estimates from CompanyValuation.BulkEarningsSurpises instead of per symbol list
+	Transcripts   bool // look up earning call transcripts of reported symbols
+	SkipEstimates bool // do not load analyst estimates range
+	SkipProfiles  bool // do not load sector from company profile
+	SkipReactions bool // do not load daily candles
+}
+
+// EarningsSeason - tracks earnings of universe between From and To, Refresh updates it incrementally
+type EarningsSeason struct {
+	Client *APIClient
+	Config EarningsSeasonConfig
+
+	mu        sync.Mutex // guards reports
+	refresh   sync.Mutex // serializes Refresh, guards caches loaded without mu
+	now       func() time.Time
+	universe  map[string]bool
+	reports   map[string]*objects.EarningsReport // symbol|fiscal date ending, symbol|date without fiscal date
+	sectors   map[string]string
+	estimates map[string][]objects.AnalystEstimates
+	surprises map[string][]objects.EarningSurprise
+	bulkYears map[int]bool // years of loaded bulk surprises
+}
+
+// NewEarningsSeason ...
+func NewEarningsSeason(client *APIClient, cfg EarningsSeasonConfig) (*EarningsSeason, error) {
+	if cfg.From.IsZero() || cfg.To.Before(cfg.From) {
+		return nil, errors.New("Error invalid earnings season period")
+	}
+
+	if cfg.ReactionDays <= 0 {
+		cfg.ReactionDays = earningsDefaultReactionDays
+	}
+
+	s := &EarningsSeason{
+		Client:    client,
+		Config:    cfg,
+		now:       time.Now,
+		universe:  make(map[string]bool, len(cfg.SymbolList)),
+		reports:   make(map[string]*objects.EarningsReport),
+		sectors:   make(map[string]string),
+		estimates: make(map[string][]objects.AnalystEstimates),
+		surprises: make(map[string][]objects.EarningSurprise),
+		bulkYears: make(map[int]bool),
+	}
+
+	for _, symbol := range cfg.SymbolList {
+		s.universe[strings.ToUpper(symbol)] = true
+	}
+
+	return s, nil
+}
+
+// Refresh - reload calendars and enrich reports that printed since last refresh.
+// Rescheduled reports move to new date, reports are readable while enrichment loads.
+func (s *EarningsSeason) Refresh() error {
+	s.refresh.Lock()
+	defer s.refresh.Unlock()
+
+	from, to := s.Config.From, s.Config.To
+	calendar, err := s.Client.CompanyValuation.EarningCalendar(&from, &to)
+	if err != nil {
+		return errors.Wrap(err, "Error load earning calendar")
+	}
+
+	confirmed, err := s.Client.CompanyValuation.EarningCalendarConfirmed(&from, &to)
+	if err != nil {
+		return errors.Wrap(err, "Error load confirmed earning calendar")
+	}
+
+	s.mu.Lock()
+	today := s.now().Format(layoutDate)
+	for _, c := range calendar {
+		if !s.inUniverse(c.Symbol) {
+			continue
+		}
+
+		r := s.report(c.Symbol, c.FiscalDateEnding, c.Date)
+		r.Time = c.Time
+		if c.EpsEstimated != 0 {
+			r.EpsEstimated = c.EpsEstimated
+		}
+
+		if c.RevenueEstimated != 0 {
+			r.RevenueEstimated = c.RevenueEstimated
+		}
+
+		// Calendar has zero values until company reports
+		if c.Date <= today && (c.Eps != 0 || c.Revenue != 0) {
+			r.Eps = c.Eps
+			r.Revenue = c.Revenue
+			r.Status = objects.EarningsStatusReported
+		}
+	}
+
+	// Confirmed calendar has no fiscal date, reports are matched by date
+	byDate := make(map[string]*objects.EarningsReport, len(s.reports))
+	for _, r := range s.reports {
+		byDate[r.Symbol+"|"+r.Date] = r
+	}
+
+	for _, c := range confirmed {
+		if !s.inUniverse(c.Symbol) {
+			continue
+		}
+
+		r, ok := byDate[c.Symbol+"|"+c.Date]
+		if !ok {
+			r = s.report(c.Symbol, "", c.Date)
+		}
+
+		if r.Status == objects.EarningsStatusScheduled {
+			r.Status = objects.EarningsStatusConfirmed
+		}
+
+		if len(r.Time) == 0 {
+			r.Time = c.When
+		}
+	}
+
+	pending := make(map[string]objects.EarningsReport)
+	for key, r := range s.reports {
+		if r.Status == objects.EarningsStatusReported {
+			pending[key] = *r
+		}
+	}
+	s.mu.Unlock()
+
+	for key, r := range pending {
+		report := r
+		if err := s.enrich(&report); err != nil {
+			return errors.Wrapf(err, "Error update earnings of %s", r.Symbol)
+		}
+
+		s.mu.Lock()
+		s.reports[key] = &report
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Reports - reports sorted by date and symbol
+func (s *EarningsSeason) Reports() []objects.EarningsReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]objects.EarningsReport, 0, len(s.reports))
+	for _, r := range s.reports {
+		list = append(list, *r)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date != list[j].Date {
+			return list[i].Date < list[j].Date
+		}
+
+		return list[i].Symbol < list[j].Symbol
+	})
+
+	return list
+}
+
+// SectorSummary - beat and miss rates by sector, sorted by sector
+func (s *EarningsSeason) SectorSummary() []objects.EarningsSectorSummary {
+	index := make(map[string]*objects.EarningsSectorSummary)
+	surpriseCount := make(map[string]int)
+	reactionCount := make(map[string]int)
+	for _, r := range s.Reports() {
+		summary, ok := index[r.Sector]
+		if !ok {
+			summary = &objects.EarningsSectorSummary{Sector: r.Sector}
+			index[r.Sector] = summary
+		}
+
+		if r.Status != objects.EarningsStatusReported {
+			summary.Scheduled++
+			continue
+		}
+
+		summary.Reported++
+		switch r.EpsResult {
+		case objects.EarningsResultBeat:
+			summary.EpsBeat++
+		case objects.EarningsResultMiss:
+			summary.EpsMiss++
+		case objects.EarningsResultInline:
+			summary.EpsInline++
+		}
+
+		switch r.RevenueResult {
+		case objects.EarningsResultBeat:
+			summary.RevenueBeat++
+		case objects.EarningsResultMiss:
+			summary.RevenueMiss++
+		}
+
+		if r.EpsResult != objects.EarningsResultNone {
+			summary.AverageSurprisePercent += r.EpsSurprisePercent
+			surpriseCount[r.Sector]++
+		}
+
+		if r.ReactionDays > 0 {
+			summary.AverageReaction += r.Reaction
+			reactionCount[r.Sector]++
+		}
+	}
+
+	list := make([]objects.EarningsSectorSummary, 0, len(index))
+	for sector, summary := range index {
+		if n := summary.EpsBeat + summary.EpsMiss + summary.EpsInline; n > 0 {
+			summary.EpsBeatRate = float64(summary.EpsBeat) / float64(n)
+		}
+
+		if n := summary.RevenueBeat + summary.RevenueMiss; n > 0 {
+			summary.RevenueBeatRate = float64(summary.RevenueBeat) / float64(n)
+		}
+
+		if n := surpriseCount[sector]; n > 0 {
+			summary.AverageSurprisePercent /= float64(n)
+		}
+
+		if n := reactionCount[sector]; n > 0 {
+			summary.AverageReaction /= float64(n)
+		}
+
+		list = append(list, *summary)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Sector < list[j].Sector })
+
+	return list
+}
+
+func (s *EarningsSeason) enrich(r *objects.EarningsReport) error {
+	if r.EpsEstimated == 0 {
+		if err := s.loadSurprise(r); err != nil {
+			return err
+		}
+	}
+
+	r.EpsSurprise, r.EpsSurprisePercent = 0, 0
+	r.EpsResult, r.RevenueResult = objects.EarningsResultNone, objects.EarningsResultNone
+	if r.EpsEstimated != 0 {
+		r.EpsSurprise = r.Eps - r.EpsEstimated
+		r.EpsSurprisePercent = r.EpsSurprise / math.Abs(r.EpsEstimated)
+		r.EpsResult = earningsResult(r.EpsSurprise, earningsEpsInlineTolerance)
+	}
+
+	if r.RevenueEstimated != 0 && r.Revenue != 0 {
+		r.RevenueResult = earningsResult(r.Revenue-r.RevenueEstimated, math.Abs(r.RevenueEstimated)*earningsRevenueInlineRatio)
+	}
+
+	if !s.Config.SkipProfiles && len(r.Sector) == 0 {
+		sector, err := s.sector(r.Symbol)
+		if err != nil {
+			return err
+		}
+
+		r.Sector = sector
+	}
+
+	if !s.Config.SkipEstimates && r.NumberAnalystsEps == 0 {
+		if err := s.loadEstimates(r); err != nil {
+			return err
+		}
+	}
+
+	if !s.Config.SkipReactions && r.ReactionDays == 0 {
+		if err := s.loadReaction(r); err != nil {
+			return err
+		}
+	}
+
+	if s.Config.Transcripts && !r.TranscriptAvailable {
+		if err := s.loadTranscript(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *EarningsSeason) loadSurprise(r *objects.EarningsReport) error {
+	if s.Config.BulkSurprises {
+		date, err := time.Parse(layoutDate, r.Date)
+		if err != nil {
+			return err
+		}
+
+		// Bulk file of year covers all symbols and is loaded once
+		if !s.bulkYears[date.Year()] {
+			sList, err := s.Client.CompanyValuation.BulkEarningsSurpises(date.Year())
+			if err != nil {
+				return err
+			}
+
+			s.bulkYears[date.Year()] = true
+			for _, surprise := range sList {
+				s.surprises[surprise.Symbol] = append(s.surprises[surprise.Symbol], surprise)
+			}
+		}
+	} else if _, ok := s.surprises[r.Symbol]; !ok {
+		sList, err := s.Client.CompanyValuation.EarningSurpriseList(r.Symbol)
+		if err != nil {
+			return err
+		}
+
+		s.surprises[r.Symbol] = sList
+	}
+
+	for _, surprise := range s.surprises[r.Symbol] {
+		if surprise.Date == r.Date {
+			r.EpsEstimated = surprise.EstimatedEarning
+			if r.Eps == 0 {
+				r.Eps = surprise.ActualEarningResult
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *EarningsSeason) sector(symbol string) (string, error) {
+	if sector, ok := s.sectors[symbol]; ok {
+		return sector, nil
+	}
+
+	profileList, err := s.Client.Stock.CompanyProfile(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	sector := ""
+	if len(profileList) > 0 {
+		sector = profileList[0].Sector
+	}
+
+	s.sectors[symbol] = sector
+
+	return sector, nil
+}
+
+// loadEstimates - analyst range of quarter closest to fiscal date ending
+func (s *EarningsSeason) loadEstimates(r *objects.EarningsReport) error {
+	eList, ok := s.estimates[r.Symbol]
+	if !ok {
+		var err error
+		eList, err = s.Client.CompanyValuation.AnalystEstimates(objects.RequestAnalystEstimates{
+			Symbol: r.Symbol, Period: objects.CompanyValuationPeriodQuarter,
+		})
+		if err != nil {
+			return err
+		}
+
+		s.estimates[r.Symbol] = eList
+	}
+
+	fiscal, err := time.Parse(layoutDate, r.FiscalDateEnding)
+	if err != nil {
+		return nil
+	}
+
+	best := -1
+	bestDiff := time.Duration(math.MaxInt64)
+	for i, e := range eList {
+		date, err := time.Parse(layoutDate, e.Date)
+		if err != nil {
+			continue
+		}
+
+		if diff := absDuration(date.Sub(fiscal)); diff < bestDiff {
+			best, bestDiff = i, diff
+		}
+	}
+
+	if best >= 0 && bestDiff <= 45*24*time.Hour {
+		r.AnalystEpsLow = eList[best].EstimatedEpsLow
+		r.AnalystEpsHigh = eList[best].EstimatedEpsHigh
+		r.NumberAnalystsEps = eList[best].NumberAnalystsEstimatedEps
+	}
+
+	return nil
+}
+
+// loadReaction - before market open reports react from previous close, others from close of report date
+func (s *EarningsSeason) loadReaction(r *objects.EarningsReport) error {
+	date, err := time.Parse(layoutDate, r.Date)
+	if err != nil {
+		return nil
+	}
+
+	cList, err := s.Client.Stock.DailySpecificPeriod(r.Symbol, date.AddDate(0, 0, -10), date.AddDate(0, 0, 2*s.Config.ReactionDays+10))
+	if err != nil {
+		return err
+	}
+
+	if cList == nil {
+		return nil
+	}
+
+	candles := make([]objects.StockDailyCandle, len(cList.Historical))
+	copy(candles, cList.Historical)
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Date < candles[j].Date })
+
+	// Index of first candle after report date
+	after := sort.Search(len(candles), func(i int) bool { return candles[i].Date > r.Date })
+	base := after - 1
+	if strings.EqualFold(r.Time, "bmo") {
+		base = sort.Search(len(candles), func(i int) bool { return candles[i].Date >= r.Date }) - 1
+	}
+
+	exit := base + s.Config.ReactionDays
+	if base < 0 || exit >= len(candles) || candles[base].Close == 0 {
+		return nil
+	}
+
+	r.Reaction = candles[exit].Close/candles[base].Close - 1
+	r.ReactionDays = s.Config.ReactionDays
+
+	return nil
+}
+
+// loadTranscript - try calendar quarter of fiscal date ending and following fiscal quarters,
+// transcript must be dated near report date
+func (s *EarningsSeason) loadTranscript(r *objects.EarningsReport) error {
+	fiscal, err := time.Parse(layoutDate, r.FiscalDateEnding)
+	if err != nil {
+		return nil
+	}
+
+	date, err := time.Parse(layoutDate, r.Date)
+	if err != nil {
+		return nil
+	}
+
+	quarter, year := (int(fiscal.Month())-1)/3+1, fiscal.Year()
+	for i := 0; i < 4; i++ {
+		tList, err := s.Client.CompanyValuation.EarningCallTranscript(objects.RequestEarningCallTranscript{
+			Symbol: r.Symbol, Quarter: int64(quarter), Year: int64(year),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, t := range tList {
+			transcriptDate, err := parseBarDate(t.Date)
+			if err != nil {
+				continue
+			}
+
+			if absDuration(transcriptDate.Sub(date)) <= earningsTranscriptMaxDayDiff*24*time.Hour {
+				r.TranscriptAvailable = true
+				r.TranscriptDate = t.Date
+				r.TranscriptQuarter = t.Quarter
+				r.TranscriptYear = t.Year
+
+				return nil
+			}
+		}
+
+		quarter++
+		if quarter > 4 {
+			quarter, year = 1, year+1
+		}
+	}
+
+	return nil
+}
+
+// report - report of symbol for fiscal period, not reported report moves to date when rescheduled
+func (s *EarningsSeason) report(symbol, fiscal, date string) *objects.EarningsReport {
+	key := symbol + "|" + firstNonEmpty(fiscal, date)
+	r, ok := s.reports[key]
+	if !ok {
+		r = &objects.EarningsReport{Symbol: symbol, Date: date, FiscalDateEnding: fiscal, Status: objects.EarningsStatusScheduled}
+		s.reports[key] = r
+	}
+
+	if r.Date != date && r.Status != objects.EarningsStatusReported {
+		r.Date = date
+		r.Status = objects.EarningsStatusScheduled
+	}
+
+	return r
+}
+
+func (s *EarningsSeason) inUniverse(symbol string) bool {
+	return len(s.universe) == 0 || s.universe[strings.ToUpper(symbol)]
+}
+
+func earningsResult(surprise, tolerance float64) objects.EarningsResult {
+	switch {
+	case math.Abs(surprise) <= tolerance:
+		return objects.EarningsResultInline
+	case surprise > 0:
+		return objects.EarningsResultBeat
+	}
+
+	return objects.EarningsResultMiss
+}
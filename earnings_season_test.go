@@ -0,0 +1,146 @@
+package fmpcloud
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestEarningsSeason(t *testing.T) {
+	routes := map[string]string{
+		"/v3/earning_calendar": `[
+			{"date":"2021-01-26","symbol":"AAA","eps":1.10,"epsEstimated":1.00,"time":"bmo","revenue":105,"revenueEstimated":100,"fiscalDateEnding":"2020-12-31"},
+			{"date":"2021-01-27","symbol":"BBB","eps":0.50,"time":"amc","revenue":99.99,"revenueEstimated":100,"fiscalDateEnding":"2020-12-31"},
+			{"date":"2021-02-10","symbol":"CCC","time":"amc","fiscalDateEnding":"2020-12-31"},
+			{"date":"2021-02-11","symbol":"ZZZ","time":"amc","fiscalDateEnding":"2020-12-31"}]`,
+		"/v4/earning-calendar-confirmed": `[{"symbol":"CCC","date":"2021-02-10","when":"amc"}]`,
+		"/v3/earnings-surpises/BBB":      `[{"date":"2021-01-27","symbol":"BBB","actualEarningResult":0.5,"estimatedEarning":0.6}]`,
+		"/v3/profile/AAA":                `[{"symbol":"AAA","sector":"Technology"}]`,
+		"/v3/profile/BBB":                `[{"symbol":"BBB","sector":"Technology"}]`,
+		"/v3/analyst-estimates/AAA":      `[{"symbol":"AAA","date":"2020-12-31","estimatedEpsLow":0.9,"estimatedEpsHigh":1.2,"numberAnalystsEstimatedEps":12}]`,
+		"/v3/analyst-estimates/BBB":      `[]`,
+		"/v3/historical-price-full/AAA": `{"symbol":"AAA","historical":[
+			{"date":"2021-01-27","close":110},
+			{"date":"2021-01-26","close":105},
+			{"date":"2021-01-25","close":100}]}`,
+		"/v3/historical-price-full/BBB": `{"symbol":"BBB","historical":[
+			{"date":"2021-01-26","close":48},
+			{"date":"2021-01-27","close":50},
+			{"date":"2021-01-28","close":45}]}`,
+		"/v3/earning_call_transcript/AAA?quarter=4&year=2020": `[{"symbol":"AAA","quarter":4,"year":2020,"date":"2021-01-26 08:30:00"}]`,
+		"/v3/earning_call_transcript/AAA":                     `[]`,
+		"/v3/earning_call_transcript/BBB":                     `[]`,
+	}
+
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, routes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	season, err := NewEarningsSeason(APIClient, EarningsSeasonConfig{
+		SymbolList:  []string{"AAA", "BBB", "CCC"},
+		From:        time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC),
+		Transcripts: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	season.now = func() time.Time { return time.Date(2021, 1, 29, 0, 0, 0, 0, time.UTC) }
+	if err := season.Refresh(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	reportList := season.Reports()
+	if len(reportList) != 3 {
+		t.Fatalf("expected 3 reports, got %+v", reportList)
+	}
+
+	aaa, bbb, ccc := reportList[0], reportList[1], reportList[2]
+	if aaa.EpsResult != objects.EarningsResultBeat || aaa.RevenueResult != objects.EarningsResultBeat ||
+		math.Abs(aaa.EpsSurprisePercent-0.1) > 1e-9 || aaa.NumberAnalystsEps != 12 || aaa.Sector != "Technology" {
+		t.Fatalf("unexpected AAA report: %+v", aaa)
+	}
+
+	// Before market open reaction from previous close
+	if aaa.ReactionDays != 1 || math.Abs(aaa.Reaction-0.05) > 1e-9 || !aaa.TranscriptAvailable || aaa.TranscriptQuarter != 4 {
+		t.Fatalf("unexpected AAA reaction or transcript: %+v", aaa)
+	}
+
+	// Estimate from surprise list, after market close reaction from report date close
+	if bbb.EpsResult != objects.EarningsResultMiss || bbb.EpsEstimated != 0.6 || bbb.RevenueResult != objects.EarningsResultInline ||
+		math.Abs(bbb.Reaction+0.1) > 1e-9 || bbb.TranscriptAvailable {
+		t.Fatalf("unexpected BBB report: %+v", bbb)
+	}
+
+	if ccc.Status != objects.EarningsStatusConfirmed || ccc.EpsResult != objects.EarningsResultNone {
+		t.Fatalf("unexpected CCC report: %+v", ccc)
+	}
+
+	summaryList := season.SectorSummary()
+	if len(summaryList) != 2 {
+		t.Fatalf("expected 2 sectors, got %+v", summaryList)
+	}
+
+	tech := summaryList[1]
+	if tech.Sector != "Technology" || tech.Reported != 2 || tech.EpsBeatRate != 0.5 || tech.RevenueBeatRate != 1 ||
+		math.Abs(tech.AverageReaction+0.025) > 1e-9 {
+		t.Fatalf("unexpected sector summary: %+v", tech)
+	}
+
+	// Rescheduled report moves to new date instead of adding second entry
+	routes["/v3/earning_calendar"] = strings.Replace(routes["/v3/earning_calendar"], `"2021-02-10","symbol":"CCC"`, `"2021-02-12","symbol":"CCC"`, 1)
+	routes["/v4/earning-calendar-confirmed"] = `[]`
+	if err := season.Refresh(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	reportList = season.Reports()
+	if len(reportList) != 3 {
+		t.Fatalf("expected 3 reports after reschedule, got %+v", reportList)
+	}
+
+	ccc = reportList[2]
+	if ccc.Symbol != "CCC" || ccc.Date != "2021-02-12" || ccc.Status != objects.EarningsStatusScheduled {
+		t.Fatalf("unexpected rescheduled CCC report: %+v", ccc)
+	}
+}
+
+func TestEarningsSeasonBulkSurprises(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v4/earnings-surprises-bulk?year=2021": "date,symbol,actualEarningResult,estimatedEarning\n2021-10-27,AAA,1.2,1.1\n",
+		"/v4/earnings-surprises-bulk?year=2022": "date,symbol,actualEarningResult,estimatedEarning\n2022-01-26,AAA,1.5,1.3\n",
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	season, err := NewEarningsSeason(APIClient, EarningsSeasonConfig{
+		From:          time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+		BulkSurprises: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Symbol missing from bulk file does not reload year, Q4 report in January loads next year
+	reportList := []*objects.EarningsReport{
+		{Symbol: "AAA", Date: "2021-10-27"},
+		{Symbol: "NONE", Date: "2021-11-02"},
+		{Symbol: "AAA", Date: "2022-01-26"},
+	}
+	for _, r := range reportList {
+		if err := season.loadSurprise(r); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if len(season.surprises["AAA"]) != 2 || reportList[0].EpsEstimated != 1.1 || reportList[2].EpsEstimated != 1.3 {
+		t.Fatalf("unexpected bulk surprises: %+v %+v", season.surprises, reportList)
+	}
+}
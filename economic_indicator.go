@@ -0,0 +1,356 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// economicCalendarMaxDays - longest period of one economic calendar request
+const economicCalendarMaxDays = 90
+
+// economicIndicatorCatalogue - supported indicators of Economics.Indicator
+var economicIndicatorCatalogue = []objects.EconomicIndicatorInfo{
+	{Name: objects.EconomicIndicatorGDP, Title: "Gross Domestic Product", Unit: "billions of dollars, SAAR", Frequency: objects.EconomicFrequencyQuarterly, CalendarEvent: "GDP Growth Rate QoQ", ReleaseLag: 30},
+	{Name: objects.EconomicIndicatorRealGDP, Title: "Real Gross Domestic Product", Unit: "billions of chained dollars, SAAR", Frequency: objects.EconomicFrequencyQuarterly, CalendarEvent: "GDP Growth Rate QoQ", ReleaseLag: 30},
+	{Name: objects.EconomicIndicatorNominalPotentialGDP, Title: "Nominal Potential Gross Domestic Product", Unit: "billions of dollars", Frequency: objects.EconomicFrequencyQuarterly, ReleaseLag: 90},
+	{Name: objects.EconomicIndicatorRealGDPPerCapita, Title: "Real GDP per Capita", Unit: "chained dollars", Frequency: objects.EconomicFrequencyQuarterly, CalendarEvent: "GDP Growth Rate QoQ", ReleaseLag: 30},
+	{Name: objects.EconomicIndicatorFederalFunds, Title: "Federal Funds Effective Rate", Unit: "percent", Frequency: objects.EconomicFrequencyMonthly, ReleaseLag: 1},
+	{Name: objects.EconomicIndicatorCPI, Title: "Consumer Price Index for All Urban Consumers", Unit: "index 1982-1984=100", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "CPI", ReleaseLag: 15},
+	{Name: objects.EconomicIndicatorInflationRate, Title: "Inflation Rate", Unit: "percent", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Inflation Rate YoY", ReleaseLag: 15},
+	{Name: objects.EconomicIndicatorInflation, Title: "Inflation, Consumer Prices", Unit: "percent", Frequency: objects.EconomicFrequencyAnnual, ReleaseLag: 120},
+	{Name: objects.EconomicIndicatorRetailSales, Title: "Retail Sales", Unit: "millions of dollars", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Retail Sales MoM", ReleaseLag: 16},
+	{Name: objects.EconomicIndicatorConsumerSentiment, Title: "University of Michigan Consumer Sentiment", Unit: "index 1966:Q1=100", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Michigan Consumer Sentiment", ReleaseLag: 0},
+	{Name: objects.EconomicIndicatorDurableGoods, Title: "Durable Goods New Orders", Unit: "millions of dollars", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Durable Goods Orders MoM", ReleaseLag: 27},
+	{Name: objects.EconomicIndicatorUnemploymentRate, Title: "Unemployment Rate", Unit: "percent", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Unemployment Rate", ReleaseLag: 7},
+	{Name: objects.EconomicIndicatorTotalNonfarmPayroll, Title: "All Employees, Total Nonfarm", Unit: "thousands of persons", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Non Farm Payrolls", ReleaseLag: 7},
+	{Name: objects.EconomicIndicatorInitialClaims, Title: "Initial Jobless Claims", Unit: "number", Frequency: objects.EconomicFrequencyWeekly, CalendarEvent: "Initial Jobless Claims", ReleaseLag: 5},
+	{Name: objects.EconomicIndicatorIndustrialProduction, Title: "Industrial Production: Total Index", Unit: "index 2017=100", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Industrial Production MoM", ReleaseLag: 16},
+	{Name: objects.EconomicIndicatorHousingStarts, Title: "Housing Starts: Total Units", Unit: "thousands of units, SAAR", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Housing Starts", ReleaseLag: 18},
+	{Name: objects.EconomicIndicatorTotalVehicleSales, Title: "Total Vehicle Sales", Unit: "millions of units, SAAR", Frequency: objects.EconomicFrequencyMonthly, CalendarEvent: "Total Vehicle Sales", ReleaseLag: 3},
+	{Name: objects.EconomicIndicatorRetailMoneyFunds, Title: "Retail Money Funds", Unit: "billions of dollars", Frequency: objects.EconomicFrequencyWeekly, ReleaseLag: 5},
+	{Name: objects.EconomicIndicatorRecessionProbabilities, Title: "Smoothed U.S. Recession Probabilities", Unit: "percent", Frequency: objects.EconomicFrequencyMonthly, ReleaseLag: 60},
+	{Name: objects.EconomicIndicatorCertificatesOfDeposit3Month, Title: "3-Month Certificates of Deposit Rate", Unit: "percent", Frequency: objects.EconomicFrequencyMonthly, ReleaseLag: 30},
+	{Name: objects.EconomicIndicatorCreditCardInterestRate, Title: "Commercial Bank Interest Rate on Credit Card Plans", Unit: "percent", Frequency: objects.EconomicFrequencyQuarterly, ReleaseLag: 40},
+	{Name: objects.EconomicIndicatorMortgage30YearFixedRate, Title: "30-Year Fixed Rate Mortgage Average", Unit: "percent", Frequency: objects.EconomicFrequencyWeekly, CalendarEvent: "MBA 30-Year Mortgage Rate", ReleaseLag: 0},
+	{Name: objects.EconomicIndicatorMortgage15YearFixedRate, Title: "15-Year Fixed Rate Mortgage Average", Unit: "percent", Frequency: objects.EconomicFrequencyWeekly, ReleaseLag: 0},
+}
+
+// EconomicIndicators - catalogue of supported indicators
+func EconomicIndicators() []objects.EconomicIndicatorInfo {
+	list := make([]objects.EconomicIndicatorInfo, len(economicIndicatorCatalogue))
+	copy(list, economicIndicatorCatalogue)
+
+	return list
+}
+
+// EconomicIndicator - catalogue entry of indicator
+func EconomicIndicator(name objects.EconomicIndicatorName) (objects.EconomicIndicatorInfo, bool) {
+	for _, info := range economicIndicatorCatalogue {
+		if info.Name == name {
+			return info, true
+		}
+	}
+
+	return objects.EconomicIndicatorInfo{}, false
+}
+
+// EconomicSeries - observations of indicator, oldest first
+type EconomicSeries struct {
+	Info            objects.EconomicIndicatorInfo
+	ObservationList []objects.EconomicObservation
+}
+
+// IndicatorSeries - typed series of catalogue indicator, release dates estimated from release lag
+func (e *Economics) IndicatorSeries(name objects.EconomicIndicatorName, from *time.Time, to *time.Time) (*EconomicSeries, error) {
+	info, ok := EconomicIndicator(name)
+	if !ok {
+		return nil, errors.Errorf("Error unknown economic indicator %s", name)
+	}
+
+	iList, err := e.Indicator(name.String(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEconomicSeries(info, iList), nil
+}
+
+// LoadEconomicSeries - indicator series with release dates from economic calendar
+func LoadEconomicSeries(client *APIClient, name objects.EconomicIndicatorName, from time.Time, to time.Time) (*EconomicSeries, error) {
+	series, err := client.Economics.IndicatorSeries(name, &from, &to)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load economic indicator")
+	}
+
+	if len(series.Info.CalendarEvent) == 0 || len(series.ObservationList) == 0 {
+		return series, nil
+	}
+
+	// Releases of last periods happen after to
+	calendarTo := to.AddDate(0, 0, series.Info.ReleaseLag+economicPeriodDays(series.Info.Frequency)+30)
+	calendar, err := loadEconomicCalendar(client, from, calendarTo)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load economic calendar")
+	}
+
+	series.AlignReleases(calendar, "US")
+
+	return series, nil
+}
+
+// NewEconomicSeries - series from raw indicator values
+func NewEconomicSeries(info objects.EconomicIndicatorInfo, iList []objects.EconomicsIndicator) *EconomicSeries {
+	s := &EconomicSeries{Info: info, ObservationList: make([]objects.EconomicObservation, 0, len(iList))}
+	for _, i := range iList {
+		s.ObservationList = append(s.ObservationList, objects.EconomicObservation{Date: i.Date, Value: i.Value})
+	}
+
+	sort.SliceStable(s.ObservationList, func(i, j int) bool { return s.ObservationList[i].Date < s.ObservationList[j].Date })
+	for i := range s.ObservationList {
+		s.ObservationList[i].ReleaseDate = s.estimatedRelease(s.ObservationList[i].Date)
+	}
+
+	return s
+}
+
+// AlignReleases - release date of each observation is first matching calendar event after period end,
+// country empty matches all countries, observations without release keep estimated date
+func (s *EconomicSeries) AlignReleases(calendar []objects.EconomicCalendar, country string) {
+	var releases []string
+	event := strings.ToLower(s.Info.CalendarEvent)
+	for _, c := range calendar {
+		if len(country) > 0 && !strings.EqualFold(c.Country, country) {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(c.Event), event) {
+			releases = append(releases, economicDateTime(c.Date))
+		}
+	}
+
+	sort.Strings(releases)
+
+	for i := range s.ObservationList {
+		o := &s.ObservationList[i]
+		o.ReleaseDate = s.estimatedRelease(o.Date)
+
+		date, err := time.Parse(layoutDate, o.Date)
+		if err != nil {
+			continue
+		}
+
+		// Release after next period end belongs to later observation
+		periodEnd := economicPeriodEnd(date, s.Info.Frequency)
+		nextPeriodEnd := economicPeriodEnd(periodEnd, s.Info.Frequency).Format(layoutDateTime)
+		n := sort.SearchStrings(releases, periodEnd.Format(layoutDateTime))
+		if n < len(releases) && releases[n] < nextPeriodEnd {
+			o.ReleaseDate = releases[n]
+		}
+	}
+}
+
+// AsOf - observations published at or before date, point in time view without look-ahead
+func (s *EconomicSeries) AsOf(date time.Time) *EconomicSeries {
+	limit := date.Format(layoutDateTime)
+	out := &EconomicSeries{Info: s.Info}
+	for _, o := range s.ObservationList {
+		if o.ReleaseDate <= limit {
+			out.ObservationList = append(out.ObservationList, o)
+		}
+	}
+
+	return out
+}
+
+// Resample - combine observations into periods of lower frequency, release date is latest release in period
+func (s *EconomicSeries) Resample(frequency objects.EconomicFrequency, aggregation objects.EconomicAggregation) *EconomicSeries {
+	info := s.Info
+	info.Frequency = frequency
+	out := &EconomicSeries{Info: info}
+
+	var bucket []objects.EconomicObservation
+	flush := func() {
+		if len(bucket) == 0 {
+			return
+		}
+
+		date, _ := time.Parse(layoutDate, bucket[0].Date)
+		o := objects.EconomicObservation{Date: economicPeriodStart(date, frequency).Format(layoutDate)}
+		for _, b := range bucket {
+			o.Value += b.Value
+			if b.ReleaseDate > o.ReleaseDate {
+				o.ReleaseDate = b.ReleaseDate
+			}
+		}
+
+		switch aggregation {
+		case objects.EconomicAggregationMean:
+			o.Value /= float64(len(bucket))
+		case objects.EconomicAggregationSum:
+		default:
+			o.Value = bucket[len(bucket)-1].Value
+		}
+
+		out.ObservationList = append(out.ObservationList, o)
+		bucket = nil
+	}
+
+	current := ""
+	for _, o := range s.ObservationList {
+		date, err := time.Parse(layoutDate, o.Date)
+		if err != nil {
+			continue
+		}
+
+		period := economicPeriodStart(date, frequency).Format(layoutDate)
+		if period != current {
+			flush()
+			current = period
+		}
+
+		bucket = append(bucket, o)
+	}
+
+	flush()
+
+	return out
+}
+
+// YoY - change against observation one year earlier, 0.03 = 3%
+func (s *EconomicSeries) YoY() *EconomicSeries {
+	return s.PercentChange(1, 0)
+}
+
+// MoM - change against observation one month earlier
+func (s *EconomicSeries) MoM() *EconomicSeries {
+	return s.PercentChange(0, 1)
+}
+
+// PercentChange - change against nearest observation years and months earlier,
+// observations without base within half period are dropped
+func (s *EconomicSeries) PercentChange(years, months int) *EconomicSeries {
+	out := &EconomicSeries{Info: s.Info}
+	out.Info.Unit = "ratio"
+
+	dates := make([]time.Time, len(s.ObservationList))
+	for i, o := range s.ObservationList {
+		dates[i], _ = time.Parse(layoutDate, o.Date)
+	}
+
+	// Daily series skip weekends and holidays
+	tolerance := time.Duration(economicPeriodDays(s.Info.Frequency)) * 24 * time.Hour / 2
+	if tolerance < 72*time.Hour {
+		tolerance = 72 * time.Hour
+	}
+	for i, o := range s.ObservationList {
+		target := dates[i].AddDate(-years, -months, 0)
+		n := sort.Search(len(dates), func(k int) bool { return !dates[k].Before(target) })
+
+		base := -1
+		for _, k := range []int{n - 1, n} {
+			if k < 0 || k >= i || absDuration(dates[k].Sub(target)) > tolerance {
+				continue
+			}
+
+			if base == -1 || absDuration(dates[k].Sub(target)) < absDuration(dates[base].Sub(target)) {
+				base = k
+			}
+		}
+
+		if base == -1 || s.ObservationList[base].Value == 0 {
+			continue
+		}
+
+		out.ObservationList = append(out.ObservationList, objects.EconomicObservation{
+			Date:        o.Date,
+			ReleaseDate: o.ReleaseDate,
+			Value:       (o.Value - s.ObservationList[base].Value) / math.Abs(s.ObservationList[base].Value),
+		})
+	}
+
+	return out
+}
+
+func (s *EconomicSeries) estimatedRelease(date string) string {
+	d, err := time.Parse(layoutDate, date)
+	if err != nil {
+		return ""
+	}
+
+	return economicPeriodEnd(d, s.Info.Frequency).AddDate(0, 0, s.Info.ReleaseLag).Format(layoutDateTime)
+}
+
+// loadEconomicCalendar - economic calendar split into allowed request periods
+func loadEconomicCalendar(client *APIClient, from time.Time, to time.Time) ([]objects.EconomicCalendar, error) {
+	return loadCalendarChunks(from, to, economicCalendarMaxDays, func(from, to *time.Time) ([]objects.EconomicCalendar, error) {
+		return client.CompanyValuation.EconomicCalendar(objects.RequestEconomicCalendar{From: from, To: to})
+	})
+}
+
+func economicPeriodStart(date time.Time, frequency objects.EconomicFrequency) time.Time {
+	y, m, d := date.Date()
+	switch frequency {
+	case objects.EconomicFrequencyWeekly:
+		return time.Date(y, m, d-(int(date.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+	case objects.EconomicFrequencyMonthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	case objects.EconomicFrequencyQuarterly:
+		return time.Date(y, (m-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	case objects.EconomicFrequencyAnnual:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func economicPeriodEnd(date time.Time, frequency objects.EconomicFrequency) time.Time {
+	switch frequency {
+	case objects.EconomicFrequencyWeekly:
+		return date.AddDate(0, 0, 7)
+	case objects.EconomicFrequencyMonthly:
+		return date.AddDate(0, 1, 0)
+	case objects.EconomicFrequencyQuarterly:
+		return date.AddDate(0, 3, 0)
+	case objects.EconomicFrequencyAnnual:
+		return date.AddDate(1, 0, 0)
+	}
+
+	return date.AddDate(0, 0, 1)
+}
+
+func economicPeriodDays(frequency objects.EconomicFrequency) int {
+	switch frequency {
+	case objects.EconomicFrequencyWeekly:
+		return 7
+	case objects.EconomicFrequencyMonthly:
+		return 30
+	case objects.EconomicFrequencyQuarterly:
+		return 91
+	case objects.EconomicFrequencyAnnual:
+		return 365
+	}
+
+	return 1
+}
+
+// economicDateTime - calendar date with time
+func economicDateTime(s string) string {
+	if len(s) == len(layoutDate) {
+		return s + " 00:00:00"
+	}
+
+	return s
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
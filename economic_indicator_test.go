@@ -0,0 +1,72 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestEconomicSeries(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v4/economic": `[
+			{"date":"2021-03-01","value":110},
+			{"date":"2021-02-01","value":105},
+			{"date":"2021-01-01","value":104},
+			{"date":"2020-03-01","value":100},
+			{"date":"2020-02-01","value":100}]`,
+		"/v3/economic_calendar": `[
+			{"event":"CPI","date":"2021-04-13 12:30:00","country":"US","actual":110},
+			{"event":"CPI","date":"2021-03-10 12:30:00","country":"US","actual":105},
+			{"event":"CPI","date":"2021-03-10 12:30:00","country":"GB","actual":99},
+			{"event":"Retail Sales MoM","date":"2021-03-16 12:30:00","country":"US"}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := APIClient.Economics.IndicatorSeries("unknown", nil, nil); err == nil {
+		t.Fatal("expected error for unknown indicator")
+	}
+
+	series, err := LoadEconomicSeries(APIClient, objects.EconomicIndicatorCPI, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if series.Info.Unit == "" || series.ObservationList[0].Date != "2020-02-01" {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+
+	feb := series.ObservationList[3]
+	if feb.Date != "2021-02-01" || feb.ReleaseDate != "2021-03-10 12:30:00" || series.ObservationList[4].ReleaseDate != "2021-04-13 12:30:00" {
+		t.Fatalf("unexpected release alignment: %+v", series.ObservationList)
+	}
+
+	// Without calendar release, estimated from lag
+	if series.ObservationList[0].ReleaseDate != "2020-03-16 00:00:00" {
+		t.Fatalf("unexpected estimated release: %+v", series.ObservationList[0])
+	}
+
+	asOf := series.AsOf(time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC))
+	if len(asOf.ObservationList) != 4 {
+		t.Fatalf("expected march value unpublished, got %+v", asOf.ObservationList)
+	}
+
+	yoy := series.YoY()
+	if len(yoy.ObservationList) != 2 || math.Abs(yoy.ObservationList[1].Value-0.1) > 1e-12 {
+		t.Fatalf("unexpected YoY: %+v", yoy.ObservationList)
+	}
+
+	mom := series.MoM()
+	if len(mom.ObservationList) != 3 || math.Abs(mom.ObservationList[0].Value) > 1e-12 || math.Abs(mom.ObservationList[2].Value-(110.0/105-1)) > 1e-12 {
+		t.Fatalf("unexpected MoM: %+v", mom.ObservationList)
+	}
+
+	quarterly := series.Resample(objects.EconomicFrequencyQuarterly, objects.EconomicAggregationMean)
+	if len(quarterly.ObservationList) != 2 || quarterly.ObservationList[1].Date != "2021-01-01" ||
+		math.Abs(quarterly.ObservationList[1].Value-(104+105+110)/3.0) > 1e-12 || quarterly.ObservationList[1].ReleaseDate != "2021-04-13 12:30:00" {
+		t.Fatalf("unexpected resample: %+v", quarterly.ObservationList)
+	}
+}
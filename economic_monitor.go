@@ -0,0 +1,288 @@
+package fmpcloud
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Economic monitor defaults
+const (
+	economicMonitorDefaultInterval = time.Minute
+	economicMonitorDefaultWindow   = 24 * time.Hour
+	economicMonitorMinSample       = 3
+)
+
+// EconomicMonitorConfig for create economic calendar monitor
+type EconomicMonitorConfig struct {
+	Interval time.Duration // poll interval, default 1 minute
+	Window   time.Duration // polled period around now, default 24 hours each side
+}
+
+// EconomicSubscription - filter of releases, empty lists match all
+type EconomicSubscription struct {
+	CountryList []string // US, GB ...
+	EventList   []string // case-insensitive part of event name
+	ImpactList  []string // Low, Medium, High
+}
+
+// EconomicMonitor - polls economic calendar and emits releases when actual values print
+type EconomicMonitor struct {
+	Client *APIClient
+	Config EconomicMonitorConfig
+
+	mu            sync.Mutex
+	now           func() time.Time
+	seen          map[string]bool
+	surprises     map[string][]float64 // event|country => past surprises against estimate
+	changes       map[string][]float64 // event|country => past changes against previous print
+	subscriptions map[int]economicSubscriber
+	nextID        int
+}
+
+type economicSubscriber struct {
+	filter  EconomicSubscription
+	handler func(objects.EconomicRelease)
+}
+
+// NewEconomicMonitor ...
+func NewEconomicMonitor(client *APIClient, cfg EconomicMonitorConfig) *EconomicMonitor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = economicMonitorDefaultInterval
+	}
+
+	if cfg.Window <= 0 {
+		cfg.Window = economicMonitorDefaultWindow
+	}
+
+	return &EconomicMonitor{
+		Client:        client,
+		Config:        cfg,
+		now:           time.Now,
+		seen:          make(map[string]bool),
+		surprises:     make(map[string][]float64),
+		changes:       make(map[string][]float64),
+		subscriptions: make(map[int]economicSubscriber),
+	}
+}
+
+// Seed - surprise history from past calendar, printed events are not emitted later
+func (m *EconomicMonitor) Seed(from time.Time, to time.Time) error {
+	calendar, err := loadEconomicCalendar(m.Client, from, to)
+	if err != nil {
+		return errors.Wrap(err, "Error load economic calendar")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range calendar {
+		if e.Actual == nil || m.seen[economicEventKey(e)] {
+			continue
+		}
+
+		m.seen[economicEventKey(e)] = true
+		m.record(e)
+	}
+
+	return nil
+}
+
+// SeedHistory - change history of event without consensus from historical calendar
+func (m *EconomicMonitor) SeedHistory(event string, country string) error {
+	hList, err := m.Client.CompanyValuation.HistoryEconomicCalendar(objects.RequestHistoryEconomicCalendar{Event: event, Country: country})
+	if err != nil {
+		return errors.Wrap(err, "Error load history economic calendar")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := economicSeriesKey(event, country)
+	for _, h := range hList {
+		m.changes[key] = append(m.changes[key], h.Actual-h.Previous)
+	}
+
+	return nil
+}
+
+// Subscribe - handler is called from Poll for each matching release, returns subscription id
+func (m *EconomicMonitor) Subscribe(filter EconomicSubscription, handler func(objects.EconomicRelease)) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	m.subscriptions[m.nextID] = economicSubscriber{filter: filter, handler: handler}
+
+	return m.nextID
+}
+
+// Unsubscribe ...
+func (m *EconomicMonitor) Unsubscribe(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.subscriptions, id)
+}
+
+// Poll - fetch calendar around now once, score new prints and notify subscribers
+func (m *EconomicMonitor) Poll() ([]objects.EconomicRelease, error) {
+	now := m.now()
+	from, to := now.Add(-m.Config.Window), now.Add(m.Config.Window)
+	calendar, err := m.Client.CompanyValuation.EconomicCalendar(objects.RequestEconomicCalendar{From: &from, To: &to})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load economic calendar")
+	}
+
+	m.mu.Lock()
+	var releaseList []objects.EconomicRelease
+	for _, e := range calendar {
+		key := economicEventKey(e)
+		if e.Actual == nil || m.seen[key] {
+			continue
+		}
+
+		m.seen[key] = true
+		releaseList = append(releaseList, m.score(e))
+		m.record(e)
+	}
+
+	subscribers := make([]economicSubscriber, 0, len(m.subscriptions))
+	for id := 1; id <= m.nextID; id++ {
+		if s, ok := m.subscriptions[id]; ok {
+			subscribers = append(subscribers, s)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, release := range releaseList {
+		for _, s := range subscribers {
+			if s.filter.Match(release) {
+				s.handler(release)
+			}
+		}
+	}
+
+	return releaseList, nil
+}
+
+// Run - poll until context is done, poll errors are logged
+func (m *EconomicMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Poll(); err != nil && m.Client.Logger != nil {
+			m.Client.Logger.Error("Economic calendar poll failed", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Match - release passes filter
+func (f EconomicSubscription) Match(release objects.EconomicRelease) bool {
+	if len(f.CountryList) > 0 && !economicContains(f.CountryList, release.Country, strings.EqualFold) {
+		return false
+	}
+
+	if len(f.ImpactList) > 0 && !economicContains(f.ImpactList, release.Impact, strings.EqualFold) {
+		return false
+	}
+
+	if len(f.EventList) > 0 && !economicContains(f.EventList, release.Event, func(pattern, event string) bool {
+		return strings.Contains(strings.ToLower(event), strings.ToLower(pattern))
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// score - surprise against consensus, or previous print without consensus, normalized by history
+func (m *EconomicMonitor) score(e objects.EconomicCalendar) objects.EconomicRelease {
+	release := objects.EconomicRelease{
+		Event:    e.Event,
+		Country:  e.Country,
+		Date:     e.Date,
+		Actual:   *e.Actual,
+		Estimate: e.Estimate,
+		Previous: e.Previous,
+	}
+
+	if e.Impact != nil {
+		release.Impact = *e.Impact
+	}
+
+	key := economicSeriesKey(e.Event, e.Country)
+	var history []float64
+	switch {
+	case e.Estimate != nil:
+		release.Basis = objects.EconomicSurpriseBasisEstimate
+		release.Surprise = *e.Actual - *e.Estimate
+		history = m.surprises[key]
+	case e.Previous != nil:
+		release.Basis = objects.EconomicSurpriseBasisPrevious
+		release.Surprise = *e.Actual - *e.Previous
+		history = m.changes[key]
+	default:
+		return release
+	}
+
+	release.SampleSize = len(history)
+	if len(history) >= economicMonitorMinSample {
+		if sd := surpriseDeviation(history); sd > 0 {
+			release.SurpriseScore = release.Surprise / sd
+		}
+	}
+
+	return release
+}
+
+func (m *EconomicMonitor) record(e objects.EconomicCalendar) {
+	key := economicSeriesKey(e.Event, e.Country)
+	if e.Estimate != nil {
+		m.surprises[key] = append(m.surprises[key], *e.Actual-*e.Estimate)
+	}
+
+	if e.Previous != nil {
+		m.changes[key] = append(m.changes[key], *e.Actual-*e.Previous)
+	}
+}
+
+// surpriseDeviation - root mean square around zero, surprises are expected to center on zero
+func surpriseDeviation(list []float64) float64 {
+	total := 0.0
+	for _, v := range list {
+		total += v * v
+	}
+
+	return math.Sqrt(total / float64(len(list)))
+}
+
+func economicContains(list []string, value string, match func(pattern, value string) bool) bool {
+	for _, pattern := range list {
+		if match(pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func economicEventKey(e objects.EconomicCalendar) string {
+	return economicSeriesKey(e.Event, e.Country) + "|" + e.Date
+}
+
+func economicSeriesKey(event string, country string) string {
+	return strings.ToLower(event) + "|" + strings.ToUpper(country)
+}
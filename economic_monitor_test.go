@@ -0,0 +1,75 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestEconomicMonitor(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/economic_calendar?from=2021-01-01": `[
+			{"event":"CPI","date":"2021-01-05 12:30:00","country":"US","actual":1.1,"estimate":1.0,"previous":1.0},
+			{"event":"CPI","date":"2021-01-12 12:30:00","country":"US","actual":0.9,"estimate":1.0,"previous":1.1},
+			{"event":"CPI","date":"2021-01-19 12:30:00","country":"US","actual":1.2,"estimate":1.0,"previous":0.9},
+			{"event":"CPI","date":"2021-01-26 12:30:00","country":"US","actual":0.8,"estimate":1.0,"previous":1.2}]`,
+		"/v3/economic_calendar?from=2021-02-09": `[
+			{"event":"CPI","date":"2021-01-26 12:30:00","country":"US","actual":0.8,"estimate":1.0,"previous":1.2},
+			{"event":"CPI","date":"2021-02-10 12:30:00","country":"US","impact":"High","actual":1.5,"estimate":1.0,"previous":0.8},
+			{"event":"Retail Sales","date":"2021-02-10 12:30:00","country":"GB","impact":"Low","actual":2,"previous":1},
+			{"event":"CPI","date":"2021-02-11 12:30:00","country":"US","impact":"High","estimate":1.0}]`,
+		"/v3/historical/economic_calendar/Retail Sales": `[{"event":"Retail Sales","date":"2021-01-01","country":"GB","actual":1,"previous":0}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	monitor := NewEconomicMonitor(APIClient, EconomicMonitorConfig{})
+	monitor.now = func() time.Time { return time.Date(2021, 2, 10, 12, 0, 0, 0, time.UTC) }
+
+	if err := monitor.Seed(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := monitor.SeedHistory("Retail Sales", "GB"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var usHigh, all []objects.EconomicRelease
+	monitor.Subscribe(EconomicSubscription{CountryList: []string{"us"}, EventList: []string{"cpi"}, ImpactList: []string{"High"}}, func(r objects.EconomicRelease) {
+		usHigh = append(usHigh, r)
+	})
+	id := monitor.Subscribe(EconomicSubscription{}, func(r objects.EconomicRelease) { all = append(all, r) })
+
+	releaseList, err := monitor.Poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(releaseList) != 2 || len(usHigh) != 1 || len(all) != 2 {
+		t.Fatalf("unexpected releases: %+v", releaseList)
+	}
+
+	cpi := usHigh[0]
+	expected := 0.5 / math.Sqrt((0.01+0.01+0.04+0.04)/4)
+	if cpi.Basis != objects.EconomicSurpriseBasisEstimate || cpi.SampleSize != 4 || math.Abs(cpi.SurpriseScore-expected) > 1e-9 {
+		t.Fatalf("unexpected CPI release: %+v", cpi)
+	}
+
+	retail := all[1]
+	if retail.Basis != objects.EconomicSurpriseBasisPrevious || retail.Surprise != 1 || retail.SampleSize != 1 || retail.SurpriseScore != 0 {
+		t.Fatalf("unexpected retail release: %+v", retail)
+	}
+
+	monitor.Unsubscribe(id)
+	releaseList, err = monitor.Poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(releaseList) != 0 || len(all) != 2 {
+		t.Fatalf("expected no repeated releases, got %+v", releaseList)
+	}
+}
@@ -13,7 +13,6 @@ const (
 	urlAPIForexListAndQuotes = "/v3/fx"
 	urlAPIForexSymbols       = "/v3/symbol/available-forex-currency-pairs"
 	urlAPIForexQuotes        = "/v3/quotes/forex"
-	urlAPIForexCandles       = "/v3/historical-chart/%s/%s"
 	urlAPIForexDaily         = "/v3/historical-price-full/%s"
 )
 
@@ -67,28 +66,9 @@ func (f *Forex) ListSymbolsAndQuotes() (bList []objects.ForexBindAsk, err error)
 	return bList, nil
 }
 
-// Candles - historical candles
+// Candles - historical candles newest first, same as Candles[objects.ForexCandle]
 func (f *Forex) Candles(req objects.RequestForexCandleList) (cList []objects.ForexCandle, err error) {
-	reqParam := make(map[string]string)
-	if req.From != nil {
-		reqParam["from"] = req.From.Format("2006-01-02")
-	}
-
-	if req.To != nil {
-		reqParam["to"] = req.To.Format("2006-01-02")
-	}
-
-	data, err := f.Client.Get(fmt.Sprintf(urlAPIForexCandles, req.Period, req.Symbol), reqParam)
-	if err != nil {
-		return nil, err
-	}
-
-	err = jsoniter.Unmarshal(data.Body(), &cList)
-	if err != nil {
-		return nil, err
-	}
-
-	return cList, nil
+	return loadTypedCandles[objects.ForexCandle](f.Client, candleRequest(req.Symbol, objects.Resolution(req.Period), req.From, req.To))
 }
 
 // DailyLine - faily line
@@ -0,0 +1,342 @@
+package fmpcloud
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// form13FFilingQuarters - quarters before quarter of date searched for latest filed 13F,
+// holdings are filed up to 45 days after quarter end
+const form13FFilingQuarters = 2
+
+// PositionChanges - position changes of manager for quarters ending at latest quarter filed
+// by date, newest first
+func (f *Form13F) PositionChanges(cik string, date time.Time, quarters int) ([]objects.Form13FReport, error) {
+	if len(cik) == 0 {
+		return nil, errors.New("Error empty cik")
+	}
+
+	if quarters < 1 {
+		return nil, errors.New("Error quarters must be positive")
+	}
+
+	holdingList := make([][]objects.Thirteen, quarters+1)
+	dateList := make([]time.Time, quarters+1)
+	quarterDate, list, err := f.latestThirteen(cik, date)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range holdingList {
+		if i > 0 {
+			if list, err = f.ThirteenList(cik, &quarterDate); err != nil {
+				return nil, errors.Wrapf(err, "Error load 13F for %s", quarterDate.Format(layoutDate))
+			}
+		}
+
+		holdingList[i] = list
+		dateList[i] = quarterDate
+		quarterDate = previousQuarterEnd(quarterDate)
+	}
+
+	symbols := make(map[string]string)
+	reportList := make([]objects.Form13FReport, 0, quarters)
+	for i := 0; i < quarters; i++ {
+		report := DiffThirteen(holdingList[i+1], holdingList[i])
+		report.Cik = cik
+		if len(report.Date) == 0 {
+			report.Date = dateList[i].Format(layoutDate)
+		}
+
+		if len(report.PreviousDate) == 0 {
+			report.PreviousDate = dateList[i+1].Format(layoutDate)
+		}
+
+		if err := f.mapPositionSymbols(report.PositionList, symbols); err != nil {
+			return nil, err
+		}
+
+		reportList = append(reportList, report)
+	}
+
+	return reportList, nil
+}
+
+// ManagersConsensus - latest quarter changes of each manager aggregated per CUSIP
+func (f *Form13F) ManagersConsensus(cikList []string, date time.Time) ([]objects.Form13FConsensus, error) {
+	reportList := make([]objects.Form13FReport, 0, len(cikList))
+	for _, cik := range cikList {
+		list, err := f.PositionChanges(cik, date, 1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load position changes of %s", cik)
+		}
+
+		reportList = append(reportList, list...)
+	}
+
+	return Form13FConsensus(reportList), nil
+}
+
+// latestThirteen - holdings of latest quarter filed by date, empty quarter is not filed yet
+func (f *Form13F) latestThirteen(cik string, date time.Time) (time.Time, []objects.Thirteen, error) {
+	quarterDate := quarterEnd(date)
+	for i := 0; i <= form13FFilingQuarters; i++ {
+		list, err := f.ThirteenList(cik, &quarterDate)
+		if err != nil {
+			return quarterDate, nil, errors.Wrapf(err, "Error load 13F for %s", quarterDate.Format(layoutDate))
+		}
+
+		if len(list) > 0 {
+			return quarterDate, list, nil
+		}
+
+		quarterDate = previousQuarterEnd(quarterDate)
+	}
+
+	return quarterDate, nil, errors.Errorf("Error no 13F of %s filed by %s", cik, date.Format(layoutDate))
+}
+
+// DiffThirteen - position changes between previous and current 13F holdings.
+// Rows of the same CUSIP (discretion lines) are summed, put and call rows are skipped.
+func DiffThirteen(previous, current []objects.Thirteen) objects.Form13FReport {
+	previousHoldings, previousTotal := groupThirteen(previous)
+	currentHoldings, currentTotal := groupThirteen(current)
+
+	report := objects.Form13FReport{
+		TotalValue:         currentTotal,
+		PreviousTotalValue: previousTotal,
+		PositionCount:      len(currentHoldings),
+	}
+
+	if len(current) > 0 {
+		report.Date = current[0].Date
+		report.Cik = current[0].Cik
+	}
+
+	if len(previous) > 0 {
+		report.PreviousDate = previous[0].Date
+	}
+
+	for cusip, row := range currentHoldings {
+		position := objects.Form13FPosition{
+			Cusip:        cusip,
+			Symbol:       row.Tickercusip,
+			NameOfIssuer: row.NameOfIssuer,
+			TitleOfClass: row.TitleOfClass,
+			Shares:       row.Shares,
+			Value:        row.Value,
+			Weight:       float64Ratio(row.Value, currentTotal),
+		}
+
+		if old, ok := previousHoldings[cusip]; ok {
+			position.PreviousShares = old.Shares
+			position.PreviousValue = old.Value
+			position.PreviousWeight = float64Ratio(old.Value, previousTotal)
+		}
+
+		report.PositionList = append(report.PositionList, finishPosition(position))
+	}
+
+	for cusip, old := range previousHoldings {
+		if _, ok := currentHoldings[cusip]; ok {
+			continue
+		}
+
+		report.PositionList = append(report.PositionList, finishPosition(objects.Form13FPosition{
+			Cusip:          cusip,
+			Symbol:         old.Tickercusip,
+			NameOfIssuer:   old.NameOfIssuer,
+			TitleOfClass:   old.TitleOfClass,
+			PreviousShares: old.Shares,
+			PreviousValue:  old.Value,
+			PreviousWeight: float64Ratio(old.Value, previousTotal),
+		}))
+	}
+
+	sort.Slice(report.PositionList, func(i, j int) bool {
+		a, b := report.PositionList[i], report.PositionList[j]
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+
+		if a.PreviousValue != b.PreviousValue {
+			return a.PreviousValue > b.PreviousValue
+		}
+
+		return a.Cusip < b.Cusip
+	})
+
+	for i, p := range report.PositionList {
+		if i < 10 {
+			report.Top10Weight += p.Weight
+		}
+
+		report.Herfindahl += p.Weight * p.Weight
+	}
+
+	return report
+}
+
+// Form13FConsensus - aggregate position changes of several managers per CUSIP,
+// sorted by net buyers and net value
+func Form13FConsensus(reportList []objects.Form13FReport) []objects.Form13FConsensus {
+	index := make(map[string]*objects.Form13FConsensus)
+	for _, report := range reportList {
+		for _, p := range report.PositionList {
+			c, ok := index[p.Cusip]
+			if !ok {
+				c = &objects.Form13FConsensus{Cusip: p.Cusip, Symbol: p.Symbol, NameOfIssuer: p.NameOfIssuer}
+				index[p.Cusip] = c
+			}
+
+			if len(c.Symbol) == 0 {
+				c.Symbol = p.Symbol
+			}
+
+			switch p.Change {
+			case objects.Form13FChangeNew:
+				c.NewCount++
+				c.BuyerList = append(c.BuyerList, report.Cik)
+			case objects.Form13FChangeIncreased:
+				c.BuyerList = append(c.BuyerList, report.Cik)
+			case objects.Form13FChangeClosed:
+				c.ClosedCount++
+				c.SellerList = append(c.SellerList, report.Cik)
+			case objects.Form13FChangeDecreased:
+				c.SellerList = append(c.SellerList, report.Cik)
+			}
+
+			if p.Shares > 0 {
+				c.HolderCount++
+			}
+
+			c.NetShares += p.ShareChange
+			c.NetValue += p.ValueChange
+		}
+	}
+
+	list := make([]objects.Form13FConsensus, 0, len(index))
+	for _, c := range index {
+		c.NetBuyers = len(c.BuyerList) - len(c.SellerList)
+		list = append(list, *c)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].NetBuyers != list[j].NetBuyers {
+			return list[i].NetBuyers > list[j].NetBuyers
+		}
+
+		if list[i].NetValue != list[j].NetValue {
+			return list[i].NetValue > list[j].NetValue
+		}
+
+		return list[i].Cusip < list[j].Cusip
+	})
+
+	return list
+}
+
+// mapPositionSymbols - fill missing tickers with Form13F.CusipMapper, symbols caches resolved CUSIPs
+func (f *Form13F) mapPositionSymbols(positionList []objects.Form13FPosition, symbols map[string]string) error {
+	for i := range positionList {
+		p := &positionList[i]
+		if len(p.Symbol) > 0 {
+			symbols[p.Cusip] = p.Symbol
+			continue
+		}
+
+		symbol, ok := symbols[p.Cusip]
+		if !ok {
+			cusipList, err := f.CusipMapper(p.Cusip)
+			if err != nil {
+				return errors.Wrapf(err, "Error map cusip %s", p.Cusip)
+			}
+
+			if len(cusipList) > 0 {
+				symbol = cusipList[0].Ticker
+			}
+
+			symbols[p.Cusip] = symbol
+		}
+
+		p.Symbol = symbol
+	}
+
+	return nil
+}
+
+func groupThirteen(list []objects.Thirteen) (map[string]objects.Thirteen, int) {
+	holdings := make(map[string]objects.Thirteen, len(list))
+	total := 0
+	for _, row := range list {
+		if isThirteenOption(row) {
+			continue
+		}
+
+		total += row.Value
+		if old, ok := holdings[row.Cusip]; ok {
+			old.Shares += row.Shares
+			old.Value += row.Value
+			holdings[row.Cusip] = old
+			continue
+		}
+
+		holdings[row.Cusip] = row
+	}
+
+	return holdings, total
+}
+
+// isThirteenOption - put or call row, title of class names option type
+func isThirteenOption(row objects.Thirteen) bool {
+	for _, word := range strings.Fields(strings.ToUpper(row.TitleOfClass)) {
+		if word == "PUT" || word == "CALL" || word == "PUTS" || word == "CALLS" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func finishPosition(p objects.Form13FPosition) objects.Form13FPosition {
+	p.ShareChange = p.Shares - p.PreviousShares
+	p.ValueChange = p.Value - p.PreviousValue
+
+	switch {
+	case p.PreviousShares == 0 && p.Shares > 0:
+		p.Change = objects.Form13FChangeNew
+	case p.Shares == 0 && p.PreviousShares > 0:
+		p.Change = objects.Form13FChangeClosed
+	case p.ShareChange > 0:
+		p.Change = objects.Form13FChangeIncreased
+	case p.ShareChange < 0:
+		p.Change = objects.Form13FChangeDecreased
+	default:
+		p.Change = objects.Form13FChangeUnchanged
+	}
+
+	return p
+}
+
+// quarterEnd - last day of calendar quarter containing date
+func quarterEnd(date time.Time) time.Time {
+	month := time.Month((int(date.Month())-1)/3*3 + 3)
+	return time.Date(date.Year(), month+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// previousQuarterEnd - last day of calendar quarter before quarter end date
+func previousQuarterEnd(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month()-2, 0, 0, 0, 0, 0, time.UTC)
+}
+
+func float64Ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
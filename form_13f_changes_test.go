@@ -0,0 +1,83 @@
+package fmpcloud
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestForm13FPositionChanges(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/form-thirteen/0001?date=2021-12-31": `[
+			{"date":"2021-12-31","cik":"0001","cusip":"AAA","tickercusip":"AAA","nameOfIssuer":"A","shares":150,"value":600},
+			{"date":"2021-12-31","cik":"0001","cusip":"BBB","nameOfIssuer":"B","shares":50,"value":300},
+			{"date":"2021-12-31","cik":"0001","cusip":"BBB","nameOfIssuer":"B","shares":10,"value":100},
+			{"date":"2021-12-31","cik":"0001","cusip":"AAA","tickercusip":"AAA","nameOfIssuer":"A","titleOfClass":"CALL","shares":500,"value":2000}]`,
+		"/v3/form-thirteen/0001?date=2021-09-30": `[
+			{"date":"2021-09-30","cik":"0001","cusip":"AAA","tickercusip":"AAA","nameOfIssuer":"A","shares":100,"value":400},
+			{"date":"2021-09-30","cik":"0001","cusip":"CCC","tickercusip":"CCC","nameOfIssuer":"C","shares":20,"value":100}]`,
+		"/v3/form-thirteen/0002?date=2021-12-31": `[{"date":"2021-12-31","cik":"0002","cusip":"AAA","tickercusip":"AAA","shares":10,"value":40}]`,
+		"/v3/form-thirteen/0002?date=2021-09-30": `[
+			{"date":"2021-09-30","cik":"0002","cusip":"AAA","tickercusip":"AAA","shares":5,"value":20},
+			{"date":"2021-09-30","cik":"0002","cusip":"CCC","tickercusip":"CCC","shares":5,"value":25}]`,
+		"/v3/form-thirteen/0001?date=2022-03-31": `[]`,
+		"/v3/form-thirteen/0002?date=2022-03-31": `[]`,
+		"/v3/form-thirteen/0003?date=2021-12-31": `[]`,
+		"/v3/form-thirteen/0003?date=2021-09-30": `[]`,
+		"/v3/form-thirteen/0003?date=2021-06-30": `[]`,
+		"/v3/cusip/BBB":                          `[{"ticker":"BBBX","cusip":"BBB","company":"B"}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	reportList, err := APIClient.Form13F.PositionChanges("0001", time.Date(2021, 11, 15, 0, 0, 0, 0, time.UTC), 1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(reportList) != 1 {
+		t.Fatalf("expected one report, got %d", len(reportList))
+	}
+
+	report := reportList[0]
+	if report.Date != "2021-12-31" || report.PreviousDate != "2021-09-30" || report.TotalValue != 1000 || report.PositionCount != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	expected := []objects.Form13FPosition{
+		{Cusip: "AAA", Symbol: "AAA", Change: objects.Form13FChangeIncreased, ShareChange: 50, ValueChange: 200, Weight: 0.6},
+		{Cusip: "BBB", Symbol: "BBBX", Change: objects.Form13FChangeNew, ShareChange: 60, ValueChange: 400, Weight: 0.4},
+		{Cusip: "CCC", Symbol: "CCC", Change: objects.Form13FChangeClosed, ShareChange: -20, ValueChange: -100},
+	}
+	for i, e := range expected {
+		p := report.PositionList[i]
+		if p.Cusip != e.Cusip || p.Symbol != e.Symbol || p.Change != e.Change || p.ShareChange != e.ShareChange ||
+			p.ValueChange != e.ValueChange || math.Abs(p.Weight-e.Weight) > 1e-9 {
+			t.Fatalf("unexpected position %d: %+v", i, p)
+		}
+	}
+
+	if math.Abs(report.Herfindahl-0.52) > 1e-9 || math.Abs(report.Top10Weight-1) > 1e-9 {
+		t.Fatalf("unexpected concentration: %v %v", report.Herfindahl, report.Top10Weight)
+	}
+
+	// Quarter not filed yet falls back to latest filed quarter
+	consensus, err := APIClient.Form13F.ManagersConsensus([]string{"0001", "0002"}, time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(consensus) != 3 || consensus[0].Cusip != "AAA" || consensus[0].NetBuyers != 2 || consensus[0].NetShares != 55 ||
+		consensus[2].Cusip != "CCC" || consensus[2].ClosedCount != 2 || consensus[2].NetBuyers != -2 {
+		t.Fatalf("unexpected consensus: %+v", consensus)
+	}
+
+	if _, err := APIClient.Form13F.PositionChanges("0003", time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), 1); err == nil ||
+		!strings.Contains(err.Error(), "no 13F") {
+		t.Fatal("expected no filed 13F error")
+	}
+}
@@ -0,0 +1,439 @@
+package fmpcloud
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Fundamentals defaults
+const (
+	FundamentalsTolerance = 0.005 // relative difference allowed between standardized and as-reported values
+	fundamentalsPeriodTTM = "TTM"
+)
+
+// fundamentalsReconcileList - standardized field => as-reported fields in priority order
+var fundamentalsReconcileList = []struct {
+	field    string
+	reported []string
+	abs      bool // compare absolute values, sign conventions differ
+}{
+	{field: "income.revenue", reported: []string{"revenuefromcontractwithcustomerexcludingassessedtax", "revenues", "salesrevenuenet"}},
+	{field: "income.costOfRevenue", reported: []string{"costofgoodsandservicessold", "costofrevenue"}},
+	{field: "income.grossProfit", reported: []string{"grossprofit"}},
+	{field: "income.researchAndDevelopmentExpenses", reported: []string{"researchanddevelopmentexpense"}},
+	{field: "income.sellingGeneralAndAdministrativeExpenses", reported: []string{"sellinggeneralandadministrativeexpense"}},
+	{field: "income.operatingIncome", reported: []string{"operatingincomeloss"}},
+	{field: "income.incomeTaxExpense", reported: []string{"incometaxexpensebenefit"}},
+	{field: "income.netIncome", reported: []string{"netincomeloss"}},
+	{field: "income.eps", reported: []string{"earningspersharebasic"}},
+	{field: "income.epsdiluted", reported: []string{"earningspersharediluted"}},
+	{field: "balanceSheet.cashAndCashEquivalents", reported: []string{"cashandcashequivalentsatcarryingvalue"}},
+	{field: "balanceSheet.totalCurrentAssets", reported: []string{"assetscurrent"}},
+	{field: "balanceSheet.propertyPlantEquipmentNet", reported: []string{"propertyplantandequipmentnet"}},
+	{field: "balanceSheet.totalAssets", reported: []string{"assets"}},
+	{field: "balanceSheet.totalCurrentLiabilities", reported: []string{"liabilitiescurrent"}},
+	{field: "balanceSheet.totalLiabilities", reported: []string{"liabilities"}},
+	{field: "balanceSheet.totalStockholdersEquity", reported: []string{"stockholdersequity"}},
+	{field: "cashFlow.operatingCashFlow", reported: []string{"netcashprovidedbyusedinoperatingactivities"}},
+	{field: "cashFlow.stockBasedCompensation", reported: []string{"sharebasedcompensation"}},
+	{field: "cashFlow.capitalExpenditure", reported: []string{"paymentstoacquirepropertyplantandequipment"}, abs: true},
+	{field: "cashFlow.dividendsPaid", reported: []string{"paymentsofdividends"}, abs: true},
+}
+
+// Fundamentals - merged income, balance sheet and cash flow statements, newest first
+func (c *CompanyValuation) Fundamentals(req objects.RequestFundamentals) ([]objects.Fundamentals, error) {
+	if len(req.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	incomeList, err := c.IncomeStatement(objects.RequestIncomeStatement{Symbol: req.Symbol, Period: req.Period, Limit: req.Limit})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load income statement")
+	}
+
+	balanceList, err := c.BalanceSheetStatement(objects.RequestBalanceSheetStatement{Symbol: req.Symbol, Period: req.Period, Limit: req.Limit})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load balance sheet statement")
+	}
+
+	cashFlowList, err := c.CashFlowStatement(objects.RequestCashFlowStatement{Symbol: req.Symbol, Period: req.Period, Limit: req.Limit})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load cash flow statement")
+	}
+
+	var asReportedList []objects.FullFinancialStatementAsReported
+	if req.AsReported {
+		asReportedList, err = c.FullFinancialStatementAsReported(objects.RequestFullFinancialStatementAsReported{
+			Symbol: req.Symbol, Period: req.Period, Limit: req.Limit,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "Error load full financial statement as reported")
+		}
+	}
+
+	return MergeFundamentals(incomeList, balanceList, cashFlowList, asReportedList), nil
+}
+
+// MergeFundamentals - join statements by symbol, date and period and reconcile with as-reported values
+func MergeFundamentals(
+	incomeList []objects.IncomeStatement,
+	balanceList []objects.BalanceSheetStatement,
+	cashFlowList []objects.CashFlowStatement,
+	asReportedList []objects.FullFinancialStatementAsReported,
+) []objects.Fundamentals {
+	index := make(map[string]*objects.Fundamentals)
+	order := make([]string, 0, len(incomeList))
+
+	record := func(symbol, date, period, currency, fillingDate string) *objects.Fundamentals {
+		key := fundamentalsKey(symbol, date, period)
+		if f, ok := index[key]; ok {
+			return f
+		}
+
+		f := &objects.Fundamentals{Symbol: symbol, Date: date, Period: period, ReportedCurrency: currency, FillingDate: fillingDate}
+		index[key] = f
+		order = append(order, key)
+
+		return f
+	}
+
+	for i := range incomeList {
+		s := incomeList[i]
+		f := record(s.Symbol, s.Date, s.Period, s.ReportedCurrency, s.FillingDate)
+		f.CalendarYear = s.CalendarYear
+		f.Income = &s
+	}
+
+	for i := range balanceList {
+		s := balanceList[i]
+		f := record(s.Symbol, s.Date, s.Period, s.ReportedCurrency, s.FillingDate)
+		f.BalanceSheet = &s
+	}
+
+	for i := range cashFlowList {
+		s := cashFlowList[i]
+		f := record(s.Symbol, s.Date, s.Period, s.ReportedCurrency, s.FillingDate)
+		f.CashFlow = &s
+	}
+
+	// As-reported periods are labelled differently, match by symbol and date only
+	reported := make(map[string]map[string]float64, len(asReportedList))
+	for _, s := range asReportedList {
+		reported[s.Symbol+"|"+s.Date] = asReportedValues(s)
+	}
+
+	list := make([]objects.Fundamentals, 0, len(order))
+	for _, key := range order {
+		f := index[key]
+		if values, ok := reported[f.Symbol+"|"+f.Date]; ok {
+			f.AsReported = values
+			ReconcileFundamentals(f, FundamentalsTolerance)
+		}
+
+		list = append(list, *f)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Symbol != list[j].Symbol {
+			return list[i].Symbol < list[j].Symbol
+		}
+
+		return list[i].Date > list[j].Date
+	})
+
+	return list
+}
+
+// ReconcileFundamentals - compare standardized values with as-reported values, tolerance is relative (0.005 = 0.5%)
+func ReconcileFundamentals(f *objects.Fundamentals, tolerance float64) {
+	f.MismatchList = nil
+	if len(f.AsReported) == 0 {
+		return
+	}
+
+	standardized := fundamentalsValues(*f)
+	for _, r := range fundamentalsReconcileList {
+		value, ok := standardized[r.field]
+		if !ok {
+			continue
+		}
+
+		for _, name := range r.reported {
+			reportedValue, ok := f.AsReported[name]
+			if !ok {
+				continue
+			}
+
+			a, b := value, reportedValue
+			if r.abs {
+				a, b = math.Abs(a), math.Abs(b)
+			}
+
+			if math.Abs(a-b) > tolerance*math.Max(math.Abs(a), math.Abs(b)) {
+				f.MismatchList = append(f.MismatchList, objects.FundamentalsMismatch{
+					Field:        r.field,
+					ReportedName: name,
+					Standardized: value,
+					AsReported:   reportedValue,
+					Difference:   a - b,
+				})
+			}
+
+			break
+		}
+	}
+}
+
+// TTMFundamentals - trailing twelve months from four consecutive quarters, newest first.
+// Flows are summed, balance sheet and share counts are taken from the latest quarter.
+func TTMFundamentals(quarterList []objects.Fundamentals) []objects.Fundamentals {
+	bySymbol := make(map[string][]objects.Fundamentals)
+	symbolList := make([]string, 0)
+	for _, f := range quarterList {
+		if !strings.HasPrefix(f.Period, "Q") || f.Income == nil || f.CashFlow == nil {
+			continue
+		}
+
+		if _, ok := bySymbol[f.Symbol]; !ok {
+			symbolList = append(symbolList, f.Symbol)
+		}
+
+		bySymbol[f.Symbol] = append(bySymbol[f.Symbol], f)
+	}
+
+	sort.Strings(symbolList)
+
+	var list []objects.Fundamentals
+	for _, symbol := range symbolList {
+		quarters := bySymbol[symbol]
+		sort.Slice(quarters, func(i, j int) bool { return quarters[i].Date < quarters[j].Date })
+
+		ttmList := make([]objects.Fundamentals, 0, len(quarters))
+		for i := 3; i < len(quarters); i++ {
+			window := quarters[i-3 : i+1]
+			if !isConsecutiveQuarters(window) {
+				continue
+			}
+
+			ttmList = append(ttmList, ttmFundamentals(window))
+		}
+
+		for i := len(ttmList) - 1; i >= 0; i-- {
+			list = append(list, ttmList[i])
+		}
+	}
+
+	return list
+}
+
+// FundamentalsRestatements - values changed between previous and current fetch of the same periods
+func FundamentalsRestatements(previousList, currentList []objects.Fundamentals) []objects.FundamentalsRestatement {
+	previous := make(map[string]map[string]float64, len(previousList))
+	for _, f := range previousList {
+		previous[fundamentalsKey(f.Symbol, f.Date, f.Period)] = fundamentalsValues(f)
+	}
+
+	var list []objects.FundamentalsRestatement
+	for _, f := range currentList {
+		before, ok := previous[fundamentalsKey(f.Symbol, f.Date, f.Period)]
+		if !ok {
+			continue
+		}
+
+		current := fundamentalsValues(f)
+		names := make([]string, 0, len(current))
+		for name := range current {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			old, ok := before[name]
+			if !ok || !isRestated(old, current[name]) {
+				continue
+			}
+
+			list = append(list, objects.FundamentalsRestatement{
+				Symbol:   f.Symbol,
+				Date:     f.Date,
+				Period:   f.Period,
+				Field:    name,
+				Previous: old,
+				Current:  current[name],
+			})
+		}
+	}
+
+	return list
+}
+
+func ttmFundamentals(window []objects.Fundamentals) objects.Fundamentals {
+	latest := window[len(window)-1]
+
+	incomeList := make([]objects.IncomeStatement, 0, len(window))
+	cashFlowList := make([]objects.CashFlowStatement, 0, len(window))
+	for _, f := range window {
+		incomeList = append(incomeList, *f.Income)
+		cashFlowList = append(cashFlowList, *f.CashFlow)
+	}
+
+	income := sumStatements(incomeList, map[string]bool{"weightedAverageShsOut": true, "weightedAverageShsOutDil": true})
+	income.Period = fundamentalsPeriodTTM
+	income.GrossProfitRatio = floatRatio(income.GrossProfit, income.Revenue)
+	income.Ebitdaratio = floatRatio(income.Ebitda, income.Revenue)
+	income.OperatingIncomeRatio = floatRatio(income.OperatingIncome, income.Revenue)
+	income.IncomeBeforeTaxRatio = floatRatio(income.IncomeBeforeTax, income.Revenue)
+	income.NetIncomeRatio = floatRatio(income.NetIncome, income.Revenue)
+
+	cashFlow := sumStatements(cashFlowList, map[string]bool{"cashAtEndOfPeriod": true, "cashAtBeginningOfPeriod": true})
+	cashFlow.Period = fundamentalsPeriodTTM
+	cashFlow.CashAtBeginningOfPeriod = cashFlowList[0].CashAtBeginningOfPeriod
+
+	ttm := objects.Fundamentals{
+		Symbol:           latest.Symbol,
+		Date:             latest.Date,
+		Period:           fundamentalsPeriodTTM,
+		CalendarYear:     latest.CalendarYear,
+		ReportedCurrency: latest.ReportedCurrency,
+		FillingDate:      latest.FillingDate,
+		Income:           &income,
+		CashFlow:         &cashFlow,
+	}
+
+	if latest.BalanceSheet != nil {
+		balance := *latest.BalanceSheet
+		balance.Period = fundamentalsPeriodTTM
+		ttm.BalanceSheet = &balance
+	}
+
+	return ttm
+}
+
+// sumStatements - copy of the latest statement with float fields summed, except fields in latest
+func sumStatements[T objects.StatementTypes](list []T, latest map[string]bool) T {
+	out := list[len(list)-1]
+	rv := reflect.ValueOf(&out).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Type.Kind() != reflect.Float64 || latest[screenerFieldName(field)] {
+			continue
+		}
+
+		total := 0.0
+		for _, s := range list {
+			total += reflect.ValueOf(s).Field(i).Float()
+		}
+
+		rv.Field(i).SetFloat(total)
+	}
+
+	return out
+}
+
+// isConsecutiveQuarters - four quarter ends spanning about nine months
+func isConsecutiveQuarters(window []objects.Fundamentals) bool {
+	first, err := time.Parse(layoutDate, window[0].Date)
+	if err != nil {
+		return false
+	}
+
+	previous := first
+	for _, f := range window[1:] {
+		date, err := time.Parse(layoutDate, f.Date)
+		if err != nil {
+			return false
+		}
+
+		days := date.Sub(previous).Hours() / 24
+		if days < 75 || days > 105 {
+			return false
+		}
+
+		previous = date
+	}
+
+	return true
+}
+
+// fundamentalsValues - float fields of standardized statements keyed by statement.field
+func fundamentalsValues(f objects.Fundamentals) map[string]float64 {
+	values := make(map[string]float64)
+	add := func(prefix string, statement interface{}) {
+		rv := reflect.ValueOf(statement)
+		if rv.IsNil() {
+			return
+		}
+
+		rv = rv.Elem()
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Field(i).Kind() == reflect.Float64 {
+				values[prefix+"."+screenerFieldName(rv.Type().Field(i))] = rv.Field(i).Float()
+			}
+		}
+	}
+
+	add("income", f.Income)
+	add("balanceSheet", f.BalanceSheet)
+	add("cashFlow", f.CashFlow)
+
+	return values
+}
+
+// asReportedValues - numeric fields of as-reported statement, string and untyped fields may hold numbers.
+// Zero is skipped, typed fields can not tell a missing value from zero.
+func asReportedValues(statement interface{}) map[string]float64 {
+	values := make(map[string]float64)
+	rv := reflect.ValueOf(statement)
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		v := rv.Field(i)
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if v.Float() != 0 {
+				values[screenerFieldName(rt.Field(i))] = v.Float()
+			}
+		case reflect.Interface, reflect.String:
+			if value, ok := asReportedNumber(v.Interface()); ok && value != 0 {
+				values[screenerFieldName(rt.Field(i))] = value
+			}
+		}
+	}
+
+	return values
+}
+
+// asReportedNumber - lists of values (segments, contexts) are ambiguous and skipped
+func asReportedNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func isRestated(previous, current float64) bool {
+	return math.Abs(previous-current) > 1e-9*math.Max(1, math.Max(math.Abs(previous), math.Abs(current)))
+}
+
+func fundamentalsKey(symbol, date, period string) string {
+	return fmt.Sprintf("%s|%s|%s", symbol, date, period)
+}
@@ -0,0 +1,76 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestFundamentals(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/income-statement/TEST": `[
+			{"date":"2021-12-31","symbol":"TEST","period":"Q4","revenue":40,"grossProfit":20,"netIncome":8,"eps":0.8,"weightedAverageShsOut":10},
+			{"date":"2021-09-30","symbol":"TEST","period":"Q3","revenue":30,"grossProfit":15,"netIncome":6,"eps":0.6,"weightedAverageShsOut":11},
+			{"date":"2021-06-30","symbol":"TEST","period":"Q2","revenue":20,"grossProfit":10,"netIncome":4,"eps":0.4,"weightedAverageShsOut":12},
+			{"date":"2021-03-31","symbol":"TEST","period":"Q1","revenue":10,"grossProfit":5,"netIncome":2,"eps":0.2,"weightedAverageShsOut":13}]`,
+		"/v3/balance-sheet-statement/TEST": `[
+			{"date":"2021-12-31","symbol":"TEST","period":"Q4","totalAssets":500,"totalLiabilities":300},
+			{"date":"2021-09-30","symbol":"TEST","period":"Q3","totalAssets":450,"totalLiabilities":280}]`,
+		"/v3/cash-flow-statement/TEST": `[
+			{"date":"2021-12-31","symbol":"TEST","period":"Q4","freeCashFlow":9,"cashAtBeginningOfPeriod":40,"cashAtEndOfPeriod":49},
+			{"date":"2021-09-30","symbol":"TEST","period":"Q3","freeCashFlow":7,"cashAtBeginningOfPeriod":33,"cashAtEndOfPeriod":40},
+			{"date":"2021-06-30","symbol":"TEST","period":"Q2","freeCashFlow":5,"cashAtBeginningOfPeriod":28,"cashAtEndOfPeriod":33},
+			{"date":"2021-03-31","symbol":"TEST","period":"Q1","freeCashFlow":3,"cashAtBeginningOfPeriod":25,"cashAtEndOfPeriod":28}]`,
+		"/v3/financial-statement-full-as-reported/TEST": `[
+			{"date":"2021-12-31","symbol":"TEST","period":"Q4","netincomeloss":8,"grossprofit":"20","revenuefromcontractwithcustomerexcludingassessedtax":"41","liabilities":[300,12]}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	list, err := APIClient.CompanyValuation.Fundamentals(objects.RequestFundamentals{
+		Symbol: "TEST", Period: objects.CompanyValuationPeriodQuarter, Limit: 4, AsReported: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(list) != 4 || list[0].Date != "2021-12-31" || list[0].BalanceSheet == nil || list[2].BalanceSheet != nil {
+		t.Fatalf("unexpected merge: %+v", list)
+	}
+
+	latest := list[0]
+	if latest.AsReported["grossprofit"] != 20 || len(latest.MismatchList) != 1 || latest.MismatchList[0].Field != "income.revenue" {
+		t.Fatalf("unexpected reconciliation: %+v", latest.MismatchList)
+	}
+
+	if _, ok := latest.AsReported["liabilities"]; ok {
+		t.Fatal("expected ambiguous list value to be skipped")
+	}
+
+	ttmList := TTMFundamentals(list)
+	if len(ttmList) != 1 {
+		t.Fatalf("expected one TTM record, got %d", len(ttmList))
+	}
+
+	ttm := ttmList[0]
+	if ttm.Income.Revenue != 100 || ttm.Income.WeightedAverageShsOut != 10 || math.Abs(ttm.Income.Eps-2) > 1e-9 || ttm.Income.GrossProfitRatio != 0.5 {
+		t.Fatalf("unexpected TTM income: %+v", ttm.Income)
+	}
+
+	if ttm.CashFlow.FreeCashFlow != 24 || ttm.CashFlow.CashAtBeginningOfPeriod != 25 || ttm.CashFlow.CashAtEndOfPeriod != 49 || ttm.BalanceSheet.TotalAssets != 500 {
+		t.Fatalf("unexpected TTM cash flow or balance sheet: %+v %+v", ttm.CashFlow, ttm.BalanceSheet)
+	}
+
+	restated := make([]objects.Fundamentals, len(list))
+	copy(restated, list)
+	income := *restated[1].Income
+	income.NetIncome = 5
+	restated[1].Income = &income
+
+	restatementList := FundamentalsRestatements(list, restated)
+	if len(restatementList) != 1 || restatementList[0].Field != "income.netIncome" || restatementList[0].Previous != 6 || restatementList[0].Current != 5 {
+		t.Fatalf("unexpected restatements: %+v", restatementList)
+	}
+}
@@ -0,0 +1,215 @@
+package fmpcloud
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+const layoutIndexEvent = "January 2, 2006"
+
+// IndexMembership - point-in-time index membership built from constituent history
+type IndexMembership struct {
+	Index         objects.Index                     `json:"index"`
+	Intervals     []objects.IndexMembershipInterval `json:"intervals"`
+	TickerChanges []objects.IndexTickerChange       `json:"tickerChanges"`
+}
+
+// IndexMembership - point-in-time membership of index (SP500, Nasdaq, DJ)
+func (s *Stock) IndexMembership(index objects.Index) (*IndexMembership, error) {
+	current, err := s.IndexConstituentList(index)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.HistoryIndexConstituentList(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildIndexMembership(index, current, history), nil
+}
+
+// BuildIndexMembership - replay add/remove events backwards from current members.
+// Events which contradict the replayed state are skipped.
+func BuildIndexMembership(index objects.Index, current []objects.IndexSymbol, history []objects.HistoryIndexSymbol) *IndexMembership {
+	type event struct {
+		date time.Time
+		objects.HistoryIndexSymbol
+	}
+
+	events := make([]event, 0, len(history))
+	for _, h := range history {
+		date, err := parseIndexEventDate(h.DateAdded)
+		if err != nil {
+			continue
+		}
+
+		events = append(events, event{date: date, HistoryIndexSymbol: h})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].date.After(events[j].date)
+	})
+
+	m := &IndexMembership{Index: index}
+
+	// Intervals still open going backwards in time, by symbol
+	active := make(map[string]*objects.IndexMembershipInterval, len(current))
+	for _, c := range current {
+		active[c.Symbol] = &objects.IndexMembershipInterval{Symbol: c.Symbol, Name: c.Name}
+	}
+
+	for _, e := range events {
+		date := e.date
+		added := strings.TrimSpace(e.Symbol)
+		removed := strings.TrimSpace(e.RemovedTicker)
+
+		if interval, ok := active[added]; ok && len(added) > 0 {
+			interval.From = &date
+			m.Intervals = append(m.Intervals, *interval)
+			delete(active, added)
+		}
+
+		if _, ok := active[removed]; !ok && len(removed) > 0 {
+			active[removed] = &objects.IndexMembershipInterval{Symbol: removed, Name: e.RemovedSecurity, To: &date}
+		}
+
+		if len(added) > 0 && len(removed) > 0 && isIndexTickerChange(e.HistoryIndexSymbol) {
+			m.TickerChanges = append(m.TickerChanges, objects.IndexTickerChange{Date: date, OldSymbol: removed, NewSymbol: added})
+		}
+	}
+
+	for _, interval := range active {
+		m.Intervals = append(m.Intervals, *interval)
+	}
+
+	sort.SliceStable(m.Intervals, func(i, j int) bool {
+		if m.Intervals[i].Symbol != m.Intervals[j].Symbol {
+			return m.Intervals[i].Symbol < m.Intervals[j].Symbol
+		}
+
+		return intervalBefore(m.Intervals[i], m.Intervals[j])
+	})
+
+	sort.SliceStable(m.TickerChanges, func(i, j int) bool {
+		return m.TickerChanges[i].Date.Before(m.TickerChanges[j].Date)
+	})
+
+	return m
+}
+
+// ReadIndexMembership - decode membership saved by Write
+func ReadIndexMembership(r io.Reader) (*IndexMembership, error) {
+	var m IndexMembership
+	if err := jsoniter.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Write - encode membership as json for reuse
+func (m *IndexMembership) Write(w io.Writer) error {
+	return jsoniter.NewEncoder(w).Encode(m)
+}
+
+// Members - symbols in index on date
+func (m *IndexMembership) Members(date time.Time) []string {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, interval := range m.Intervals {
+		if intervalContains(interval, date) && !seen[interval.Symbol] {
+			seen[interval.Symbol] = true
+			symbols = append(symbols, interval.Symbol)
+		}
+	}
+
+	sort.Strings(symbols)
+
+	return symbols
+}
+
+// IsMember - symbol was in index on date
+func (m *IndexMembership) IsMember(symbol string, date time.Time) bool {
+	for _, interval := range m.Intervals {
+		if interval.Symbol == symbol && intervalContains(interval, date) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SymbolIntervals - membership intervals of symbol, following ticker changes of the same company
+func (m *IndexMembership) SymbolIntervals(symbol string) (iList []objects.IndexMembershipInterval) {
+	symbols := map[string]bool{symbol: true}
+	for changed := true; changed; {
+		changed = false
+		for _, c := range m.TickerChanges {
+			if symbols[c.NewSymbol] != symbols[c.OldSymbol] {
+				symbols[c.NewSymbol], symbols[c.OldSymbol] = true, true
+				changed = true
+			}
+		}
+	}
+
+	for _, interval := range m.Intervals {
+		if symbols[interval.Symbol] {
+			iList = append(iList, interval)
+		}
+	}
+
+	sort.SliceStable(iList, func(i, j int) bool {
+		return intervalBefore(iList[i], iList[j])
+	})
+
+	return iList
+}
+
+// LatestSymbol - newest ticker of the company after ticker changes
+func (m *IndexMembership) LatestSymbol(symbol string) string {
+	for _, c := range m.TickerChanges {
+		if c.OldSymbol == symbol {
+			symbol = c.NewSymbol
+		}
+	}
+
+	return symbol
+}
+
+// intervalBefore - order by start, open start first
+func intervalBefore(a, b objects.IndexMembershipInterval) bool {
+	if a.From == nil || b.From == nil {
+		return a.From == nil && b.From != nil
+	}
+
+	return a.From.Before(*b.From)
+}
+
+func intervalContains(interval objects.IndexMembershipInterval, date time.Time) bool {
+	if interval.From != nil && date.Before(*interval.From) {
+		return false
+	}
+
+	return interval.To == nil || date.Before(*interval.To)
+}
+
+// isIndexTickerChange - event replaces ticker of the same security
+func isIndexTickerChange(e objects.HistoryIndexSymbol) bool {
+	reason := strings.ToLower(e.Reason)
+	if strings.Contains(reason, "ticker") || strings.Contains(reason, "symbol change") || strings.Contains(reason, "name change") {
+		return true
+	}
+
+	return len(e.AddedSecurity) > 0 && strings.EqualFold(strings.TrimSpace(e.AddedSecurity), strings.TrimSpace(e.RemovedSecurity))
+}
+
+// parseIndexEventDate - parse date of historical index constituent event (July 26, 2017)
+func parseIndexEventDate(s string) (time.Time, error) {
+	return time.Parse(layoutIndexEvent, strings.TrimSpace(s))
+}
@@ -0,0 +1,59 @@
+package fmpcloud
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestIndexMembership(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/sp500_constituent": `[{"symbol":"A","name":"A Corp"},{"symbol":"C","name":"Foo Inc"}]`,
+		"/v3/historical/sp500_constituent": `[
+			{"dateAdded":"June 1, 2021","addedSecurity":"Foo Inc","removedTicker":"B","removedSecurity":"Foo Inc","reason":"","symbol":"C"},
+			{"dateAdded":"March 1, 2020","addedSecurity":"A Corp","removedTicker":"X","removedSecurity":"X Corp","reason":"Market cap change","symbol":"A"}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	membership, err := APIClient.Stock.IndexMembership(objects.IndexSP500)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for date, expected := range map[time.Time][]string{
+		time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC): {"B", "X"},
+		time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC): {"A", "B"},
+		time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC): {"A", "C"},
+	} {
+		if members := membership.Members(date); !reflect.DeepEqual(members, expected) {
+			t.Fatalf("members on %s: expected %v, got %v", date.Format("2006-01-02"), expected, members)
+		}
+	}
+
+	if intervals := membership.SymbolIntervals("C"); len(intervals) != 2 || intervals[0].Symbol != "B" {
+		t.Fatalf("unexpected intervals: %+v", intervals)
+	}
+
+	if membership.LatestSymbol("B") != "C" {
+		t.Fatal("expected ticker change B -> C")
+	}
+
+	var buf bytes.Buffer
+	if err = membership.Write(&buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	restored, err := ReadIndexMembership(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !restored.IsMember("X", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)) || restored.IsMember("X", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("restored membership differs")
+	}
+}
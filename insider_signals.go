@@ -0,0 +1,368 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// insiderRoleWeightList - TypeOfOwner role words => weight, most specific first. Words of a match
+// are not matched again, so "vice president" is not a president, the highest weight is used.
+var insiderRoleWeightList = []struct {
+	keyword string
+	weight  float64
+}{
+	{keyword: "chief executive", weight: 3},
+	{keyword: "chief financial", weight: 2.5},
+	{keyword: "vice chairman", weight: 2},
+	{keyword: "vice president", weight: 1.5},
+	{keyword: "10 percent", weight: 0.75},
+	{keyword: "ceo", weight: 3},
+	{keyword: "cfo", weight: 2.5},
+	{keyword: "chairman", weight: 2.5},
+	{keyword: "president", weight: 2},
+	{keyword: "chief", weight: 2},
+	{keyword: "vp", weight: 1.5},
+	{keyword: "officer", weight: 1.5},
+	{keyword: "director", weight: 1},
+	{keyword: "10%", weight: 0.75},
+}
+
+const insiderDefaultRoleWeight = 0.5
+
+// LoadInsiderTrades - classified insider trades, with close to close returns
+// for each horizon in trading days when horizons are set
+func LoadInsiderTrades(client *APIClient, req objects.RequestInsiderTrading, horizons []int) ([]objects.InsiderTrade, error) {
+	rowList, err := client.InsiderTrading.List(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load insider trading")
+	}
+
+	tradeList := make([]objects.InsiderTrade, 0, len(rowList))
+	for _, row := range rowList {
+		tradeList = append(tradeList, ClassifyInsiderTrade(row))
+	}
+
+	if len(horizons) == 0 {
+		return tradeList, nil
+	}
+
+	bySymbol := make(map[string][]int)
+	from := make(map[string]time.Time)
+	for i, trade := range tradeList {
+		date, err := time.Parse(layoutDate, trade.Date)
+		if err != nil {
+			continue
+		}
+
+		bySymbol[trade.Symbol] = append(bySymbol[trade.Symbol], i)
+		if f, ok := from[trade.Symbol]; !ok || date.Before(f) {
+			from[trade.Symbol] = date
+		}
+	}
+
+	for symbol, indexList := range bySymbol {
+		cList, err := client.Stock.DailySpecificPeriod(symbol, from[symbol], time.Now())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load prices of %s", symbol)
+		}
+
+		var barList []objects.Bar
+		if cList != nil {
+			for _, c := range cList.Historical {
+				date, err := parseBarDate(c.Date)
+				if err != nil {
+					return nil, err
+				}
+
+				price := c.AdjClose
+				if price == 0 {
+					price = c.Close
+				}
+
+				barList = append(barList, objects.Bar{Symbol: symbol, Date: date, Close: price})
+			}
+		}
+
+		symbolTrades := make([]objects.InsiderTrade, 0, len(indexList))
+		for _, i := range indexList {
+			symbolTrades = append(symbolTrades, tradeList[i])
+		}
+
+		symbolTrades = InsiderTradeReturns(symbolTrades, barList, horizons)
+		for n, i := range indexList {
+			tradeList[i] = symbolTrades[n]
+		}
+	}
+
+	return tradeList, nil
+}
+
+// ClassifyInsiderTrade - transaction code, kind, signed shares and role weight of Form 4 row
+func ClassifyInsiderTrade(row objects.InsiderTrading) objects.InsiderTrade {
+	code := strings.ToUpper(strings.TrimSpace(strings.SplitN(row.TransactionType, "-", 2)[0]))
+
+	trade := objects.InsiderTrade{
+		Symbol:        row.Symbol,
+		Date:          row.TransactionDate,
+		ReportingCik:  row.ReportingCik,
+		ReportingName: row.ReportingName,
+		TypeOfOwner:   row.TypeOfOwner,
+		Code:          code,
+		Kind:          insiderTradeKind(code),
+		RoleWeight:    InsiderRoleWeight(row.TypeOfOwner),
+		Shares:        row.SecuritiesTransacted,
+	}
+
+	if strings.EqualFold(row.AcquistionOrDisposition, "D") {
+		trade.Shares = -trade.Shares
+	}
+
+	if row.Price != nil {
+		trade.Price = *row.Price
+	}
+
+	trade.Value = trade.Shares * trade.Price
+
+	return trade
+}
+
+// InsiderRoleWeight - weight of insider role, CEO 3, CFO and chairman 2.5, other officers 1.5 - 2,
+// director 1, 10 percent owner 0.75, unknown 0.5
+func InsiderRoleWeight(typeOfOwner string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(typeOfOwner), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '%'
+	})
+
+	used := make([]bool, len(words))
+	weight := 0.0
+	for _, r := range insiderRoleWeightList {
+		keyword := strings.Fields(r.keyword)
+		for i := 0; i+len(keyword) <= len(words); i++ {
+			if !matchRoleWords(words[i:i+len(keyword)], used[i:i+len(keyword)], keyword) {
+				continue
+			}
+
+			for j := range keyword {
+				used[i+j] = true
+			}
+
+			weight = math.Max(weight, r.weight)
+		}
+	}
+
+	if weight == 0 {
+		return insiderDefaultRoleWeight
+	}
+
+	return weight
+}
+
+// matchRoleWords - words equal keyword and none is used by a more specific role
+func matchRoleWords(words []string, used []bool, keyword []string) bool {
+	for i, k := range keyword {
+		if used[i] || words[i] != k {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterInsiderTrades - trades of given kinds, e.g. purchases and sales without option exercises
+func FilterInsiderTrades(tradeList []objects.InsiderTrade, kinds ...objects.InsiderTradeKind) []objects.InsiderTrade {
+	var list []objects.InsiderTrade
+	for _, trade := range tradeList {
+		for _, kind := range kinds {
+			if trade.Kind == kind {
+				list = append(list, trade)
+				break
+			}
+		}
+	}
+
+	return list
+}
+
+// InsiderNetBuying - open market purchases and sales per symbol between from and to inclusive
+func InsiderNetBuying(tradeList []objects.InsiderTrade, from, to time.Time) []objects.InsiderNetActivity {
+	fromDate, toDate := from.Format(layoutDate), to.Format(layoutDate)
+
+	index := make(map[string]*objects.InsiderNetActivity)
+	insiders := make(map[string]map[string]bool)
+	for _, trade := range tradeList {
+		if trade.Date < fromDate || trade.Date > toDate {
+			continue
+		}
+
+		if trade.Kind != objects.InsiderTradeKindPurchase && trade.Kind != objects.InsiderTradeKindSale {
+			continue
+		}
+
+		a, ok := index[trade.Symbol]
+		if !ok {
+			a = &objects.InsiderNetActivity{Symbol: trade.Symbol, From: fromDate, To: toDate}
+			index[trade.Symbol] = a
+			insiders[trade.Symbol] = make(map[string]bool)
+		}
+
+		insiders[trade.Symbol][insiderKey(trade)] = true
+		if trade.Kind == objects.InsiderTradeKindPurchase {
+			a.BuyCount++
+			a.BuyShares += trade.Shares
+			a.BuyValue += trade.Value
+		} else {
+			a.SellCount++
+			a.SellShares -= trade.Shares
+			a.SellValue -= trade.Value
+		}
+
+		a.NetShares += trade.Shares
+		a.NetValue += trade.Value
+		a.WeightedNetValue += trade.Value * trade.RoleWeight
+	}
+
+	list := make([]objects.InsiderNetActivity, 0, len(index))
+	for symbol, a := range index {
+		a.InsiderCount = len(insiders[symbol])
+		list = append(list, *a)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].WeightedNetValue != list[j].WeightedNetValue {
+			return list[i].WeightedNetValue > list[j].WeightedNetValue
+		}
+
+		return list[i].Symbol < list[j].Symbol
+	})
+
+	return list
+}
+
+// InsiderClusterBuys - open market purchases of at least minInsiders distinct insiders within days calendar days.
+// Clusters of one symbol do not overlap.
+func InsiderClusterBuys(tradeList []objects.InsiderTrade, days int, minInsiders int) []objects.InsiderCluster {
+	bySymbol := make(map[string][]objects.InsiderTrade)
+	for _, trade := range tradeList {
+		if trade.Kind == objects.InsiderTradeKindPurchase {
+			bySymbol[trade.Symbol] = append(bySymbol[trade.Symbol], trade)
+		}
+	}
+
+	symbolList := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbolList = append(symbolList, symbol)
+	}
+
+	sort.Strings(symbolList)
+
+	var list []objects.InsiderCluster
+	for _, symbol := range symbolList {
+		purchases := bySymbol[symbol]
+		sort.SliceStable(purchases, func(i, j int) bool { return purchases[i].Date < purchases[j].Date })
+
+		for i := 0; i < len(purchases); {
+			start, err := time.Parse(layoutDate, purchases[i].Date)
+			if err != nil {
+				i++
+				continue
+			}
+
+			end := start.AddDate(0, 0, days).Format(layoutDate)
+			j := i
+			insiders := make(map[string]bool)
+			for j < len(purchases) && purchases[j].Date <= end {
+				insiders[insiderKey(purchases[j])] = true
+				j++
+			}
+
+			if len(insiders) < minInsiders {
+				i++
+				continue
+			}
+
+			cluster := objects.InsiderCluster{Symbol: symbol, From: purchases[i].Date, To: purchases[j-1].Date}
+			seen := make(map[string]bool)
+			for _, trade := range purchases[i:j] {
+				if !seen[insiderKey(trade)] {
+					seen[insiderKey(trade)] = true
+					cluster.InsiderList = append(cluster.InsiderList, trade.ReportingName)
+				}
+
+				cluster.Shares += trade.Shares
+				cluster.Value += trade.Value
+				cluster.WeightedValue += trade.Value * trade.RoleWeight
+				cluster.TradeList = append(cluster.TradeList, trade)
+			}
+
+			list = append(list, cluster)
+			i = j
+		}
+	}
+
+	return list
+}
+
+// InsiderTradeReturns - returns after each trade from close of trade date
+// (or next trading day) to close horizons trading days later, bars of one symbol
+func InsiderTradeReturns(tradeList []objects.InsiderTrade, barList []objects.Bar, horizons []int) []objects.InsiderTrade {
+	bars := make([]objects.Bar, len(barList))
+	copy(bars, barList)
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date.Before(bars[j].Date) })
+
+	list := make([]objects.InsiderTrade, len(tradeList))
+	for n, trade := range tradeList {
+		trade.ReturnList = nil
+		date, err := time.Parse(layoutDate, trade.Date)
+		if err == nil {
+			entry := sort.Search(len(bars), func(i int) bool { return !bars[i].Date.Before(date) })
+			for _, days := range horizons {
+				exit := entry + days
+				if entry >= len(bars) || exit >= len(bars) || bars[entry].Close == 0 {
+					continue
+				}
+
+				trade.ReturnList = append(trade.ReturnList, objects.InsiderReturn{
+					Days:   days,
+					Return: bars[exit].Close/bars[entry].Close - 1,
+				})
+			}
+		}
+
+		list[n] = trade
+	}
+
+	return list
+}
+
+func insiderTradeKind(code string) objects.InsiderTradeKind {
+	switch code {
+	case "P":
+		return objects.InsiderTradeKindPurchase
+	case "S":
+		return objects.InsiderTradeKindSale
+	case "M", "X", "C", "O":
+		return objects.InsiderTradeKindOptionExercise
+	case "A":
+		return objects.InsiderTradeKindAward
+	case "F":
+		return objects.InsiderTradeKindTaxWithholding
+	case "G":
+		return objects.InsiderTradeKindGift
+	}
+
+	return objects.InsiderTradeKindOther
+}
+
+func insiderKey(trade objects.InsiderTrade) string {
+	if len(trade.ReportingCik) > 0 {
+		return trade.ReportingCik
+	}
+
+	return trade.ReportingName
+}
@@ -0,0 +1,72 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestInsiderSignals(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v4/insider-trading": `[
+			{"symbol":"TEST","transactionDate":"2021-03-01","reportingCik":"1","reportingName":"Ceo","typeOfOwner":"director, officer: Chief Executive Officer","transactionType":"P-Purchase","acquistionOrDisposition":"A","securitiesTransacted":100,"price":10},
+			{"symbol":"TEST","transactionDate":"2021-03-03","reportingCik":"2","reportingName":"Dir","typeOfOwner":"director","transactionType":"P-Purchase","acquistionOrDisposition":"A","securitiesTransacted":50,"price":10},
+			{"symbol":"TEST","transactionDate":"2021-03-04","reportingCik":"3","reportingName":"Cfo","typeOfOwner":"officer: CFO","transactionType":"M-Exempt","acquistionOrDisposition":"A","securitiesTransacted":500,"price":1},
+			{"symbol":"TEST","transactionDate":"2021-03-20","reportingCik":"4","reportingName":"Owner","typeOfOwner":"10 percent owner","transactionType":"S-Sale","acquistionOrDisposition":"D","securitiesTransacted":20,"price":12}]`,
+		"/v3/historical-price-full/TEST": `{"symbol":"TEST","historical":[
+			{"date":"2021-03-05","close":12,"adjClose":12},
+			{"date":"2021-03-02","close":11,"adjClose":11},
+			{"date":"2021-03-01","close":10,"adjClose":10},
+			{"date":"2021-03-04","close":12,"adjClose":12},
+			{"date":"2021-03-03","close":10.5,"adjClose":10.5}]}`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tradeList, err := LoadInsiderTrades(APIClient, objects.RequestInsiderTrading{Symbol: "TEST"}, []int{1, 3})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ceo := tradeList[0]
+	if ceo.Kind != objects.InsiderTradeKindPurchase || ceo.RoleWeight != 3 || ceo.Value != 1000 || len(ceo.ReturnList) != 2 || math.Abs(ceo.ReturnList[1].Return-0.2) > 1e-9 {
+		t.Fatalf("unexpected trade: %+v", ceo)
+	}
+
+	if tradeList[2].Kind != objects.InsiderTradeKindOptionExercise || tradeList[3].Shares != -20 || tradeList[3].RoleWeight != 0.75 {
+		t.Fatalf("unexpected classification: %+v", tradeList)
+	}
+
+	if len(FilterInsiderTrades(tradeList, objects.InsiderTradeKindPurchase, objects.InsiderTradeKindSale)) != 3 {
+		t.Fatal("expected option exercise to be filtered")
+	}
+
+	activity := InsiderNetBuying(tradeList, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC))
+	if len(activity) != 1 || activity[0].NetValue != 1260 || activity[0].InsiderCount != 3 || activity[0].WeightedNetValue != 3000+500-180 {
+		t.Fatalf("unexpected net buying: %+v", activity)
+	}
+
+	clusters := InsiderClusterBuys(tradeList, 5, 2)
+	if len(clusters) != 1 || len(clusters[0].InsiderList) != 2 || clusters[0].To != "2021-03-03" || clusters[0].Value != 1500 {
+		t.Fatalf("unexpected clusters: %+v", clusters)
+	}
+}
+
+func TestInsiderRoleWeight(t *testing.T) {
+	for owner, weight := range map[string]float64{
+		"officer: Vice President":             1.5,
+		"officer: Senior Vice President, CFO": 2.5,
+		"officer: President and CEO":          3,
+		"officer: President":                  2,
+		"director, Vice Chairman":             2,
+		"10% owner":                           0.75,
+		"other: Mischief Maker":               0.5,
+	} {
+		if got := InsiderRoleWeight(owner); got != weight {
+			t.Fatalf("expected %v for %s, got %v", weight, owner, got)
+		}
+	}
+}
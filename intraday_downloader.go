@@ -0,0 +1,423 @@
+package fmpcloud
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+	"golang.org/x/time/rate"
+)
+
+// Intraday downloader defaults
+const (
+	intradayDownloaderConcurrency = 4
+	intradayDownloaderRetries     = 3
+	intradayDownloaderRetryWait   = time.Second
+)
+
+// resolutionStep - duration of intraday bar
+var resolutionStep = map[objects.Resolution]time.Duration{
+	objects.Resolution1Min:  time.Minute,
+	objects.Resolution5Min:  5 * time.Minute,
+	objects.Resolution15Min: 15 * time.Minute,
+	objects.Resolution30Min: 30 * time.Minute,
+	objects.Resolution1Hour: time.Hour,
+	objects.Resolution4Hour: 4 * time.Hour,
+}
+
+// IntradayDownloaderConfig for create intraday downloader
+type IntradayDownloaderConfig struct {
+	Symbol        string
+	Resolution    objects.Resolution
+	From          time.Time
+	To            time.Time
+	Exchange      string           // calendar of bar time zone and gap detection, empty skips gaps in America/New_York
+	Calendar      *TradingCalendar // default NewTradingCalendar
+	WindowDays    int              // days of one request, default by resolution
+	Concurrency   int              // parallel requests, default 4
+	Retries       int              // attempts of window, default 3
+	RetryWait     time.Duration    // wait before retry multiplied by attempt, default 1s
+	RateLimiter   *rate.Limiter    // optional limit of window requests
+	CheckpointDir string           // completed windows are stored and reused, empty disables
+}
+
+// IntradayDownloader - long range intraday history in API sized windows
+type IntradayDownloader struct {
+	Client *APIClient
+	Config IntradayDownloaderConfig
+
+	now      func() time.Time
+	exchange *ExchangeCalendar
+	location *time.Location
+}
+
+// NewIntradayDownloader ...
+func NewIntradayDownloader(client *APIClient, cfg IntradayDownloaderConfig) (*IntradayDownloader, error) {
+	if len(cfg.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	if _, ok := resolutionStep[cfg.Resolution]; !ok {
+		return nil, errors.Errorf("Error unknown intraday resolution %s", cfg.Resolution)
+	}
+
+	if cfg.From.IsZero() || cfg.To.Before(cfg.From) {
+		return nil, errors.New("Error invalid intraday download period")
+	}
+
+	if cfg.WindowDays <= 0 {
+		cfg.WindowDays = candleMaxDays[cfg.Resolution]
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = intradayDownloaderConcurrency
+	}
+
+	if cfg.Retries <= 0 {
+		cfg.Retries = intradayDownloaderRetries
+	}
+
+	if cfg.RetryWait <= 0 {
+		cfg.RetryWait = intradayDownloaderRetryWait
+	}
+
+	location, err := candleLocation(objects.RequestCandles{Resolution: cfg.Resolution})
+	if err != nil {
+		return nil, err
+	}
+
+	d := &IntradayDownloader{Client: client, Config: cfg, now: time.Now, location: location}
+	if len(cfg.Exchange) > 0 {
+		if cfg.Calendar == nil {
+			calendar, err := NewTradingCalendar()
+			if err != nil {
+				return nil, err
+			}
+
+			d.Config.Calendar = calendar
+		}
+
+		exchange, err := d.Config.Calendar.Exchange(cfg.Exchange)
+		if err != nil {
+			return nil, err
+		}
+
+		d.exchange = exchange
+		d.location = exchange.Location
+	}
+
+	if len(cfg.CheckpointDir) > 0 {
+		if err := os.MkdirAll(cfg.CheckpointDir, 0o755); err != nil {
+			return nil, errors.Wrap(err, "Error create intraday checkpoint")
+		}
+	}
+
+	return d, nil
+}
+
+// Download - load windows missing from checkpoint, reload windows with gaps once and stitch bars.
+// Windows are stored as soon as loaded, result holds loaded bars even when some windows failed
+// after all retries or context was cancelled.
+func (d *IntradayDownloader) Download(ctx context.Context) (*objects.IntradayDownload, error) {
+	windows := d.windows()
+	barLists := make([][]objects.Bar, len(windows))
+
+	var pending []int
+	for i := range windows {
+		if bars, ok := d.readCheckpoint(windows[i]); ok {
+			barLists[i] = bars
+			windows[i].Status = objects.IntradayWindowDone
+			windows[i].Bars = len(bars)
+			windows[i].Checkpoint = true
+			continue
+		}
+
+		pending = append(pending, i)
+	}
+
+	if err := d.fetch(ctx, windows, barLists, pending); err != nil {
+		return nil, err
+	}
+
+	// Windows with missing bars are loaded again and merged
+	if d.exchange != nil {
+		retry := make(map[int]bool)
+		for _, gap := range d.gaps(stitchBars(barLists)) {
+			for i, w := range windows {
+				if !w.Checkpoint && w.Status == objects.IntradayWindowDone && w.Attempts < d.Config.Retries &&
+					gap.Session >= w.From && gap.Session <= w.To {
+					retry[i] = true
+				}
+			}
+		}
+
+		var retryList []int
+		for i := range retry {
+			retryList = append(retryList, i)
+		}
+
+		sort.Ints(retryList)
+
+		previous := make(map[int][]objects.Bar, len(retryList))
+		for _, i := range retryList {
+			previous[i] = barLists[i]
+		}
+
+		// Failed reload keeps bars of first load
+		if err := d.fetch(ctx, windows, barLists, retryList); err != nil {
+			return nil, err
+		}
+
+		for _, i := range retryList {
+			barLists[i] = stitchBars([][]objects.Bar{previous[i], barLists[i]})
+			windows[i].Status = objects.IntradayWindowDone
+			windows[i].Bars = len(barLists[i])
+			windows[i].Error = ""
+			if err := d.writeCheckpoint(windows[i], barLists[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	failed := 0
+	for _, w := range windows {
+		if w.Status == objects.IntradayWindowFailed {
+			failed++
+		}
+	}
+
+	result := &objects.IntradayDownload{
+		Symbol:     d.Config.Symbol,
+		Resolution: d.Config.Resolution,
+		Bars:       stitchBars(barLists),
+		Windows:    windows,
+	}
+
+	if d.exchange != nil {
+		result.Gaps = d.gaps(result.Bars)
+	}
+
+	if failed > 0 {
+		return result, errors.Errorf("Error download %d of %d windows of %s", failed, len(windows), d.Config.Symbol)
+	}
+
+	return result, nil
+}
+
+// windows - consecutive date ranges of WindowDays
+func (d *IntradayDownloader) windows() []objects.IntradayWindow {
+	var list []objects.IntradayWindow
+	to := d.Config.To.Format(layoutDate)
+	for start := d.Config.From; start.Format(layoutDate) <= to; start = start.AddDate(0, 0, d.Config.WindowDays) {
+		end := start.AddDate(0, 0, d.Config.WindowDays-1).Format(layoutDate)
+		if end > to {
+			end = to
+		}
+
+		list = append(list, objects.IntradayWindow{From: start.Format(layoutDate), To: end, Status: objects.IntradayWindowPending})
+	}
+
+	return list
+}
+
+// fetch - load windows of indexes concurrently and store each loaded window,
+// failed windows are retried with growing wait
+func (d *IntradayDownloader) fetch(ctx context.Context, windows []objects.IntradayWindow, barLists [][]objects.Bar, indexes []int) error {
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		writeErr error
+	)
+
+	for w := 0; w < d.Config.Concurrency && w < len(indexes); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				bars, attempts, err := d.fetchWindow(ctx, windows[i])
+				windows[i].Attempts += attempts
+				if err != nil {
+					windows[i].Status = objects.IntradayWindowFailed
+					windows[i].Error = err.Error()
+					continue
+				}
+
+				barLists[i] = bars
+				windows[i].Status = objects.IntradayWindowDone
+				windows[i].Bars = len(bars)
+				windows[i].Error = ""
+				if err := d.writeCheckpoint(windows[i], bars); err != nil {
+					mu.Lock()
+					writeErr = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, i := range indexes {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return writeErr
+}
+
+func (d *IntradayDownloader) fetchWindow(ctx context.Context, w objects.IntradayWindow) ([]objects.Bar, int, error) {
+	from, _ := time.Parse(layoutDate, w.From)
+	to, _ := time.Parse(layoutDate, w.To)
+
+	var err error
+	for attempt := 1; attempt <= d.Config.Retries; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, attempt - 1, ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * d.Config.RetryWait):
+			}
+		}
+
+		if d.Config.RateLimiter != nil {
+			if err := d.Config.RateLimiter.Wait(ctx); err != nil {
+				return nil, attempt - 1, err
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, attempt - 1, err
+		}
+
+		var cList []objects.StockCandle
+		cList, err = loadCandleChunk(d.Client.Stock.Client, d.Config.Symbol, d.Config.Resolution, &from, &to)
+		if err == nil {
+			bars, err := candleBars(d.Config.Symbol, cList, d.location)
+			return bars, attempt, err
+		}
+	}
+
+	return nil, d.Config.Retries, errors.Wrapf(err, "Error load %s window %s %s", d.Config.Symbol, w.From, w.To)
+}
+
+// gaps - expected bars of sessions up to now missing from bars
+func (d *IntradayDownloader) gaps(bars []objects.Bar) []objects.IntradayGap {
+	have := make(map[int64]bool, len(bars))
+	for _, bar := range bars {
+		have[bar.Date.Unix()] = true
+	}
+
+	step := resolutionStep[d.Config.Resolution]
+	now := d.now()
+
+	var gapList []objects.IntradayGap
+	// Dates of config are calendar days, not instants of exchange time zone
+	from := time.Date(d.Config.From.Year(), d.Config.From.Month(), d.Config.From.Day(), 12, 0, 0, 0, d.location)
+	to := time.Date(d.Config.To.Year(), d.Config.To.Month(), d.Config.To.Day(), 12, 0, 0, 0, d.location)
+	for _, s := range d.exchange.Sessions(from, to) {
+		var gap *objects.IntradayGap
+		for t := s.Open; t.Before(s.Close) && t.Add(step).Before(now); t = t.Add(step) {
+			if have[t.Unix()] {
+				if gap != nil {
+					gapList = append(gapList, *gap)
+					gap = nil
+				}
+
+				continue
+			}
+
+			if gap == nil {
+				gap = &objects.IntradayGap{Session: s.Date, From: t}
+			}
+
+			gap.To = t
+			gap.Bars++
+		}
+
+		if gap != nil {
+			gapList = append(gapList, *gap)
+		}
+	}
+
+	return gapList
+}
+
+// checkpointPath - file of window bars
+func (d *IntradayDownloader) checkpointPath(w objects.IntradayWindow) string {
+	symbol := strings.NewReplacer("/", "_", "\\", "_").Replace(d.Config.Symbol)
+	return filepath.Join(d.Config.CheckpointDir, fmt.Sprintf("%s_%s_%s_%s.json", symbol, d.Config.Resolution, w.From, w.To))
+}
+
+func (d *IntradayDownloader) readCheckpoint(w objects.IntradayWindow) ([]objects.Bar, bool) {
+	if len(d.Config.CheckpointDir) == 0 {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(d.checkpointPath(w))
+	if err != nil {
+		return nil, false
+	}
+
+	var bars []objects.Bar
+	if err := jsoniter.Unmarshal(data, &bars); err != nil {
+		return nil, false
+	}
+
+	for i := range bars {
+		bars[i].Date = bars[i].Date.In(d.location)
+	}
+
+	return bars, true
+}
+
+// writeCheckpoint - store window, windows reaching today are still growing and not stored
+func (d *IntradayDownloader) writeCheckpoint(w objects.IntradayWindow, bars []objects.Bar) error {
+	if len(d.Config.CheckpointDir) == 0 || w.To >= d.now().In(d.location).Format(layoutDate) {
+		return nil
+	}
+
+	data, err := jsoniter.Marshal(bars)
+	if err != nil {
+		return errors.Wrap(err, "Error write intraday checkpoint")
+	}
+
+	// Write to temp file first so a crash never leaves partial window
+	path := d.checkpointPath(w)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return errors.Wrap(err, "Error write intraday checkpoint")
+	}
+
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return errors.Wrap(err, "Error write intraday checkpoint")
+	}
+
+	return nil
+}
+
+// stitchBars - bars of lists oldest first without duplicate dates, later lists win
+func stitchBars(barLists [][]objects.Bar) []objects.Bar {
+	byDate := make(map[int64]objects.Bar)
+	for _, bars := range barLists {
+		for _, bar := range bars {
+			byDate[bar.Date.UnixNano()] = bar
+		}
+	}
+
+	bars := make([]objects.Bar, 0, len(byDate))
+	for _, bar := range byDate {
+		bars = append(bars, bar)
+	}
+
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date.Before(bars[j].Date) })
+
+	return bars
+}
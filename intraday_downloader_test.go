@@ -0,0 +1,172 @@
+package fmpcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestIntradayDownloader(t *testing.T) {
+	// Early close session of 2021-11-26 misses 11:30 bar
+	first := `[{"date":"2021-11-26 12:30:00","close":4},{"date":"2021-11-26 10:30:00","close":2},{"date":"2021-11-26 09:30:00","close":1}]`
+	second := `[{"date":"2021-11-29 15:30:00","close":17},{"date":"2021-11-29 14:30:00","close":16},{"date":"2021-11-29 13:30:00","close":15},
+		{"date":"2021-11-29 12:30:00","close":14},{"date":"2021-11-29 11:30:00","close":13},{"date":"2021-11-29 10:30:00","close":12},
+		{"date":"2021-11-29 09:30:00","close":11},{"date":"2021-11-29 09:30:00","close":11}]`
+
+	cfg := IntradayDownloaderConfig{
+		Symbol:        "AAPL",
+		Resolution:    objects.Resolution1Hour,
+		From:          time.Date(2021, 11, 26, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2021, 11, 29, 0, 0, 0, 0, time.UTC),
+		Exchange:      TradingExchangeNASDAQ,
+		WindowDays:    2,
+		Retries:       2,
+		RetryWait:     time.Millisecond,
+		CheckpointDir: t.TempDir(),
+	}
+
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-26&to=2021-11-27": first,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	downloader, err := NewIntradayDownloader(APIClient, cfg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err := downloader.Download(context.Background())
+	if err == nil || result == nil {
+		t.Fatal("expected failed window error")
+	}
+
+	if w := result.Windows[1]; w.Status != objects.IntradayWindowFailed || w.Attempts != 2 {
+		t.Fatalf("unexpected failed window: %+v", w)
+	}
+
+	// Gap of first window is loaded again once
+	if w := result.Windows[0]; w.Status != objects.IntradayWindowDone || w.Attempts != 2 || w.Bars != 3 {
+		t.Fatalf("unexpected first window: %+v", w)
+	}
+
+	// Resume loads first window from checkpoint
+	APIClient, err = NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-28&to=2021-11-29": second,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	downloader, err = NewIntradayDownloader(APIClient, cfg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err = downloader.Download(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !result.Windows[0].Checkpoint || result.Windows[1].Checkpoint || len(result.Bars) != 10 {
+		t.Fatalf("unexpected resumed download: %+v", result.Windows)
+	}
+
+	if result.Bars[0].Close != 1 || result.Bars[9].Close != 17 || result.Bars[0].Date.Location() != downloader.location ||
+		result.Bars[0].Date.UTC().Hour() != 14 {
+		t.Fatalf("unexpected bars: %+v", result.Bars)
+	}
+
+	if len(result.Gaps) != 1 || result.Gaps[0].Session != "2021-11-26" || result.Gaps[0].Bars != 1 || result.Gaps[0].From.Hour() != 11 {
+		t.Fatalf("unexpected gaps: %+v", result.Gaps)
+	}
+
+	if _, err := NewIntradayDownloader(APIClient, IntradayDownloaderConfig{Symbol: "AAPL", Resolution: objects.Resolution1Day,
+		From: cfg.From, To: cfg.To}); err == nil {
+		t.Fatal("expected daily resolution error")
+	}
+}
+
+func TestIntradayDownloaderCancel(t *testing.T) {
+	routes := map[string]string{
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-22&to=2021-11-22": `[{"date":"2021-11-22 09:30:00","close":1}]`,
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-23&to=2021-11-23": `[{"date":"2021-11-23 09:30:00","close":2}]`,
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-24&to=2021-11-24": `[{"date":"2021-11-24 09:30:00","close":3}]`,
+	}
+
+	cfg := IntradayDownloaderConfig{
+		Symbol:        "AAPL",
+		Resolution:    objects.Resolution1Hour,
+		From:          time.Date(2021, 11, 22, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2021, 11, 24, 0, 0, 0, 0, time.UTC),
+		WindowDays:    1,
+		Concurrency:   1,
+		Retries:       1,
+		CheckpointDir: t.TempDir(),
+	}
+
+	// Context is cancelled once first window is served
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer cancel()
+
+		body, ok := mockRoute(routes, r.URL)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	APIClient, err := NewAPIClient(Config{APIUrl: APIUrl(server.URL), Timeout: 5})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	downloader, err := NewIntradayDownloader(APIClient, cfg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err := downloader.Download(ctx)
+	if err == nil || result == nil || result.Windows[0].Status != objects.IntradayWindowDone ||
+		result.Windows[1].Status != objects.IntradayWindowFailed || result.Windows[2].Status != objects.IntradayWindowFailed {
+		t.Fatalf("unexpected cancelled download: %+v %v", result, err)
+	}
+
+	// Resume loads only windows missing from checkpoint
+	delete(routes, "/v3/historical-chart/1hour/AAPL?from=2021-11-22&to=2021-11-22")
+	APIClient, err = NewAPIClient(testCaseMockConfig(t, routes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	downloader, err = NewIntradayDownloader(APIClient, cfg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err = downloader.Download(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !result.Windows[0].Checkpoint || result.Windows[1].Checkpoint || result.Windows[2].Checkpoint || len(result.Bars) != 3 {
+		t.Fatalf("unexpected resumed download: %+v", result.Windows)
+	}
+
+	// Without exchange bars are wall clock of default candle time zone
+	if result.Bars[0].Close != 1 || result.Bars[0].Date.Location().String() != candleDefaultLocation || result.Bars[0].Date.UTC().Hour() != 14 {
+		t.Fatalf("unexpected bars: %+v", result.Bars)
+	}
+}
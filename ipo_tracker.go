@@ -0,0 +1,304 @@
+package fmpcloud
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// IPO tracker defaults
+const (
+	ipoCalendarMaxDays = 90 // maximum range of IPO calendar endpoints
+	ipoPerformanceDays = 90
+)
+
+// ipoStatusRank - forward order of statuses, transitions never go back. Postponed has no rank,
+// it pauses record at reached stage
+var ipoStatusRank = map[objects.IPOStatus]int{
+	objects.IPOStatusFiled:     1,
+	objects.IPOStatusConfirmed: 2,
+	objects.IPOStatusPriced:    3,
+	objects.IPOStatusListed:    4,
+	objects.IPOStatusWithdrawn: 5,
+}
+
+// IPOTrackerConfig for create IPO tracker
+type IPOTrackerConfig struct {
+	From time.Time
+	To   time.Time
+}
+
+// IPOTracker - merged IPO pipeline, Refresh updates statuses and performance incrementally
+type IPOTracker struct {
+	Client *APIClient
+	Config IPOTrackerConfig
+
+	mu      sync.Mutex
+	now     func() time.Time
+	records map[string]*objects.IPORecord // symbol or company
+}
+
+// NewIPOTracker ...
+func NewIPOTracker(client *APIClient, cfg IPOTrackerConfig) (*IPOTracker, error) {
+	if cfg.From.IsZero() || cfg.To.Before(cfg.From) {
+		return nil, errors.New("Error invalid IPO tracker period")
+	}
+
+	return &IPOTracker{
+		Client:  client,
+		Config:  cfg,
+		now:     time.Now,
+		records: make(map[string]*objects.IPORecord),
+	}, nil
+}
+
+// Refresh - reload IPO endpoints and performance of listed companies
+func (t *IPOTracker) Refresh() error {
+	calendar, err := loadCalendarChunks(t.Config.From, t.Config.To, ipoCalendarMaxDays, t.Client.CompanyValuation.IPOCalendar)
+	if err != nil {
+		return errors.Wrap(err, "Error load IPO calendar")
+	}
+
+	confirmed, err := loadCalendarChunks(t.Config.From, t.Config.To, ipoCalendarMaxDays, t.Client.CompanyValuation.IPOCalendarConfirmed)
+	if err != nil {
+		return errors.Wrap(err, "Error load confirmed IPO calendar")
+	}
+
+	prospectus, err := loadCalendarChunks(t.Config.From, t.Config.To, ipoCalendarMaxDays, t.Client.CompanyValuation.IPOCalendarProspectus)
+	if err != nil {
+		return errors.Wrap(err, "Error load IPO prospectus calendar")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Filings first, calendar actions only move statuses forward
+	for _, c := range confirmed {
+		r := t.record(c.Symbol, "")
+		r.Cik = c.Cik
+		r.FilingDate = c.FilingDate
+		r.FilingURL = c.URL
+		setIPOStatus(r, objects.IPOStatusFiled, c.FilingDate)
+		if len(c.EffectivenessDate) > 0 {
+			r.EffectivenessDate = c.EffectivenessDate
+			setIPOStatus(r, objects.IPOStatusConfirmed, c.EffectivenessDate)
+		}
+	}
+
+	for _, p := range prospectus {
+		r := t.record(p.Symbol, "")
+		r.Cik = p.Cik
+		r.ProspectusURL = p.URL
+		if len(p.IpoDate) > 0 {
+			r.IpoDate = p.IpoDate
+		}
+
+		if len(r.FilingDate) == 0 {
+			r.FilingDate = p.FilingDate
+		}
+
+		setIPOStatus(r, objects.IPOStatusFiled, p.FilingDate)
+		if p.PricePublicPerShare > 0 {
+			r.OfferPrice = p.PricePublicPerShare
+			r.GrossProceeds = p.PricePublicTotal
+			r.DiscountsAndCommissionsPerShare = p.DiscountsAndCommissionsPerShare
+			r.DiscountsAndCommissionsTotal = p.DiscountsAndCommissionsTotal
+			r.NetProceeds = p.ProceedsBeforeExpensesTotal
+			setIPOStatus(r, objects.IPOStatusPriced, strings.SplitN(p.AcceptedDate, " ", 2)[0])
+		}
+	}
+
+	for _, c := range calendar {
+		r := t.record(c.Symbol, c.Company)
+		r.Company = c.Company
+		r.Exchange = c.Exchange
+		r.IpoDate = c.Date
+		if c.Shares != 0 {
+			r.Shares = c.Shares
+		}
+
+		if c.MarketCap != 0 {
+			r.MarketCap = c.MarketCap
+		}
+
+		if len(c.PriceRange) > 0 {
+			r.PriceRange = c.PriceRange
+			r.PriceLow, r.PriceHigh = parsePriceRange(c.PriceRange)
+		}
+
+		switch strings.ToLower(c.Actions) {
+		case "withdrawn":
+			setIPOStatus(r, objects.IPOStatusWithdrawn, c.Date)
+		case "postponed":
+			setIPOStatus(r, objects.IPOStatusPostponed, c.Date)
+		case "priced":
+			setIPOStatus(r, objects.IPOStatusPriced, c.Date)
+		default:
+			setIPOStatus(r, objects.IPOStatusFiled, c.Date)
+		}
+	}
+
+	today := t.now().Format(layoutDate)
+	for _, r := range t.records {
+		if r.Status == objects.IPOStatusWithdrawn || len(r.Symbol) == 0 || len(r.IpoDate) == 0 || r.IpoDate > today || r.Return90D != nil {
+			continue
+		}
+
+		if err := t.loadPerformance(r); err != nil {
+			return errors.Wrapf(err, "Error load IPO performance of %s", r.Symbol)
+		}
+	}
+
+	return nil
+}
+
+// Records - IPO records sorted by IPO date and symbol
+func (t *IPOTracker) Records() []objects.IPORecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list := make([]objects.IPORecord, 0, len(t.records))
+	for _, r := range t.records {
+		record := *r
+		record.StatusHistory = append([]objects.IPOStatusChange(nil), r.StatusHistory...)
+		list = append(list, record)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].IpoDate != list[j].IpoDate {
+			return list[i].IpoDate < list[j].IpoDate
+		}
+
+		return list[i].Symbol < list[j].Symbol
+	})
+
+	return list
+}
+
+// loadPerformance - first day and 30/90 calendar day returns once periods have passed
+func (t *IPOTracker) loadPerformance(r *objects.IPORecord) error {
+	ipoDate, err := time.Parse(layoutDate, r.IpoDate)
+	if err != nil {
+		return nil
+	}
+
+	cList, err := t.Client.Stock.DailySpecificPeriod(r.Symbol, ipoDate, ipoDate.AddDate(0, 0, ipoPerformanceDays+10))
+	if err != nil {
+		return err
+	}
+
+	if cList == nil || len(cList.Historical) == 0 {
+		return nil
+	}
+
+	candles := make([]objects.StockDailyCandle, 0, len(cList.Historical))
+	for _, c := range cList.Historical {
+		if c.Date >= r.IpoDate {
+			candles = append(candles, c)
+		}
+	}
+
+	if len(candles) == 0 {
+		return nil
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Date < candles[j].Date })
+
+	first := candles[0]
+	r.ListedDate = first.Date
+	r.FirstOpen = first.Open
+	r.FirstClose = first.Close
+	setIPOStatus(r, objects.IPOStatusListed, first.Date)
+
+	base := r.OfferPrice
+	if base == 0 {
+		base = first.Open
+	}
+
+	if base == 0 {
+		return nil
+	}
+
+	firstDay := first.Close/base - 1
+	r.FirstDayReturn = &firstDay
+
+	listed, err := time.Parse(layoutDate, first.Date)
+	if err != nil {
+		return nil
+	}
+
+	today := t.now().Format(layoutDate)
+	for _, p := range []struct {
+		days   int
+		target **float64
+	}{{30, &r.Return30D}, {90, &r.Return90D}} {
+		end := listed.AddDate(0, 0, p.days).Format(layoutDate)
+		if end > today {
+			continue
+		}
+
+		i := sort.Search(len(candles), func(i int) bool { return candles[i].Date > end }) - 1
+		performance := candles[i].Close/base - 1
+		*p.target = &performance
+	}
+
+	return nil
+}
+
+func (t *IPOTracker) record(symbol, company string) *objects.IPORecord {
+	key := strings.ToUpper(strings.TrimSpace(symbol))
+	if len(key) == 0 {
+		key = "company|" + strings.ToUpper(strings.TrimSpace(company))
+	}
+
+	r, ok := t.records[key]
+	if !ok {
+		r = &objects.IPORecord{Symbol: strings.ToUpper(strings.TrimSpace(symbol)), Company: company}
+		t.records[key] = r
+	}
+
+	return r
+}
+
+// setIPOStatus - move record forward, repeated or earlier statuses are ignored.
+// Postponed applies until record is listed or withdrawn
+func setIPOStatus(r *objects.IPORecord, status objects.IPOStatus, date string) {
+	reached := 0
+	for _, c := range r.StatusHistory {
+		reached = max(reached, ipoStatusRank[c.Status])
+	}
+
+	if status == objects.IPOStatusPostponed {
+		if r.Status == objects.IPOStatusPostponed || reached >= ipoStatusRank[objects.IPOStatusListed] {
+			return
+		}
+	} else if ipoStatusRank[status] <= reached {
+		return
+	}
+
+	r.Status = status
+	r.StatusHistory = append(r.StatusHistory, objects.IPOStatusChange{Status: status, Date: date})
+}
+
+// parsePriceRange - "14.00-16.00" or "15.00"
+func parsePriceRange(s string) (float64, float64) {
+	parts := strings.SplitN(strings.ReplaceAll(s, "$", ""), "-", 2)
+	low, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0
+	}
+
+	high := low
+	if len(parts) == 2 {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil {
+			high = v
+		}
+	}
+
+	return low, high
+}
@@ -0,0 +1,87 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestIPOTracker(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/ipo_calendar": `[
+			{"date":"2021-03-01","company":"AAA Corp","symbol":"AAA","exchange":"NYSE","actions":"Priced","shares":1000000,"priceRange":"14.00 - 16.00"},
+			{"date":"2021-03-10","company":"BBB Corp","symbol":"BBB","exchange":"NASDAQ","actions":"Withdrawn"},
+			{"date":"2021-03-15","company":"DDD Corp","symbol":"DDD","exchange":"NYSE","actions":"Postponed"},
+			{"date":"2021-05-20","company":"CCC Corp","symbol":"CCC","exchange":"NASDAQ","actions":"Expected","priceRange":"10.00"}]`,
+		"/v4/ipo-calendar-confirmed": `[{"symbol":"AAA","cik":"0000000001","form":"S-1","filingDate":"2021-02-01","effectivenessDate":"2021-02-25","url":"https://sec.gov/aaa"}]`,
+		"/v4/ipo-calendar-prospectus": `[{"symbol":"AAA","cik":"0000000001","form":"424B4","filingDate":"2021-02-26","acceptedDate":"2021-02-26 10:00:00",
+			"ipoDate":"2021-03-01","url":"https://sec.gov/aaa-424b4","pricePublicPerShare":17,"pricePublicTotal":17000000,"proceedsBeforeExpensesTotal":15810000}]`,
+		"/v3/historical-price-full/AAA": `{"symbol":"AAA","historical":[
+			{"date":"2021-04-15","close":30},
+			{"date":"2021-03-31","close":21.25},
+			{"date":"2021-03-30","close":20.4},
+			{"date":"2021-03-01","open":20,"close":25.5}]}`,
+		"/v3/historical-price-full/DDD": `{"symbol":"DDD","historical":[{"date":"2021-03-22","open":10,"close":11}]}`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tracker, err := NewIPOTracker(APIClient, IPOTrackerConfig{
+		From: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tracker.now = func() time.Time { return time.Date(2021, 4, 30, 0, 0, 0, 0, time.UTC) }
+	if err := tracker.Refresh(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	recordList := tracker.Records()
+	if len(recordList) != 4 {
+		t.Fatalf("expected 4 records, got %+v", recordList)
+	}
+
+	aaa, bbb, ddd, ccc := recordList[0], recordList[1], recordList[2], recordList[3]
+	expected := []objects.IPOStatus{objects.IPOStatusFiled, objects.IPOStatusConfirmed, objects.IPOStatusPriced, objects.IPOStatusListed}
+	if len(aaa.StatusHistory) != len(expected) {
+		t.Fatalf("unexpected AAA status history: %+v", aaa.StatusHistory)
+	}
+
+	for i, status := range expected {
+		if aaa.StatusHistory[i].Status != status {
+			t.Fatalf("unexpected AAA status history: %+v", aaa.StatusHistory)
+		}
+	}
+
+	if aaa.OfferPrice != 17 || aaa.NetProceeds != 15810000 || aaa.PriceLow != 14 || aaa.PriceHigh != 16 || aaa.ListedDate != "2021-03-01" {
+		t.Fatalf("unexpected AAA record: %+v", aaa)
+	}
+
+	if aaa.FirstDayReturn == nil || math.Abs(*aaa.FirstDayReturn-0.5) > 1e-9 ||
+		aaa.Return30D == nil || math.Abs(*aaa.Return30D-0.25) > 1e-9 || aaa.Return90D != nil {
+		t.Fatalf("unexpected AAA performance: %+v", aaa)
+	}
+
+	// Transition dated by calendar event, not by refresh
+	if bbb.Status != objects.IPOStatusWithdrawn || bbb.FirstDayReturn != nil ||
+		len(bbb.StatusHistory) != 1 || bbb.StatusHistory[0].Date != "2021-03-10" {
+		t.Fatalf("unexpected BBB record: %+v", bbb)
+	}
+
+	// Postponed IPO still lists later
+	if ddd.Status != objects.IPOStatusListed || len(ddd.StatusHistory) != 2 ||
+		ddd.StatusHistory[0].Status != objects.IPOStatusPostponed || ddd.StatusHistory[0].Date != "2021-03-15" ||
+		ddd.FirstDayReturn == nil || math.Abs(*ddd.FirstDayReturn-0.1) > 1e-9 {
+		t.Fatalf("unexpected DDD record: %+v", ddd)
+	}
+
+	if ccc.Status != objects.IPOStatusFiled || ccc.PriceLow != 10 || ccc.PriceHigh != 10 {
+		t.Fatalf("unexpected CCC record: %+v", ccc)
+	}
+}
@@ -0,0 +1,59 @@
+package fmpcloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// testCaseMockConfig - config for api client pointed at a local http server,
+// routes maps request path to raw response body, "path?key=value" routes
+// match only requests with these query params and take precedence
+func testCaseMockConfig(t *testing.T, routes map[string]string) Config {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := mockRoute(routes, r.URL)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return Config{APIUrl: APIUrl(server.URL), Timeout: 5}
+}
+
+func mockRoute(routes map[string]string, u *url.URL) (string, bool) {
+	for route, body := range routes {
+		path, rawQuery, found := strings.Cut(route, "?")
+		if !found || path != u.Path {
+			continue
+		}
+
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			continue
+		}
+
+		matched := true
+		for key := range query {
+			if u.Query().Get(key) != query.Get(key) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return body, true
+		}
+	}
+
+	body, ok := routes[u.Path]
+	return body, ok
+}
@@ -0,0 +1,415 @@
+package fmpcloud
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// News feed defaults
+const (
+	newsFeedDefaultInterval           = time.Minute
+	newsFeedDefaultLimit              = 100
+	newsFeedDefaultDuplicateThreshold = 0.6
+	newsFeedDefaultDuplicateWindow    = 48 * time.Hour
+)
+
+var (
+	// newsCashtagRegexp - $AAPL
+	newsCashtagRegexp = regexp.MustCompile(`\$([A-Z]{1,5}(?:\.[A-Z])?)\b`)
+	// newsExchangeTagRegexp - (NASDAQ: AAPL), (NYSE:BRK.B)
+	newsExchangeTagRegexp = regexp.MustCompile(`\((?i:NYSE American|NYSE|NASDAQ|AMEX|OTCQX|OTCQB|OTC|TSX|TSXV|LSE)\s*:\s*([A-Z]{1,5}(?:\.[A-Z])?)\)`)
+)
+
+// NewsFeedConfig for create news feed
+type NewsFeedConfig struct {
+	SymbolList         []string             // universe, press releases are loaded only for these symbols, empty - all news and filings
+	SourceList         []objects.NewsSource // default all sources
+	Limit              int64                // items per request, default 100
+	Interval           time.Duration        // poll interval of Run, default 1 minute
+	DuplicateThreshold float64              // Jaccard similarity of titles, default 0.6
+	DuplicateWindow    time.Duration        // near duplicates are searched within window, default 48 hours
+	Lexicon            map[string]float64   // extra sentiment words, override bundled lexicon
+}
+
+// NewsFeed - polls news, press releases and filings, emits new unique items
+type NewsFeed struct {
+	Client *APIClient
+	Config NewsFeedConfig
+
+	mu         sync.Mutex
+	universe   map[string]bool
+	watermarks map[string]string    // source or source|symbol => latest published date
+	seen       map[string]time.Time // item id => published
+	recent     []newsFingerprint
+}
+
+type newsFingerprint struct {
+	id        string
+	published time.Time
+	terms     map[string]bool
+	symbols   []string
+}
+
+// NewNewsFeed ...
+func NewNewsFeed(client *APIClient, cfg NewsFeedConfig) *NewsFeed {
+	if len(cfg.SourceList) == 0 {
+		cfg.SourceList = []objects.NewsSource{
+			objects.NewsSourceStockNews,
+			objects.NewsSourcePressRelease,
+			objects.NewsSourceSECFiling,
+			objects.NewsSourceInsiderFiling,
+		}
+	}
+
+	if cfg.Limit <= 0 {
+		cfg.Limit = newsFeedDefaultLimit
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = newsFeedDefaultInterval
+	}
+
+	if cfg.DuplicateThreshold <= 0 {
+		cfg.DuplicateThreshold = newsFeedDefaultDuplicateThreshold
+	}
+
+	if cfg.DuplicateWindow <= 0 {
+		cfg.DuplicateWindow = newsFeedDefaultDuplicateWindow
+	}
+
+	f := &NewsFeed{
+		Client:     client,
+		Config:     cfg,
+		universe:   make(map[string]bool, len(cfg.SymbolList)),
+		watermarks: make(map[string]string),
+		seen:       make(map[string]time.Time),
+	}
+
+	for _, symbol := range cfg.SymbolList {
+		f.universe[strings.ToUpper(symbol)] = true
+	}
+
+	return f
+}
+
+// Poll - fetch every source once, returns items published since last poll sorted by date
+func (f *NewsFeed) Poll() ([]objects.NewsItem, error) {
+	var batchList []newsBatch
+	for _, source := range f.Config.SourceList {
+		list, err := f.load(source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load news source %s", source)
+		}
+
+		batchList = append(batchList, list...)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var itemList []objects.NewsItem
+	for _, batch := range batchList {
+		watermark := f.watermarks[batch.key]
+		for _, item := range batch.itemList {
+			if item.PublishedDate < watermark || !f.inUniverse(item) {
+				continue
+			}
+
+			if _, ok := f.seen[item.ID]; ok {
+				continue
+			}
+
+			itemList = append(itemList, item)
+			if item.PublishedDate > f.watermarks[batch.key] {
+				f.watermarks[batch.key] = item.PublishedDate
+			}
+		}
+	}
+
+	sort.SliceStable(itemList, func(i, j int) bool { return itemList[i].PublishedDate < itemList[j].PublishedDate })
+
+	uniqueList := itemList[:0]
+	for _, item := range itemList {
+		published, err := parseBarDate(item.PublishedDate)
+		if err != nil {
+			published = time.Now()
+		}
+
+		f.seen[item.ID] = published
+		if f.duplicate(item, published) {
+			continue
+		}
+
+		item.Sentiment, item.SentimentLabel = headlineSentiment(item.Title, f.Config.Lexicon)
+		uniqueList = append(uniqueList, item)
+	}
+
+	f.prune()
+
+	return uniqueList, nil
+}
+
+// Run - poll until context is done and pass new items to handler, poll errors are logged
+func (f *NewsFeed) Run(ctx context.Context, handler func(objects.NewsItem)) error {
+	ticker := time.NewTicker(f.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		itemList, err := f.Poll()
+		if err != nil && f.Client.Logger != nil {
+			f.Client.Logger.Error("News poll failed", "err", err)
+		}
+
+		for _, item := range itemList {
+			handler(item)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewsTagSymbols - symbols of item with cashtags and exchange tags of title and text,
+// when universe is set bare upper case universe symbols in title are tagged too
+func NewsTagSymbols(item objects.NewsItem, universe map[string]bool) []string {
+	seen := make(map[string]bool)
+	var list []string
+	add := func(symbol string) {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if len(symbol) > 0 && !seen[symbol] {
+			seen[symbol] = true
+			list = append(list, symbol)
+		}
+	}
+
+	for _, symbol := range item.SymbolList {
+		add(symbol)
+	}
+
+	for _, text := range []string{item.Title, item.Text} {
+		for _, re := range []*regexp.Regexp{newsCashtagRegexp, newsExchangeTagRegexp} {
+			for _, m := range re.FindAllStringSubmatch(text, -1) {
+				add(m[1])
+			}
+		}
+	}
+
+	// Single letters are words too often
+	for _, word := range strings.FieldsFunc(item.Title, func(r rune) bool { return !isUpperOrDigit(r) }) {
+		if len(word) > 1 && universe[word] {
+			add(word)
+		}
+	}
+
+	return list
+}
+
+type newsBatch struct {
+	key      string
+	itemList []objects.NewsItem
+}
+
+func (f *NewsFeed) load(source objects.NewsSource) ([]newsBatch, error) {
+	switch source {
+	case objects.NewsSourceStockNews:
+		nList, err := f.Client.CompanyValuation.StockNews(objects.RequestStockNews{SymbolList: f.Config.SymbolList, Limit: f.Config.Limit})
+		if err != nil {
+			return nil, err
+		}
+
+		batch := newsBatch{key: string(source)}
+		for _, n := range nList {
+			batch.itemList = append(batch.itemList, f.item(objects.NewsItem{
+				Source: source, Site: n.Site, SymbolList: []string{n.Symbol}, PublishedDate: n.PublishedDate,
+				Title: n.Title, Text: n.Text, URL: n.URL,
+			}))
+		}
+
+		return []newsBatch{batch}, nil
+	case objects.NewsSourcePressRelease:
+		var list []newsBatch
+		for _, symbol := range f.Config.SymbolList {
+			prList, err := f.Client.CompanyValuation.PressReleases(objects.RequestPressReleases{Symbol: symbol, Limit: f.Config.Limit})
+			if err != nil {
+				return nil, err
+			}
+
+			batch := newsBatch{key: string(source) + "|" + strings.ToUpper(symbol)}
+			for _, pr := range prList {
+				batch.itemList = append(batch.itemList, f.item(objects.NewsItem{
+					Source: source, SymbolList: []string{pr.Symbol}, PublishedDate: pr.Date, Title: pr.Title, Text: pr.Text,
+				}))
+			}
+
+			list = append(list, batch)
+		}
+
+		return list, nil
+	case objects.NewsSourceSECFiling:
+		fList, err := f.Client.CompanyValuation.RssFeed()
+		if err != nil {
+			return nil, err
+		}
+
+		batch := newsBatch{key: string(source)}
+		for _, filing := range fList {
+			batch.itemList = append(batch.itemList, f.item(objects.NewsItem{
+				Source: source, Site: "sec.gov", SymbolList: []string{filing.Ticker}, PublishedDate: filing.Date,
+				Title: filing.Title, URL: filing.Link,
+			}))
+		}
+
+		return []newsBatch{batch}, nil
+	case objects.NewsSourceInsiderFiling:
+		iList, err := f.Client.InsiderTrading.RSSFeed(f.Config.Limit)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := newsBatch{key: string(source)}
+		for _, filing := range iList {
+			batch.itemList = append(batch.itemList, f.item(objects.NewsItem{
+				Source: source, Site: "sec.gov", SymbolList: []string{filing.Symbol}, PublishedDate: filing.FillingDate,
+				Title: filing.Title, URL: filing.Link,
+			}))
+		}
+
+		return []newsBatch{batch}, nil
+	}
+
+	return nil, errors.Errorf("Error unknown news source %s", source)
+}
+
+// item - normalized date, tagged symbols and id
+func (f *NewsFeed) item(item objects.NewsItem) objects.NewsItem {
+	item.PublishedDate = economicDateTime(strings.Replace(item.PublishedDate, "T", " ", 1))
+	if len(item.PublishedDate) > len(layoutDateTime) {
+		item.PublishedDate = item.PublishedDate[:len(layoutDateTime)]
+	}
+
+	item.SymbolList = NewsTagSymbols(item, f.universe)
+	item.ID = newsID(item)
+
+	return item
+}
+
+func (f *NewsFeed) inUniverse(item objects.NewsItem) bool {
+	if len(f.universe) == 0 {
+		return true
+	}
+
+	for _, symbol := range item.SymbolList {
+		if f.universe[symbol] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// duplicate - story with similar title and symbols seen within window,
+// filings are unique by id only
+func (f *NewsFeed) duplicate(item objects.NewsItem, published time.Time) bool {
+	if item.Source == objects.NewsSourceSECFiling || item.Source == objects.NewsSourceInsiderFiling {
+		return false
+	}
+
+	terms := newsTitleTerms(item.Title)
+	for _, r := range f.recent {
+		if absDuration(published.Sub(r.published)) > f.Config.DuplicateWindow || !newsSymbolsOverlap(item.SymbolList, r.symbols) {
+			continue
+		}
+
+		if jaccard(terms, r.terms) >= f.Config.DuplicateThreshold {
+			return true
+		}
+	}
+
+	f.recent = append(f.recent, newsFingerprint{id: item.ID, published: published, terms: terms, symbols: item.SymbolList})
+
+	return false
+}
+
+// prune - forget items older than duplicate window before newest item
+func (f *NewsFeed) prune() {
+	var newest time.Time
+	for _, published := range f.seen {
+		if published.After(newest) {
+			newest = published
+		}
+	}
+
+	limit := newest.Add(-f.Config.DuplicateWindow)
+	for id, published := range f.seen {
+		if published.Before(limit) {
+			delete(f.seen, id)
+		}
+	}
+
+	recent := f.recent[:0]
+	for _, r := range f.recent {
+		if !r.published.Before(limit) {
+			recent = append(recent, r)
+		}
+	}
+
+	f.recent = recent
+}
+
+func newsID(item objects.NewsItem) string {
+	key := string(item.Source) + "|" + item.URL
+	if len(item.URL) == 0 {
+		key = string(item.Source) + "|" + strings.Join(item.SymbolList, ",") + "|" + item.PublishedDate + "|" + item.Title
+	}
+
+	sum := sha1.Sum([]byte(key))
+
+	return hex.EncodeToString(sum[:10])
+}
+
+func newsSymbolsOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for term := range a {
+		if b[term] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func isUpperOrDigit(r rune) bool {
+	return (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
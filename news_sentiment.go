@@ -0,0 +1,114 @@
+package fmpcloud
+
+import (
+	"math"
+	"strings"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Headline sentiment model
+const (
+	newsSentimentAlpha     = 4    // normalization of raw score into -1 .. 1
+	newsSentimentThreshold = 0.05 // absolute score below threshold is neutral
+	newsNegationWindow     = 3    // words after negation with flipped polarity
+)
+
+// newsLexicon - financial headline polarity of words, -3 .. 3
+var newsLexicon = map[string]float64{
+	// positive
+	"beat": 2, "beats": 2, "tops": 2, "topped": 2, "exceeds": 2, "exceeded": 2, "surpass": 2, "surpasses": 2,
+	"record": 1.5, "high": 0.5, "highs": 1, "surge": 2.5, "surges": 2.5, "surged": 2.5, "soar": 2.5, "soars": 2.5,
+	"soared": 2.5, "jump": 2, "jumps": 2, "jumped": 2, "rally": 2, "rallies": 2, "rallied": 2, "gain": 1.5,
+	"gains": 1.5, "gained": 1.5, "rise": 1, "rises": 1, "rose": 1, "climb": 1, "climbs": 1, "climbed": 1,
+	"growth": 1.5, "grow": 1, "grows": 1, "grew": 1, "profit": 1, "profitable": 1.5, "upgrade": 2.5,
+	"upgrades": 2.5, "upgraded": 2.5, "outperform": 2, "outperforms": 2, "buy": 1, "bullish": 2.5,
+	"strong": 1.5, "stronger": 1.5, "robust": 1.5, "solid": 1, "boost": 1.5, "boosts": 1.5, "boosted": 1.5,
+	"raise": 1, "raises": 1.5, "raised": 1.5, "expand": 1, "expands": 1, "expansion": 1, "win": 2, "wins": 2,
+	"approval": 2, "approved": 2, "approves": 2, "launch": 0.5, "launches": 0.5, "partnership": 1,
+	"dividend": 0.5, "buyback": 1.5, "repurchase": 1, "optimistic": 2, "recover": 1.5, "recovery": 1.5,
+	"rebound": 1.5, "rebounds": 1.5, "improve": 1.5, "improves": 1.5, "improved": 1.5, "positive": 1.5,
+	"success": 2, "successful": 2, "breakthrough": 2.5, "accelerate": 1.5, "accelerates": 1.5,
+	// negative
+	"miss": -2, "misses": -2, "missed": -2, "fall": -1.5, "falls": -1.5, "fell": -1.5, "drop": -1.5,
+	"drops": -1.5, "dropped": -1.5, "decline": -1.5, "declines": -1.5, "declined": -1.5, "slump": -2.5,
+	"slumps": -2.5, "plunge": -3, "plunges": -3, "plunged": -3, "tumble": -2.5, "tumbles": -2.5,
+	"tumbled": -2.5, "sink": -2, "sinks": -2, "sank": -2, "slide": -1.5, "slides": -1.5, "loss": -2,
+	"losses": -2, "lose": -1.5, "loses": -1.5, "lower": -1, "lowers": -1.5, "lowered": -1.5, "cut": -1.5,
+	"cuts": -1.5, "downgrade": -2.5, "downgrades": -2.5, "downgraded": -2.5, "underperform": -2, "sell": -1,
+	"bearish": -2.5, "weak": -1.5, "weaker": -1.5, "weakness": -1.5, "warn": -2, "warns": -2, "warning": -2,
+	"lawsuit": -2, "sued": -2, "sues": -2, "probe": -2, "investigation": -2, "fraud": -3, "recall": -2,
+	"recalls": -2, "bankruptcy": -3, "default": -2.5, "layoffs": -2, "layoff": -2, "fine": -1.5, "fined": -2,
+	"delay": -1.5, "delays": -1.5, "delayed": -1.5, "halt": -2, "halts": -2, "halted": -2, "suspend": -2,
+	"suspends": -2, "suspended": -2, "risk": -1, "risks": -1, "concern": -1.5, "concerns": -1.5, "fear": -2,
+	"fears": -2, "crash": -3, "crisis": -2.5, "volatile": -1, "negative": -1.5, "disappointing": -2,
+	"disappoints": -2, "resign": -1.5, "resigns": -1.5, "shortfall": -2, "dilution": -1.5, "impairment": -2,
+}
+
+// newsNegationList - words flipping polarity of following words
+var newsNegationList = map[string]bool{
+	"not": true, "no": true, "never": true, "without": true, "fails": true, "failed": true, "fail": true, "isn": true,
+	"doesn": true, "didn": true, "cannot": true,
+}
+
+// HeadlineSentiment - lexicon score of headline in -1 .. 1 and its label, runs offline
+func HeadlineSentiment(headline string) (float64, objects.NewsSentiment) {
+	return headlineSentiment(headline, nil)
+}
+
+// headlineSentiment - extra lexicon entries take precedence over bundled lexicon
+func headlineSentiment(headline string, extra map[string]float64) (float64, objects.NewsSentiment) {
+	raw := 0.0
+	negated := 0
+	for _, word := range transcriptTerms(headline) {
+		if newsNegationList[word] {
+			negated = newsNegationWindow
+			continue
+		}
+
+		// Contraction fragments and stop words do not shorten negation window
+		if len(word) < 2 || newsStopWords[word] {
+			continue
+		}
+
+		polarity, ok := extra[word]
+		if !ok {
+			polarity = newsLexicon[word]
+		}
+
+		if negated > 0 {
+			polarity = -polarity
+			negated--
+		}
+
+		raw += polarity
+	}
+
+	score := raw / math.Sqrt(raw*raw+newsSentimentAlpha)
+	switch {
+	case score >= newsSentimentThreshold:
+		return score, objects.NewsSentimentPositive
+	case score <= -newsSentimentThreshold:
+		return score, objects.NewsSentimentNegative
+	}
+
+	return score, objects.NewsSentimentNeutral
+}
+
+// newsTitleTerms - distinct informative words of title for near duplicate detection
+func newsTitleTerms(title string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range transcriptTerms(title) {
+		if len(word) > 1 && !newsStopWords[word] {
+			terms[strings.TrimSuffix(word, "s")] = true
+		}
+	}
+
+	return terms
+}
+
+var newsStopWords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true, "of": true, "to": true, "in": true, "on": true,
+	"for": true, "with": true, "at": true, "by": true, "from": true, "as": true, "is": true, "are": true,
+	"its": true, "it": true, "after": true, "over": true, "amid": true, "inc": true, "corp": true, "co": true,
+}
@@ -0,0 +1,93 @@
+package fmpcloud
+
+import (
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestNewsFeed(t *testing.T) {
+	filings := map[string]string{
+		"/v3/press-releases/AAA": `[{"symbol":"AAA","date":"2021-07-27 09:00:00","title":"AAA announces partnership with $BBB"}]`,
+		"/v3/press-releases/BBB": `[]`,
+		"/v3/rss_feed": `[
+			{"title":"10-Q - AAA Inc","date":"2021-07-27 18:00:00","link":"https://sec.gov/1","ticker":"AAA"},
+			{"title":"10-Q - ZZZ Inc","date":"2021-07-27 18:00:00","link":"https://sec.gov/2","ticker":"ZZZ"}]`,
+		"/v4/insider-trading-rss-feed": `[{"title":"4 - AAA","fillingDate":"2021-07-27 19:00:00","symbol":"AAA","link":"https://sec.gov/3"}]`,
+	}
+
+	routes := map[string]string{
+		"/v3/stock_news": `[
+			{"symbol":"AAA","publishedDate":"2021-07-27 16:30:00","title":"AAA Beats Q3 Estimates As Sales Surge","site":"a.com","url":"https://a.com/1"},
+			{"symbol":"AAA","publishedDate":"2021-07-27 17:00:00","title":"AAA beats Q3 estimates as sales surge","site":"b.com","url":"https://b.com/1"},
+			{"symbol":"BBB","publishedDate":"2021-07-27 15:00:00","title":"BBB shares plunge after guidance cut","site":"a.com","url":"https://a.com/2"}]`,
+	}
+	for path, body := range filings {
+		routes[path] = body
+	}
+
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, routes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	feed := NewNewsFeed(APIClient, NewsFeedConfig{SymbolList: []string{"AAA", "BBB"}})
+	itemList, err := feed.Poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(itemList) != 5 {
+		t.Fatalf("expected 5 items, got %+v", itemList)
+	}
+
+	press, bbb, aaa := itemList[0], itemList[1], itemList[2]
+	if press.Source != objects.NewsSourcePressRelease || len(press.SymbolList) != 2 || press.SymbolList[1] != "BBB" {
+		t.Fatalf("unexpected press release: %+v", press)
+	}
+
+	if bbb.SentimentLabel != objects.NewsSentimentNegative || aaa.SentimentLabel != objects.NewsSentimentPositive || aaa.Site != "a.com" {
+		t.Fatalf("unexpected news: %+v %+v", bbb, aaa)
+	}
+
+	if itemList[3].Source != objects.NewsSourceSECFiling || itemList[4].Source != objects.NewsSourceInsiderFiling || len(aaa.ID) != 20 {
+		t.Fatalf("unexpected filings: %+v", itemList[3:])
+	}
+
+	routes = map[string]string{
+		"/v3/stock_news": `[
+			{"symbol":"AAA","publishedDate":"2021-07-28 10:00:00","title":"AAA stock not expected to fall","site":"c.com","url":"https://c.com/1"},
+			{"symbol":"AAA","publishedDate":"2021-07-27 16:30:00","title":"AAA Beats Q3 Estimates As Sales Surge","site":"a.com","url":"https://a.com/1"},
+			{"symbol":"AAA","publishedDate":"2021-07-26 10:00:00","title":"Late story","site":"d.com","url":"https://d.com/1"}]`,
+	}
+	for path, body := range filings {
+		routes[path] = body
+	}
+
+	feed.Client, err = NewAPIClient(testCaseMockConfig(t, routes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	itemList, err = feed.Poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(itemList) != 1 || itemList[0].SentimentLabel != objects.NewsSentimentPositive {
+		t.Fatalf("expected one new positive item, got %+v", itemList)
+	}
+}
+
+func TestHeadlineSentiment(t *testing.T) {
+	for headline, expected := range map[string]objects.NewsSentiment{
+		"Company tops estimates, raises guidance":  objects.NewsSentimentPositive,
+		"Shares tumble on weak outlook":            objects.NewsSentimentNegative,
+		"Company schedules conference call":        objects.NewsSentimentNeutral,
+		"Retailer doesn't expect sales to decline": objects.NewsSentimentPositive,
+	} {
+		if _, label := HeadlineSentiment(headline); label != expected {
+			t.Fatalf("expected %s for %q, got %s", expected, headline, label)
+		}
+	}
+}
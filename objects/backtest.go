@@ -0,0 +1,60 @@
+package objects
+
+import "time"
+
+// BacktestAsset and BacktestOrderSide
+const (
+	BacktestAssetStock  BacktestAsset = "stock"
+	BacktestAssetCrypto BacktestAsset = "crypto"
+	BacktestAssetForex  BacktestAsset = "forex"
+
+	BacktestOrderSideBuy  BacktestOrderSide = "buy"
+	BacktestOrderSideSell BacktestOrderSide = "sell"
+)
+
+// BacktestAsset ...
+type BacktestAsset string
+
+// BacktestOrderSide ...
+type BacktestOrderSide string
+
+// Bar - single OHLCV bar of any instrument
+type Bar struct {
+	Symbol string    `json:"symbol"`
+	Date   time.Time `json:"date"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+}
+
+// BacktestTrade - simulated fill
+type BacktestTrade struct {
+	Date       time.Time         `json:"date"`
+	Symbol     string            `json:"symbol"`
+	Side       BacktestOrderSide `json:"side"`
+	Quantity   float64           `json:"quantity"`
+	Price      float64           `json:"price"` // fill price with slippage
+	Commission float64           `json:"commission"`
+}
+
+// BacktestEquityPoint ...
+type BacktestEquityPoint struct {
+	Date   time.Time `json:"date"`
+	Cash   float64   `json:"cash"`
+	Equity float64   `json:"equity"`
+}
+
+// BacktestResult ...
+type BacktestResult struct {
+	InitialCash  float64               `json:"initialCash"`
+	FinalEquity  float64               `json:"finalEquity"`
+	TotalReturn  float64               `json:"totalReturn"` // 0.1 = 10%
+	MaxDrawdown  float64               `json:"maxDrawdown"` // 0.1 = 10%
+	Dividends    float64               `json:"dividends"`
+	Commissions  float64               `json:"commissions"`
+	EquityCurve  []BacktestEquityPoint `json:"equityCurve"`
+	Trades       []BacktestTrade       `json:"trades"`
+	RejectedList []BacktestTrade       `json:"rejectedList"` // orders not filled (not enough cash or position)
+}
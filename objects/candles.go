@@ -0,0 +1,31 @@
+package objects
+
+import "time"
+
+// Resolution - bar size of candles, same values as StockCandlePeriod, CryptoCandlePeriod and ForexCandlePeriod
+const (
+	Resolution1Min  Resolution = "1min"
+	Resolution5Min  Resolution = "5min"
+	Resolution15Min Resolution = "15min"
+	Resolution30Min Resolution = "30min"
+	Resolution1Hour Resolution = "1hour"
+	Resolution4Hour Resolution = "4hour"
+	Resolution1Day  Resolution = "1day"
+)
+
+// Resolution ...
+type Resolution string
+
+// CandleTypes - candles of historical chart endpoint
+type CandleTypes interface {
+	StockCandle | CryptoCandle | ForexCandle
+}
+
+// RequestCandles - candles of stock, crypto or forex symbol, zero From loads latest bars
+type RequestCandles struct {
+	Symbol     string
+	Resolution Resolution
+	From       time.Time
+	To         time.Time
+	Location   *time.Location // time zone of intraday candle dates, default America/New_York, set UTC for crypto
+}
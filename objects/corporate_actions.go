@@ -0,0 +1,59 @@
+package objects
+
+// CorporateActionKind ...
+const (
+	CorporateActionDividend CorporateActionKind = "dividend"
+	CorporateActionSplit    CorporateActionKind = "split"
+)
+
+// DividendAlertKind - change of dividend against previous regular dividend
+const (
+	DividendAlertCut      DividendAlertKind = "cut"
+	DividendAlertIncrease DividendAlertKind = "increase"
+	DividendAlertSpecial  DividendAlertKind = "special"
+)
+
+// CorporateActionKind ...
+type CorporateActionKind string
+
+// DividendAlertKind ...
+type DividendAlertKind string
+
+// CorporateAction - dividend or split with normalized dates
+type CorporateAction struct {
+	Symbol          string              `json:"symbol"`
+	Kind            CorporateActionKind `json:"kind"`
+	ExDate          string              `json:"exDate"` // 2020-09-10
+	RecordDate      string              `json:"recordDate"`
+	PaymentDate     string              `json:"paymentDate"`
+	DeclarationDate string              `json:"declarationDate"`
+	Dividend        float64             `json:"dividend"`
+	AdjDividend     float64             `json:"adjDividend"` // split adjusted
+	Numerator       float64             `json:"numerator"`
+	Denominator     float64             `json:"denominator"`
+	Special         bool                `json:"special"`   // dividend off regular cadence or amount
+	Projected       bool                `json:"projected"` // expected from cadence, not declared
+}
+
+// DividendYield - trailing and forward dividend yield of symbol
+type DividendYield struct {
+	Symbol           string  `json:"symbol"`
+	Price            float64 `json:"price"`
+	Frequency        int     `json:"frequency"` // regular dividends per year
+	TrailingDividend float64 `json:"trailingDividend"`
+	TrailingYield    float64 `json:"trailingYield"`
+	SpecialDividend  float64 `json:"specialDividend"` // special dividends of trailing year, not in yields
+	ForwardDividend  float64 `json:"forwardDividend"` // latest regular dividend * frequency
+	ForwardYield     float64 `json:"forwardYield"`
+}
+
+// DividendAlert - dividend cut, increase or special dividend
+type DividendAlert struct {
+	Symbol           string            `json:"symbol"`
+	Kind             DividendAlertKind `json:"kind"`
+	ExDate           string            `json:"exDate"`
+	DeclarationDate  string            `json:"declarationDate"`
+	Dividend         float64           `json:"dividend"`
+	PreviousDividend float64           `json:"previousDividend"` // previous regular dividend
+	Change           float64           `json:"change"`           // -0.5 = halved
+}
@@ -0,0 +1,42 @@
+package objects
+
+// COTExtreme - positioning at edge of lookback range
+const (
+	COTExtremeNone  COTExtreme = ""
+	COTExtremeLong  COTExtreme = "long"  // COT index at or above 100 - threshold
+	COTExtremeShort COTExtreme = "short" // COT index at or below threshold
+)
+
+// COTExtreme ...
+type COTExtreme string
+
+// COTPoint - positioning of contract on report date
+type COTPoint struct {
+	Date                   string     `json:"date"` // 2021-09-21
+	OpenInterest           float64    `json:"openInterest"`
+	OpenInterestChange     float64    `json:"openInterestChange"` // week over week
+	CommercialLong         float64    `json:"commercialLong"`
+	CommercialShort        float64    `json:"commercialShort"`
+	CommercialNet          float64    `json:"commercialNet"`
+	CommercialNetChange    float64    `json:"commercialNetChange"`
+	CommercialIndex        float64    `json:"commercialIndex"` // 0 - 100, net position percentile in lookback range
+	CommercialExtreme      COTExtreme `json:"commercialExtreme"`
+	NonCommercialLong      float64    `json:"nonCommercialLong"`
+	NonCommercialShort     float64    `json:"nonCommercialShort"`
+	NonCommercialNet       float64    `json:"nonCommercialNet"`
+	NonCommercialNetChange float64    `json:"nonCommercialNetChange"`
+	NonCommercialIndex     float64    `json:"nonCommercialIndex"`
+	NonCommercialExtreme   COTExtreme `json:"nonCommercialExtreme"`
+	NonReportableNet       float64    `json:"nonReportableNet"`
+	Price                  float64    `json:"price"`       // close on or before report date, 0 without prices
+	PriceChange            float64    `json:"priceChange"` // 0.01 = 1% since previous report
+}
+
+// COTSeries - computed positioning series of contract, oldest first
+type COTSeries struct {
+	Symbol        string     `json:"symbol"`
+	ShortName     string     `json:"shortName"`
+	ContractUnits string     `json:"contractUnits"`
+	PriceSymbol   string     `json:"priceSymbol"`
+	PointList     []COTPoint `json:"pointList"`
+}
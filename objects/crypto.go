@@ -9,7 +9,11 @@ const (
 	CryptoCandlePeriod15Min CryptoCandlePeriod = "15min"
 	CryptoCandlePeriod30Min CryptoCandlePeriod = "30min"
 	CryptoCandlePeriod1Hour CryptoCandlePeriod = "1hour"
-	CryptoCnadlePeriod4Hour CryptoCandlePeriod = "4hour"
+	CryptoCandlePeriod4Hour CryptoCandlePeriod = "4hour"
+	CryptoCandlePeriod1Day  CryptoCandlePeriod = "1day" // loaded from daily candles
+
+	// Deprecated: use CryptoCandlePeriod4Hour
+	CryptoCnadlePeriod4Hour = CryptoCandlePeriod4Hour
 
 	CryptoSerieTypeLine CryptoSerieType = "line"
 )
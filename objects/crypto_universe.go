@@ -0,0 +1,50 @@
+package objects
+
+import "time"
+
+// CryptoRankBy - ranking of crypto universe
+const (
+	CryptoRankByMarketCap    CryptoRankBy = "marketCap"
+	CryptoRankByVolume       CryptoRankBy = "volume"
+	CryptoRankByDollarVolume CryptoRankBy = "dollarVolume" // price * volume
+)
+
+// CryptoRankBy ...
+type CryptoRankBy string
+
+// CryptoAsset - ranked crypto of market snapshot
+type CryptoAsset struct {
+	Rank              int     `json:"rank"`
+	Symbol            string  `json:"symbol"`
+	Name              string  `json:"name"`
+	Price             float64 `json:"price"`
+	MarketCap         float64 `json:"marketCap"`
+	Volume            float64 `json:"volume"`
+	DollarVolume      float64 `json:"dollarVolume"`
+	ChangesPercentage float64 `json:"changesPercentage"`
+	Dominance         float64 `json:"dominance"` // share of market cap of all quoted cryptos
+}
+
+// CryptoUniverseHistory - bars of symbols aligned on dates, Bars[symbol][i] is bar of Dates[i]
+type CryptoUniverseHistory struct {
+	Period       CryptoCandlePeriod `json:"period"`
+	Symbols      []string           `json:"symbols"`
+	Dates        []time.Time        `json:"dates"`
+	Bars         map[string][]Bar   `json:"bars"`         // missing bars of partial dates have zero close
+	DroppedDates int                `json:"droppedDates"` // dates missing bars of some symbols left out of common dates
+}
+
+// CryptoCorrelation - correlation of log returns, Matrix[i][j] of Symbols[i] and Symbols[j]
+type CryptoCorrelation struct {
+	Symbols      []string    `json:"symbols"`
+	Matrix       [][]float64 `json:"matrix"`
+	Observations int         `json:"observations"`
+}
+
+// CryptoDominance - market cap share of symbols within universe, Shares[i][j] of Symbols[j] on Dates[i].
+// Historical market cap is estimated with current supply.
+type CryptoDominance struct {
+	Symbols []string    `json:"symbols"`
+	Dates   []time.Time `json:"dates"`
+	Shares  [][]float64 `json:"shares"`
+}
@@ -0,0 +1,27 @@
+package objects
+
+// CurrencyRate - rate of From in To, derived through Path when there is no direct pair.
+// Selling 1 From yields Bid To, buying 1 From costs Ask To.
+type CurrencyRate struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Bid  float64  `json:"bid"`
+	Ask  float64  `json:"ask"`
+	Mid  float64  `json:"mid"`
+	Path []string `json:"path"` // EUR, USD, JPY
+	Date string   `json:"date"` // oldest quote date of path
+}
+
+// CurrencyRateHistory - daily close rate of From in To
+type CurrencyRateHistory struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	Path       []string            `json:"path"`
+	Historical []CurrencyRatePoint `json:"historical"` // oldest first
+}
+
+// CurrencyRatePoint ...
+type CurrencyRatePoint struct {
+	Date string  `json:"date"` // 2021-09-24
+	Rate float64 `json:"rate"`
+}
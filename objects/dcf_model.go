@@ -0,0 +1,90 @@
+package objects
+
+// DCFInputs - raw data of discounted cash flow model
+type DCFInputs struct {
+	Symbol            string                  `json:"symbol"`
+	IncomeList        []IncomeStatement       `json:"incomeList"`
+	CashFlowList      []CashFlowStatement     `json:"cashFlowList"`
+	BalanceSheetList  []BalanceSheetStatement `json:"balanceSheetList"`
+	EstimateList      []AnalystEstimates      `json:"estimateList"`
+	RiskFreeRate      float64                 `json:"riskFreeRate"`      // 0.04 = 4%
+	MarketRiskPremium float64                 `json:"marketRiskPremium"` // 0.05 = 5%
+	Beta              float64                 `json:"beta"`
+	Price             float64                 `json:"price"`
+	SharesOutstanding float64                 `json:"sharesOutstanding"`
+	FMPDcf            float64                 `json:"fmpDcf"` // FMP own DCF value per share
+}
+
+// DCFScenario - user assumptions, empty fields are derived from history
+type DCFScenario struct {
+	Name                  string    `json:"name"`
+	RevenueGrowth         []float64 `json:"revenueGrowth"` // per projected year, last value repeats
+	EbitMargin            *float64  `json:"ebitMargin"`
+	TaxRate               *float64  `json:"taxRate"`
+	CapexPercent          *float64  `json:"capexPercent"`          // capital expenditure to revenue
+	DepreciationPercent   *float64  `json:"depreciationPercent"`   // depreciation and amortization to revenue
+	WorkingCapitalPercent *float64  `json:"workingCapitalPercent"` // change in working capital to change in revenue
+	TerminalGrowth        *float64  `json:"terminalGrowth"`
+	WACC                  *float64  `json:"wacc"`
+}
+
+// DCFAssumptions - assumptions used for valuation
+type DCFAssumptions struct {
+	RiskFreeRate          float64 `json:"riskFreeRate"`
+	MarketRiskPremium     float64 `json:"marketRiskPremium"`
+	Beta                  float64 `json:"beta"`
+	CostOfEquity          float64 `json:"costOfEquity"`
+	CostOfDebt            float64 `json:"costOfDebt"` // pre-tax
+	TaxRate               float64 `json:"taxRate"`
+	EquityWeight          float64 `json:"equityWeight"`
+	DebtWeight            float64 `json:"debtWeight"`
+	WACC                  float64 `json:"wacc"`
+	TerminalGrowth        float64 `json:"terminalGrowth"`
+	EbitMargin            float64 `json:"ebitMargin"`
+	CapexPercent          float64 `json:"capexPercent"`
+	DepreciationPercent   float64 `json:"depreciationPercent"`
+	WorkingCapitalPercent float64 `json:"workingCapitalPercent"`
+}
+
+// DCFProjection - projected fiscal year
+type DCFProjection struct {
+	Year                        int     `json:"year"`
+	RevenueGrowth               float64 `json:"revenueGrowth"`
+	Revenue                     float64 `json:"revenue"`
+	Ebit                        float64 `json:"ebit"`
+	Nopat                       float64 `json:"nopat"`
+	DepreciationAndAmortization float64 `json:"depreciationAndAmortization"`
+	CapitalExpenditure          float64 `json:"capitalExpenditure"`
+	ChangeInWorkingCapital      float64 `json:"changeInWorkingCapital"`
+	FreeCashFlow                float64 `json:"freeCashFlow"`
+	DiscountFactor              float64 `json:"discountFactor"`
+	PresentValue                float64 `json:"presentValue"`
+}
+
+// DCFValuation ...
+type DCFValuation struct {
+	Symbol               string          `json:"symbol"`
+	Scenario             string          `json:"scenario"`
+	Assumptions          DCFAssumptions  `json:"assumptions"`
+	Projections          []DCFProjection `json:"projections"`
+	SumPresentValue      float64         `json:"sumPresentValue"`
+	TerminalValue        float64         `json:"terminalValue"`
+	PresentTerminalValue float64         `json:"presentTerminalValue"`
+	EnterpriseValue      float64         `json:"enterpriseValue"`
+	NetDebt              float64         `json:"netDebt"`
+	MinorityInterest     float64         `json:"minorityInterest"`
+	EquityValue          float64         `json:"equityValue"`
+	SharesOutstanding    float64         `json:"sharesOutstanding"`
+	IntrinsicValue       float64         `json:"intrinsicValue"` // per share
+	Price                float64         `json:"price"`
+	Upside               float64         `json:"upside"` // intrinsic value to price - 1
+	FMPDcf               float64         `json:"fmpDcf"`
+}
+
+// DCFSensitivity - intrinsic value per share, Values[i][j] for WACCList[i] and TerminalGrowthList[j]
+type DCFSensitivity struct {
+	WACCList           []float64   `json:"waccList"`
+	TerminalGrowthList []float64   `json:"terminalGrowthList"`
+	Values             [][]float64 `json:"values"`
+	Valid              [][]bool    `json:"valid"` // false - terminal growth not below wacc, value is 0
+}
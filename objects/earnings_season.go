@@ -0,0 +1,65 @@
+package objects
+
+// EarningsStatus - state of earnings report in season
+const (
+	EarningsStatusScheduled EarningsStatus = "scheduled" // earning calendar only
+	EarningsStatusConfirmed EarningsStatus = "confirmed" // date confirmed by company
+	EarningsStatusReported  EarningsStatus = "reported"
+)
+
+// EarningsResult - actual value against estimate
+const (
+	EarningsResultNone   EarningsResult = ""
+	EarningsResultBeat   EarningsResult = "beat"
+	EarningsResultMiss   EarningsResult = "miss"
+	EarningsResultInline EarningsResult = "inline"
+)
+
+// EarningsStatus ...
+type EarningsStatus string
+
+// EarningsResult ...
+type EarningsResult string
+
+// EarningsReport - earnings of one symbol in season
+type EarningsReport struct {
+	Symbol              string         `json:"symbol"`
+	Sector              string         `json:"sector"`
+	Date                string         `json:"date"` // 2020-10-28
+	Time                string         `json:"time"` // bmo, amc, dmh
+	FiscalDateEnding    string         `json:"fiscalDateEnding"`
+	Status              EarningsStatus `json:"status"`
+	Eps                 float64        `json:"eps"`
+	EpsEstimated        float64        `json:"epsEstimated"`
+	EpsSurprise         float64        `json:"epsSurprise"`
+	EpsSurprisePercent  float64        `json:"epsSurprisePercent"` // 0.05 = 5% of absolute estimate
+	EpsResult           EarningsResult `json:"epsResult"`
+	Revenue             float64        `json:"revenue"`
+	RevenueEstimated    float64        `json:"revenueEstimated"`
+	RevenueResult       EarningsResult `json:"revenueResult"`
+	AnalystEpsLow       float64        `json:"analystEpsLow"`
+	AnalystEpsHigh      float64        `json:"analystEpsHigh"`
+	NumberAnalystsEps   int64          `json:"numberAnalystsEps"`
+	Reaction            float64        `json:"reaction"`     // close to close return around report
+	ReactionDays        int            `json:"reactionDays"` // 0 - reaction not known yet
+	TranscriptAvailable bool           `json:"transcriptAvailable"`
+	TranscriptDate      string         `json:"transcriptDate"`
+	TranscriptQuarter   int            `json:"transcriptQuarter"`
+	TranscriptYear      int            `json:"transcriptYear"`
+}
+
+// EarningsSectorSummary - beat and miss rates of reported symbols in sector
+type EarningsSectorSummary struct {
+	Sector                 string  `json:"sector"`
+	Scheduled              int     `json:"scheduled"`
+	Reported               int     `json:"reported"`
+	EpsBeat                int     `json:"epsBeat"`
+	EpsMiss                int     `json:"epsMiss"`
+	EpsInline              int     `json:"epsInline"`
+	EpsBeatRate            float64 `json:"epsBeatRate"` // beats to reports with estimate
+	RevenueBeat            int     `json:"revenueBeat"`
+	RevenueMiss            int     `json:"revenueMiss"`
+	RevenueBeatRate        float64 `json:"revenueBeatRate"`
+	AverageSurprisePercent float64 `json:"averageSurprisePercent"`
+	AverageReaction        float64 `json:"averageReaction"` // reports with known reaction
+}
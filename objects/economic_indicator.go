@@ -0,0 +1,75 @@
+package objects
+
+// EconomicIndicatorName - indicator supported by Economics.Indicator
+const (
+	EconomicIndicatorGDP                         EconomicIndicatorName = "GDP"
+	EconomicIndicatorRealGDP                     EconomicIndicatorName = "realGDP"
+	EconomicIndicatorNominalPotentialGDP         EconomicIndicatorName = "nominalPotentialGDP"
+	EconomicIndicatorRealGDPPerCapita            EconomicIndicatorName = "realGDPPerCapita"
+	EconomicIndicatorFederalFunds                EconomicIndicatorName = "federalFunds"
+	EconomicIndicatorCPI                         EconomicIndicatorName = "CPI"
+	EconomicIndicatorInflationRate               EconomicIndicatorName = "inflationRate"
+	EconomicIndicatorInflation                   EconomicIndicatorName = "inflation"
+	EconomicIndicatorRetailSales                 EconomicIndicatorName = "retailSales"
+	EconomicIndicatorConsumerSentiment           EconomicIndicatorName = "consumerSentiment"
+	EconomicIndicatorDurableGoods                EconomicIndicatorName = "durableGoods"
+	EconomicIndicatorUnemploymentRate            EconomicIndicatorName = "unemploymentRate"
+	EconomicIndicatorTotalNonfarmPayroll         EconomicIndicatorName = "totalNonfarmPayroll"
+	EconomicIndicatorInitialClaims               EconomicIndicatorName = "initialClaims"
+	EconomicIndicatorIndustrialProduction        EconomicIndicatorName = "industrialProductionTotalIndex"
+	EconomicIndicatorHousingStarts               EconomicIndicatorName = "newPrivatelyOwnedHousingUnitsStartedTotalUnits"
+	EconomicIndicatorTotalVehicleSales           EconomicIndicatorName = "totalVehicleSales"
+	EconomicIndicatorRetailMoneyFunds            EconomicIndicatorName = "retailMoneyFunds"
+	EconomicIndicatorRecessionProbabilities      EconomicIndicatorName = "smoothedUSRecessionProbabilities"
+	EconomicIndicatorCertificatesOfDeposit3Month EconomicIndicatorName = "3MonthOr90DayRatesAndYieldsCertificatesOfDeposit"
+	EconomicIndicatorCreditCardInterestRate      EconomicIndicatorName = "commercialBankInterestRateOnCreditCardPlansAllAccounts"
+	EconomicIndicatorMortgage30YearFixedRate     EconomicIndicatorName = "30YearFixedRateMortgageAverage"
+	EconomicIndicatorMortgage15YearFixedRate     EconomicIndicatorName = "15YearFixedRateMortgageAverage"
+)
+
+// EconomicFrequency - observation frequency
+const (
+	EconomicFrequencyDaily     EconomicFrequency = "daily"
+	EconomicFrequencyWeekly    EconomicFrequency = "weekly"
+	EconomicFrequencyMonthly   EconomicFrequency = "monthly"
+	EconomicFrequencyQuarterly EconomicFrequency = "quarterly"
+	EconomicFrequencyAnnual    EconomicFrequency = "annual"
+)
+
+// EconomicAggregation - how observations are combined on resampling
+const (
+	EconomicAggregationLast EconomicAggregation = "last"
+	EconomicAggregationMean EconomicAggregation = "mean"
+	EconomicAggregationSum  EconomicAggregation = "sum"
+)
+
+// EconomicIndicatorName ...
+type EconomicIndicatorName string
+
+// EconomicFrequency ...
+type EconomicFrequency string
+
+// EconomicAggregation ...
+type EconomicAggregation string
+
+// EconomicIndicatorInfo - catalogue entry of indicator
+type EconomicIndicatorInfo struct {
+	Name          EconomicIndicatorName `json:"name"`
+	Title         string                `json:"title"`
+	Unit          string                `json:"unit"`
+	Frequency     EconomicFrequency     `json:"frequency"`
+	CalendarEvent string                `json:"calendarEvent"` // event name prefix in economic calendar, empty - not released on calendar
+	ReleaseLag    int                   `json:"releaseLag"`    // days after period end used when calendar has no release
+}
+
+// EconomicObservation - value of indicator for period starting at Date
+type EconomicObservation struct {
+	Date        string  `json:"date"`        // 2021-09-01
+	ReleaseDate string  `json:"releaseDate"` // first publication, 2021-10-13 12:30:00
+	Value       float64 `json:"value"`
+}
+
+// String return string
+func (e EconomicIndicatorName) String() string {
+	return string(e)
+}
@@ -0,0 +1,25 @@
+package objects
+
+// EconomicSurpriseBasis - value actual print is compared with
+const (
+	EconomicSurpriseBasisEstimate EconomicSurpriseBasis = "estimate" // consensus
+	EconomicSurpriseBasisPrevious EconomicSurpriseBasis = "previous" // no consensus, change against previous print
+)
+
+// EconomicSurpriseBasis ...
+type EconomicSurpriseBasis string
+
+// EconomicRelease - printed economic calendar event with surprise score
+type EconomicRelease struct {
+	Event         string                `json:"event"`
+	Country       string                `json:"country"`
+	Date          string                `json:"date"` // 2020-09-16 11:00:00
+	Impact        string                `json:"impact"`
+	Actual        float64               `json:"actual"`
+	Estimate      *float64              `json:"estimate"`
+	Previous      *float64              `json:"previous"`
+	Basis         EconomicSurpriseBasis `json:"basis"`
+	Surprise      float64               `json:"surprise"`      // actual - estimate or actual - previous
+	SurpriseScore float64               `json:"surpriseScore"` // surprise divided by standard deviation of past surprises, 0 without history
+	SampleSize    int                   `json:"sampleSize"`    // past surprises used for score
+}
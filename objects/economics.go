@@ -1,5 +1,7 @@
 package objects
 
+import "encoding/json"
+
 // EconomicsMarketRisk ...
 type EconomicsMarketRisk struct {
 	Country                string  `json:"country"`
@@ -23,8 +25,35 @@ type EconomicsTreasuryRates struct {
 	Year10 float64 `json:"year10"`
 	Year20 float64 `json:"year20"`
 	Year30 float64 `json:"year30"`
+
+	Missing []string `json:"-"` // json names of tenors absent or null in response, 0 is a valid rate
+}
+
+// UnmarshalJSON - decode rates and record missing tenors
+func (r *EconomicsTreasuryRates) UnmarshalJSON(data []byte) error {
+	type rates EconomicsTreasuryRates
+	if err := json.Unmarshal(data, (*rates)(r)); err != nil {
+		return err
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	r.Missing = nil
+	for _, name := range TreasuryRateTenorList {
+		if values[name] == nil {
+			r.Missing = append(r.Missing, name)
+		}
+	}
+
+	return nil
 }
 
+// TreasuryRateTenorList - json names of EconomicsTreasuryRates tenors, shortest first
+var TreasuryRateTenorList = []string{"month1", "month2", "month3", "month6", "year1", "year2", "year3", "year5", "year7", "year10", "year20", "year30"}
+
 // EconomicsIndicator ...
 type EconomicsIndicator struct {
 	Date  string  `json:"date"`
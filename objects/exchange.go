@@ -1,5 +1,7 @@
 package objects
 
+import "encoding/json"
+
 // Exchange ...
 type Exchange struct {
 	StockExchangeName       string             `json:"stockExchangeName"`
@@ -17,14 +19,74 @@ type ExchangeHours struct {
 	ClosingHour string `json:"closingHour"`
 }
 
-// ExchangeHolidays ...
+// ExchangeHolidays - holiday names are keys of payload, some of them are not valid struct tags
 type ExchangeHolidays struct {
-	Year            int    `json:"year"`
-	NewYearsDay     string `json:"New Years Day"`
-	GoodFriday      string `json:"Good Friday"`
-	MemorialDay     string `json:"Memorial Day"`
-	IndependenceDay string `json:"Independence Day"`
-	LaborDay        string `json:"Labor Day"`
-	ThanksgivingDay string `json:"Thanksgiving Day"`
-	Christmas       string `json:"Christmas"`
+	Year                  int    `json:"year"`
+	NewYearsDay           string `json:"-"`
+	MartinLutherKingJrDay string `json:"-"`
+	WashingtonsBirthday   string `json:"-"`
+	GoodFriday            string `json:"-"`
+	MemorialDay           string `json:"-"`
+	Juneteenth            string `json:"-"`
+	IndependenceDay       string `json:"-"`
+	LaborDay              string `json:"-"`
+	ThanksgivingDay       string `json:"-"`
+	Christmas             string `json:"-"`
+}
+
+// Dates - holiday name to date or "not announced" text
+func (h ExchangeHolidays) Dates() map[string]string {
+	dates := make(map[string]string)
+	for name, field := range h.fields() {
+		if len(*field) > 0 {
+			dates[name] = *field
+		}
+	}
+
+	return dates
+}
+
+// UnmarshalJSON ...
+func (h *ExchangeHolidays) UnmarshalJSON(data []byte) error {
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	if year, ok := values["year"].(float64); ok {
+		h.Year = int(year)
+	}
+
+	for name, field := range h.fields() {
+		if value, ok := values[name].(string); ok {
+			*field = value
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON ...
+func (h ExchangeHolidays) MarshalJSON() ([]byte, error) {
+	values := map[string]interface{}{"year": h.Year}
+	for name, date := range h.Dates() {
+		values[name] = date
+	}
+
+	return json.Marshal(values)
+}
+
+func (h *ExchangeHolidays) fields() map[string]*string {
+	return map[string]*string{
+		"New Years Day":                        &h.NewYearsDay,
+		"Martin Luther King, Jr. Day":          &h.MartinLutherKingJrDay,
+		"Washington's Birthday":                &h.WashingtonsBirthday,
+		"Good Friday":                          &h.GoodFriday,
+		"Memorial Day":                         &h.MemorialDay,
+		"Juneteenth National Independence Day": &h.Juneteenth,
+		"Independence Day":                     &h.IndependenceDay,
+		"Labor Day":                            &h.LaborDay,
+		"Thanksgiving Day":                     &h.ThanksgivingDay,
+		"Christmas":                            &h.Christmas,
+	}
 }
@@ -0,0 +1,58 @@
+package objects
+
+// Form13FChange - position change between two 13F filings
+const (
+	Form13FChangeNew       Form13FChange = "new"
+	Form13FChangeClosed    Form13FChange = "closed"
+	Form13FChangeIncreased Form13FChange = "increased"
+	Form13FChangeDecreased Form13FChange = "decreased"
+	Form13FChangeUnchanged Form13FChange = "unchanged"
+)
+
+// Form13FChange ...
+type Form13FChange string
+
+// Form13FPosition - holding of one CUSIP in current and previous quarter
+type Form13FPosition struct {
+	Cusip          string        `json:"cusip"`
+	Symbol         string        `json:"symbol"`
+	NameOfIssuer   string        `json:"nameOfIssuer"`
+	TitleOfClass   string        `json:"titleOfClass"`
+	Change         Form13FChange `json:"change"`
+	PreviousShares int           `json:"previousShares"`
+	Shares         int           `json:"shares"`
+	ShareChange    int           `json:"shareChange"`
+	PreviousValue  int           `json:"previousValue"`
+	Value          int           `json:"value"`
+	ValueChange    int           `json:"valueChange"`
+	PreviousWeight float64       `json:"previousWeight"` // 0.05 = 5% of portfolio value
+	Weight         float64       `json:"weight"`
+}
+
+// Form13FReport - position changes of one manager between two quarters
+type Form13FReport struct {
+	Cik                string            `json:"cik"`
+	Date               string            `json:"date"`
+	PreviousDate       string            `json:"previousDate"`
+	TotalValue         int               `json:"totalValue"`
+	PreviousTotalValue int               `json:"previousTotalValue"`
+	PositionCount      int               `json:"positionCount"`
+	Top10Weight        float64           `json:"top10Weight"`  // weight of ten largest positions
+	Herfindahl         float64           `json:"herfindahl"`   // sum of squared weights, 1 = single position
+	PositionList       []Form13FPosition `json:"positionList"` // sorted by current value, closed positions last
+}
+
+// Form13FConsensus - changes of one CUSIP across managers
+type Form13FConsensus struct {
+	Cusip        string   `json:"cusip"`
+	Symbol       string   `json:"symbol"`
+	NameOfIssuer string   `json:"nameOfIssuer"`
+	BuyerList    []string `json:"buyerList"`  // CIK of managers opened or increased position
+	SellerList   []string `json:"sellerList"` // CIK of managers closed or decreased position
+	NewCount     int      `json:"newCount"`
+	ClosedCount  int      `json:"closedCount"`
+	NetBuyers    int      `json:"netBuyers"`
+	NetShares    int      `json:"netShares"`
+	NetValue     int      `json:"netValue"`
+	HolderCount  int      `json:"holderCount"`
+}
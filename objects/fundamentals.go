@@ -0,0 +1,43 @@
+package objects
+
+// Fundamentals - income, balance sheet and cash flow statements of one period
+type Fundamentals struct {
+	Symbol           string                 `json:"symbol"`
+	Date             string                 `json:"date"`   // 2021-09-25
+	Period           string                 `json:"period"` // FY, Q1 ... Q4, TTM
+	CalendarYear     int                    `json:"calendarYear"`
+	ReportedCurrency string                 `json:"reportedCurrency"`
+	FillingDate      string                 `json:"fillingDate"`
+	Income           *IncomeStatement       `json:"income"`
+	BalanceSheet     *BalanceSheetStatement `json:"balanceSheet"`
+	CashFlow         *CashFlowStatement     `json:"cashFlow"`
+	AsReported       map[string]float64     `json:"asReported"` // numeric fields of full as-reported statement
+	MismatchList     []FundamentalsMismatch `json:"mismatchList"`
+}
+
+// FundamentalsMismatch - standardized value differs from as-reported value
+type FundamentalsMismatch struct {
+	Field        string  `json:"field"`        // standardized field, income.revenue
+	ReportedName string  `json:"reportedName"` // as-reported field, revenues
+	Standardized float64 `json:"standardized"`
+	AsReported   float64 `json:"asReported"`
+	Difference   float64 `json:"difference"` // standardized - as-reported, absolute values for sign-insensitive fields
+}
+
+// FundamentalsRestatement - value changed between two fetches of the same period
+type FundamentalsRestatement struct {
+	Symbol   string  `json:"symbol"`
+	Date     string  `json:"date"`
+	Period   string  `json:"period"`
+	Field    string  `json:"field"` // income.revenue, balanceSheet.totalAssets, cashFlow.freeCashFlow
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+}
+
+// RequestFundamentals ...
+type RequestFundamentals struct {
+	Symbol     string
+	Period     CompanyValuationPeriod
+	Limit      int64
+	AsReported bool // load full as-reported statements and reconcile
+}
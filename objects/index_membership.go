@@ -0,0 +1,18 @@
+package objects
+
+import "time"
+
+// IndexMembershipInterval - period when symbol was in index, From inclusive and To exclusive
+type IndexMembershipInterval struct {
+	Symbol string     `json:"symbol"`
+	Name   string     `json:"name"`
+	From   *time.Time `json:"from,omitempty"` // nil - member since before available history
+	To     *time.Time `json:"to,omitempty"`   // nil - current member
+}
+
+// IndexTickerChange - same company stays in index under new ticker
+type IndexTickerChange struct {
+	Date      time.Time `json:"date"`
+	OldSymbol string    `json:"oldSymbol"`
+	NewSymbol string    `json:"newSymbol"`
+}
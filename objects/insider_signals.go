@@ -0,0 +1,66 @@
+package objects
+
+// InsiderTradeKind - Form 4 transaction grouped by economic meaning
+const (
+	InsiderTradeKindPurchase       InsiderTradeKind = "purchase"       // P - open market or private purchase
+	InsiderTradeKindSale           InsiderTradeKind = "sale"           // S - open market or private sale
+	InsiderTradeKindOptionExercise InsiderTradeKind = "optionExercise" // M, X, C - exercise or conversion of derivative
+	InsiderTradeKindAward          InsiderTradeKind = "award"          // A - grant or award
+	InsiderTradeKindTaxWithholding InsiderTradeKind = "taxWithholding" // F - payment of exercise price or tax by delivering securities
+	InsiderTradeKindGift           InsiderTradeKind = "gift"           // G - bona fide gift
+	InsiderTradeKindOther          InsiderTradeKind = "other"
+)
+
+// InsiderTradeKind ...
+type InsiderTradeKind string
+
+// InsiderTrade - classified insider transaction
+type InsiderTrade struct {
+	Symbol        string           `json:"symbol"`
+	Date          string           `json:"date"` // transaction date, 2021-09-25
+	ReportingCik  string           `json:"reportingCik"`
+	ReportingName string           `json:"reportingName"`
+	TypeOfOwner   string           `json:"typeOfOwner"`
+	Code          string           `json:"code"` // transaction code, P, S, M ...
+	Kind          InsiderTradeKind `json:"kind"`
+	RoleWeight    float64          `json:"roleWeight"`
+	Shares        float64          `json:"shares"` // negative for dispositions
+	Price         float64          `json:"price"`
+	Value         float64          `json:"value"` // shares * price
+	ReturnList    []InsiderReturn  `json:"returnList"`
+}
+
+// InsiderReturn - close to close return after trade
+type InsiderReturn struct {
+	Days   int     `json:"days"` // trading days after trade date
+	Return float64 `json:"return"`
+}
+
+// InsiderNetActivity - insider activity of symbol in window
+type InsiderNetActivity struct {
+	Symbol           string  `json:"symbol"`
+	From             string  `json:"from"`
+	To               string  `json:"to"`
+	InsiderCount     int     `json:"insiderCount"`
+	BuyCount         int     `json:"buyCount"`
+	SellCount        int     `json:"sellCount"`
+	BuyShares        float64 `json:"buyShares"`
+	SellShares       float64 `json:"sellShares"`
+	BuyValue         float64 `json:"buyValue"`
+	SellValue        float64 `json:"sellValue"`
+	NetShares        float64 `json:"netShares"`
+	NetValue         float64 `json:"netValue"`
+	WeightedNetValue float64 `json:"weightedNetValue"` // net value weighted by insider role
+}
+
+// InsiderCluster - distinct insiders buying within window
+type InsiderCluster struct {
+	Symbol        string         `json:"symbol"`
+	From          string         `json:"from"`
+	To            string         `json:"to"`
+	InsiderList   []string       `json:"insiderList"` // reporting names
+	Shares        float64        `json:"shares"`
+	Value         float64        `json:"value"`
+	WeightedValue float64        `json:"weightedValue"`
+	TradeList     []InsiderTrade `json:"tradeList"`
+}
@@ -0,0 +1,41 @@
+package objects
+
+import "time"
+
+// IntradayWindowStatus - state of download window
+const (
+	IntradayWindowPending IntradayWindowStatus = "pending"
+	IntradayWindowDone    IntradayWindowStatus = "done"
+	IntradayWindowFailed  IntradayWindowStatus = "failed"
+)
+
+// IntradayWindowStatus ...
+type IntradayWindowStatus string
+
+// IntradayWindow - date range loaded with one request
+type IntradayWindow struct {
+	From       string               `json:"from"` // 2021-11-26
+	To         string               `json:"to"`
+	Status     IntradayWindowStatus `json:"status"`
+	Bars       int                  `json:"bars"`
+	Attempts   int                  `json:"attempts"`
+	Checkpoint bool                 `json:"checkpoint"` // loaded from checkpoint
+	Error      string               `json:"error"`
+}
+
+// IntradayGap - missing bars of trading session
+type IntradayGap struct {
+	Session string    `json:"session"` // 2021-11-26
+	From    time.Time `json:"from"`    // first missing bar
+	To      time.Time `json:"to"`      // last missing bar
+	Bars    int       `json:"bars"`
+}
+
+// IntradayDownload - stitched bars of long range, oldest first
+type IntradayDownload struct {
+	Symbol     string           `json:"symbol"`
+	Resolution Resolution       `json:"resolution"`
+	Bars       []Bar            `json:"bars"`
+	Windows    []IntradayWindow `json:"windows"`
+	Gaps       []IntradayGap    `json:"gaps"`
+}
@@ -0,0 +1,60 @@
+package objects
+
+// IPOStatus - stage of IPO, withdrawn is final
+const (
+	IPOStatusFiled     IPOStatus = "filed"     // registration statement filed
+	IPOStatusConfirmed IPOStatus = "confirmed" // registration declared effective
+	IPOStatusPriced    IPOStatus = "priced"    // offer price set
+	IPOStatusListed    IPOStatus = "listed"    // trading started
+	IPOStatusPostponed IPOStatus = "postponed" // IPO date postponed, may still be priced and listed
+	IPOStatusWithdrawn IPOStatus = "withdrawn"
+)
+
+// IPOStatus ...
+type IPOStatus string
+
+// IPOStatusChange - status transition with date of source event
+type IPOStatusChange struct {
+	Status IPOStatus `json:"status"`
+	Date   string    `json:"date"`
+}
+
+// IPORecord - IPO of company merged from calendar, confirmed and prospectus endpoints
+type IPORecord struct {
+	Symbol            string            `json:"symbol"`
+	Cik               string            `json:"cik"`
+	Company           string            `json:"company"`
+	Exchange          string            `json:"exchange"`
+	Status            IPOStatus         `json:"status"`
+	StatusHistory     []IPOStatusChange `json:"statusHistory"`
+	IpoDate           string            `json:"ipoDate"` // expected or actual first trading date
+	FilingDate        string            `json:"filingDate"`
+	EffectivenessDate string            `json:"effectivenessDate"`
+	FilingURL         string            `json:"filingURL"`
+	ProspectusURL     string            `json:"prospectusURL"`
+	Shares            int64             `json:"shares"`
+	PriceRange        string            `json:"priceRange"`
+	PriceLow          float64           `json:"priceLow"`
+	PriceHigh         float64           `json:"priceHigh"`
+	MarketCap         float64           `json:"marketCap"`
+
+	// Prospectus financials
+	OfferPrice                      float64 `json:"offerPrice"`
+	GrossProceeds                   float64 `json:"grossProceeds"`
+	DiscountsAndCommissionsPerShare float64 `json:"discountsAndCommissionsPerShare"`
+	DiscountsAndCommissionsTotal    float64 `json:"discountsAndCommissionsTotal"`
+	NetProceeds                     float64 `json:"netProceeds"` // proceeds before expenses
+
+	// Performance after listing, returns from offer price or first open without offer price
+	ListedDate     string   `json:"listedDate"`
+	FirstOpen      float64  `json:"firstOpen"`
+	FirstClose     float64  `json:"firstClose"`
+	FirstDayReturn *float64 `json:"firstDayReturn"`
+	Return30D      *float64 `json:"return30D"` // nil until 30 calendar days after listing
+	Return90D      *float64 `json:"return90D"`
+}
+
+// String return string
+func (i IPOStatus) String() string {
+	return string(i)
+}
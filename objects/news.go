@@ -0,0 +1,41 @@
+package objects
+
+// NewsSource - feed of news item
+const (
+	NewsSourceStockNews     NewsSource = "stockNews"     // CompanyValuation.StockNews
+	NewsSourcePressRelease  NewsSource = "pressRelease"  // CompanyValuation.PressReleases
+	NewsSourceSECFiling     NewsSource = "secFiling"     // CompanyValuation.RssFeed
+	NewsSourceInsiderFiling NewsSource = "insiderFiling" // InsiderTrading.RSSFeed
+)
+
+// NewsSentiment - label of headline sentiment score
+const (
+	NewsSentimentPositive NewsSentiment = "positive"
+	NewsSentimentNeutral  NewsSentiment = "neutral"
+	NewsSentimentNegative NewsSentiment = "negative"
+)
+
+// NewsSource ...
+type NewsSource string
+
+// NewsSentiment ...
+type NewsSentiment string
+
+// NewsItem - news, press release or filing in one shape
+type NewsItem struct {
+	ID             string        `json:"id"` // hash of source, url or date and title
+	Source         NewsSource    `json:"source"`
+	Site           string        `json:"site"`
+	SymbolList     []string      `json:"symbolList"`
+	PublishedDate  string        `json:"publishedDate"` // 2020-09-15 14:51:03
+	Title          string        `json:"title"`
+	Text           string        `json:"text"`
+	URL            string        `json:"url"`
+	Sentiment      float64       `json:"sentiment"` // -1 .. 1
+	SentimentLabel NewsSentiment `json:"sentimentLabel"`
+}
+
+// String return string
+func (n NewsSource) String() string {
+	return string(n)
+}
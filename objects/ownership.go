@@ -0,0 +1,50 @@
+package objects
+
+// OwnershipSource - dataset holder position comes from
+const (
+	OwnershipSourceInstitutional OwnershipSource = "institutional" // CompanyValuation.InstitutionalHolders
+	OwnershipSourceMutualFund    OwnershipSource = "mutualFund"    // CompanyValuation.MutualFundHolders
+	OwnershipSourceETF           OwnershipSource = "etf"           // CompanyValuation.ETFStockExposure
+	OwnershipSourceForm13F       OwnershipSource = "form13F"       // Form13F.ThirteenList of requested managers
+)
+
+// OwnershipSource ...
+type OwnershipSource string
+
+// OwnershipHolder - position of one holder in security
+type OwnershipHolder struct {
+	Rank             int             `json:"rank" csv:"rank"`
+	Holder           string          `json:"holder" csv:"holder"` // name or ETF symbol
+	Source           OwnershipSource `json:"source" csv:"source"`
+	DateReported     string          `json:"dateReported" csv:"dateReported"`
+	Shares           int64           `json:"shares" csv:"shares"`
+	PreviousShares   int64           `json:"previousShares" csv:"previousShares"`
+	ChangeUnknown    bool            `json:"changeUnknown" csv:"changeUnknown"` // source has no previous shares, change is 0
+	Change           int64           `json:"change" csv:"change"`
+	ChangePercent    float64         `json:"changePercent" csv:"changePercent"`       // 0.1 = 10%, 0 for new positions
+	PercentOfFloat   float64         `json:"percentOfFloat" csv:"percentOfFloat"`     // 0.01 = 1%
+	PortfolioPercent float64         `json:"portfolioPercent" csv:"portfolioPercent"` // weight in holder portfolio, as reported
+}
+
+// OwnershipSummary - totals of one source
+type OwnershipSummary struct {
+	Source         OwnershipSource `json:"source"`
+	HolderCount    int             `json:"holderCount"`
+	Shares         int64           `json:"shares"`
+	Change         int64           `json:"change"` // quarter over quarter
+	PercentOfFloat float64         `json:"percentOfFloat"`
+	NewCount       int             `json:"newCount"`
+	ClosedCount    int             `json:"closedCount"`
+	IncreasedCount int             `json:"increasedCount"`
+	DecreasedCount int             `json:"decreasedCount"`
+}
+
+// Ownership - holders of security across sources.
+// Sources overlap (funds file 13F through their managers), totals are per source.
+type Ownership struct {
+	Symbol            string             `json:"symbol"`
+	FloatShares       int64              `json:"floatShares"`
+	OutstandingShares int64              `json:"outstandingShares"`
+	SummaryList       []OwnershipSummary `json:"summaryList"`
+	HolderList        []OwnershipHolder  `json:"holderList"` // ranked by shares
+}
@@ -0,0 +1,25 @@
+package objects
+
+// ScreenerDataset - bulk datasets joined by local screener
+const (
+	ScreenerDatasetProfile ScreenerDataset = "profile" // Stock.BulkProfile
+	ScreenerDatasetRatios  ScreenerDataset = "ratios"  // CompanyValuation.RatiosTTMBulk
+	ScreenerDatasetMetrics ScreenerDataset = "metrics" // CompanyValuation.BulkKeyMetrics
+	ScreenerDatasetScores  ScreenerDataset = "scores"  // CompanyValuation.BulkScores
+	ScreenerDatasetRating  ScreenerDataset = "rating"  // CompanyValuation.BulkRating
+	ScreenerDatasetDCF     ScreenerDataset = "dcf"     // CompanyValuation.DCFBulk
+)
+
+// ScreenerDataset ...
+type ScreenerDataset string
+
+// ScreenerRow - matched symbol with requested columns
+type ScreenerRow struct {
+	Symbol string                 `json:"symbol"`
+	Values map[string]interface{} `json:"values"` // column expression => float64, string, bool or nil
+}
+
+// String return string
+func (s ScreenerDataset) String() string {
+	return string(s)
+}
@@ -0,0 +1,34 @@
+package objects
+
+// SECFormType - form family of filing, amendments belong to form of original
+const (
+	SECForm10K   SECFormType = "10-K"
+	SECForm10Q   SECFormType = "10-Q"
+	SECForm8K    SECFormType = "8-K"
+	SECFormS1    SECFormType = "S-1"
+	SECForm13D   SECFormType = "13D" // SC 13D beneficial ownership over 5% with intent
+	SECForm13G   SECFormType = "13G" // SC 13G passive beneficial ownership over 5%
+	SECFormOther SECFormType = "other"
+)
+
+// SECFormType ...
+type SECFormType string
+
+// SECFilingEvent - new filing found by watcher
+type SECFilingEvent struct {
+	Accession    string      `json:"accession"` // 0000320193-20-000096
+	Symbol       string      `json:"symbol"`
+	Cik          string      `json:"cik"`
+	Form         SECFormType `json:"form"`
+	Type         string      `json:"type"` // type as filed, 10-K/A, SC 13G ...
+	Amendment    bool        `json:"amendment"`
+	FillingDate  string      `json:"fillingDate"`
+	AcceptedDate string      `json:"acceptedDate"`
+	Link         string      `json:"link"`
+	FinalLink    string      `json:"finalLink"`
+}
+
+// String return string
+func (s SECFormType) String() string {
+	return string(s)
+}
@@ -0,0 +1,75 @@
+package objects
+
+import "time"
+
+// SecurityType - stock, etf, trust and fund come from stock list type
+const (
+	SecurityTypeStock  SecurityType = "stock"
+	SecurityTypeETF    SecurityType = "etf"
+	SecurityTypeTrust  SecurityType = "trust"
+	SecurityTypeFund   SecurityType = "fund"
+	SecurityTypeCrypto SecurityType = "crypto"
+	SecurityTypeForex  SecurityType = "forex"
+)
+
+// SecurityIdentifier - kind of security identifier
+const (
+	SecurityIdentifierTicker SecurityIdentifier = "ticker"
+	SecurityIdentifierCik    SecurityIdentifier = "cik"
+	SecurityIdentifierCusip  SecurityIdentifier = "cusip"
+	SecurityIdentifierIsin   SecurityIdentifier = "isin"
+)
+
+// SecurityChangeKind - change of security master between refreshes
+const (
+	SecurityChangeAdded    SecurityChangeKind = "added"
+	SecurityChangeRemoved  SecurityChangeKind = "removed" // missing from symbol list
+	SecurityChangeRenamed  SecurityChangeKind = "renamed"
+	SecurityChangeDelisted SecurityChangeKind = "delisted"
+	SecurityChangeRelisted SecurityChangeKind = "relisted" // listed by primary source after delisting date
+)
+
+// SecurityType ...
+type SecurityType string
+
+// SecurityIdentifier ...
+type SecurityIdentifier string
+
+// SecurityChangeKind ...
+type SecurityChangeKind string
+
+// Security - symbol metadata merged from symbol lists and identifier mappers
+type Security struct {
+	Symbol            string       `json:"symbol"`
+	Name              string       `json:"name"`
+	Type              SecurityType `json:"type"`
+	Exchange          string       `json:"exchange"`
+	ExchangeShortName string       `json:"exchangeShortName"`
+	Currency          string       `json:"currency"`
+	Cik               string       `json:"cik"` // 10 digits with leading zeros
+	Cusip             string       `json:"cusip"`
+	Isin              string       `json:"isin"`
+	Active            bool         `json:"active"`            // present in latest symbol list
+	Sources           []string     `json:"sources,omitempty"` // symbol lists of latest refresh listing security
+	Tradable          bool         `json:"tradable"`
+	HasStatements     bool         `json:"hasStatements"`
+	Delisted          bool         `json:"delisted"`
+	IpoDate           string       `json:"ipoDate"`
+	DelistedDate      string       `json:"delistedDate"`
+	FirstSeen         time.Time    `json:"firstSeen"`
+	UpdatedAt         time.Time    `json:"updatedAt"`
+}
+
+// SecurityMatch - result of security search, score from 0 to 1
+type SecurityMatch struct {
+	Security Security `json:"security"`
+	Score    float64  `json:"score"`
+}
+
+// SecurityChange - security added, removed, renamed or delisted by refresh
+type SecurityChange struct {
+	Symbol   string             `json:"symbol"`
+	Kind     SecurityChangeKind `json:"kind"`
+	Name     string             `json:"name"`
+	Previous string             `json:"previous"` // previous name of renamed security
+}
@@ -0,0 +1,15 @@
+package objects
+
+import "time"
+
+// TimeSeriesRange - dates from..to inclusive
+type TimeSeriesRange struct {
+	From string `json:"from"` // 2021-11-26
+	To   string `json:"to"`
+}
+
+// TimeSeriesMeta - ranges of series loaded from API
+type TimeSeriesMeta struct {
+	Ranges  []TimeSeriesRange `json:"ranges"`
+	Updated time.Time         `json:"updated"`
+}
@@ -0,0 +1,18 @@
+package objects
+
+import "time"
+
+// TradingSession - regular session of exchange on one date
+type TradingSession struct {
+	Date       string    `json:"date"` // 2021-11-26 in exchange time zone
+	Open       time.Time `json:"open"`
+	Close      time.Time `json:"close"`
+	EarlyClose bool      `json:"earlyClose"`
+}
+
+// TradingHoliday - full day closure or early close of exchange
+type TradingHoliday struct {
+	Date       string `json:"date"`
+	Name       string `json:"name"`
+	EarlyClose bool   `json:"earlyClose"`
+}
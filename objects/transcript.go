@@ -0,0 +1,74 @@
+package objects
+
+// TranscriptRole - role of speaker on earnings call
+const (
+	TranscriptRoleOperator  TranscriptRole = "operator"
+	TranscriptRoleExecutive TranscriptRole = "executive"
+	TranscriptRoleAnalyst   TranscriptRole = "analyst"
+)
+
+// TranscriptSection - part of earnings call
+const (
+	TranscriptSectionPrepared TranscriptSection = "prepared" // prepared remarks
+	TranscriptSectionQA       TranscriptSection = "qa"       // questions and answers
+)
+
+// TranscriptRole ...
+type TranscriptRole string
+
+// TranscriptSection ...
+type TranscriptSection string
+
+// TranscriptTurn - uninterrupted remarks of one speaker
+type TranscriptTurn struct {
+	Index   int               `json:"index"`
+	Speaker string            `json:"speaker"`
+	Role    TranscriptRole    `json:"role"`
+	Section TranscriptSection `json:"section"`
+	Text    string            `json:"text"`
+}
+
+// ParsedTranscript - earnings call split into speaker turns
+type ParsedTranscript struct {
+	Symbol   string           `json:"symbol"`
+	Quarter  int              `json:"quarter"`
+	Year     int              `json:"year"`
+	Date     string           `json:"date"` // 2020-07-31 17:00:00
+	TurnList []TranscriptTurn `json:"turnList"`
+}
+
+// TranscriptHit - match of transcript search
+type TranscriptHit struct {
+	Symbol    string            `json:"symbol"`
+	Quarter   int               `json:"quarter"`
+	Year      int               `json:"year"`
+	Date      string            `json:"date"`
+	TurnIndex int               `json:"turnIndex"`
+	Speaker   string            `json:"speaker"`
+	Role      TranscriptRole    `json:"role"`
+	Section   TranscriptSection `json:"section"`
+	Position  int               `json:"position"` // word position of match in turn
+	Snippet   string            `json:"snippet"`
+}
+
+// TranscriptKeywordCount - keyword frequency in one earnings call
+type TranscriptKeywordCount struct {
+	Symbol    string  `json:"symbol"`
+	Quarter   int     `json:"quarter"`
+	Year      int     `json:"year"`
+	Date      string  `json:"date"`
+	Keyword   string  `json:"keyword"`
+	Count     int     `json:"count"`
+	WordCount int     `json:"wordCount"`
+	Frequency float64 `json:"frequency"` // occurrences per 10000 words
+}
+
+// String return string
+func (t TranscriptRole) String() string {
+	return string(t)
+}
+
+// String return string
+func (t TranscriptSection) String() string {
+	return string(t)
+}
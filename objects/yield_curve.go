@@ -0,0 +1,43 @@
+package objects
+
+// YieldCurveMethod - interpolation between treasury tenors
+const (
+	YieldCurveMethodLinear               YieldCurveMethod = "linear"
+	YieldCurveMethodCubicSpline          YieldCurveMethod = "cubicSpline" // natural cubic spline
+	YieldCurveMethodNelsonSiegelSvensson YieldCurveMethod = "nelsonSiegelSvensson"
+)
+
+// YieldCurveMethod ...
+type YieldCurveMethod string
+
+// YieldCurvePoint - observed rate of tenor
+type YieldCurvePoint struct {
+	Tenor float64 `json:"tenor"` // years, 0.25 = 3 months
+	Rate  float64 `json:"rate"`  // percent, 4.5 = 4.5%
+}
+
+// YieldCurveNSS - Nelson-Siegel-Svensson parameters, rates in percent
+type YieldCurveNSS struct {
+	Beta0 float64 `json:"beta0"`
+	Beta1 float64 `json:"beta1"`
+	Beta2 float64 `json:"beta2"`
+	Beta3 float64 `json:"beta3"`
+	Tau1  float64 `json:"tau1"`
+	Tau2  float64 `json:"tau2"`
+	RMSE  float64 `json:"rmse"` // fit error on observed tenors
+}
+
+// YieldCurveShape - curve shape metrics of one date
+type YieldCurveShape struct {
+	Date          string  `json:"date"`
+	Month3        float64 `json:"month3"`
+	Year2         float64 `json:"year2"`
+	Year10        float64 `json:"year10"`
+	Year30        float64 `json:"year30"`
+	Spread2s10s   float64 `json:"spread2s10s"`   // 10 year - 2 year, percentage points
+	Spread3m10y   float64 `json:"spread3m10y"`   // 10 year - 3 month
+	Spread10s30s  float64 `json:"spread10s30s"`  // 30 year - 10 year
+	Butterfly     float64 `json:"butterfly"`     // 2 * 10 year - 2 year - 30 year, curvature
+	Inverted2s10s bool    `json:"inverted2s10s"` // 2s10s spread below zero
+	Inverted3m10y bool    `json:"inverted3m10y"`
+}
@@ -0,0 +1,272 @@
+package fmpcloud
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// OwnershipConfig for load ownership of security
+type OwnershipConfig struct {
+	Symbol         string
+	Top            int       // holders kept in HolderList, 0 - all
+	ManagerCikList []string  // 13F filers added as separate source
+	Cusip          string    // matches 13F rows without ticker, optional
+	Date           time.Time // 13F filings of latest quarter filed by date, default now
+}
+
+// LoadOwnership - merge institutional, mutual fund, ETF and 13F holders of security
+func LoadOwnership(client *APIClient, cfg OwnershipConfig) (*objects.Ownership, error) {
+	if len(cfg.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	if cfg.Date.IsZero() {
+		cfg.Date = time.Now()
+	}
+
+	floatList, err := client.CompanyValuation.SharesFloat(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load shares float")
+	}
+
+	institutionalList, err := client.CompanyValuation.InstitutionalHolders(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load institutional holders")
+	}
+
+	mutualFundList, err := client.CompanyValuation.MutualFundHolders(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load mutual fund holders")
+	}
+
+	// ETFHolders lists assets of an ETF, funds holding the symbol come from stock exposure
+	etfList, err := client.CompanyValuation.ETFStockExposure(cfg.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load ETF stock exposure")
+	}
+
+	var holderList []objects.OwnershipHolder
+	for _, h := range institutionalList {
+		holderList = append(holderList, objects.OwnershipHolder{
+			Holder:         h.Holder,
+			Source:         objects.OwnershipSourceInstitutional,
+			DateReported:   h.DateReported,
+			Shares:         int64(h.Shares),
+			PreviousShares: int64(h.Shares - h.Change),
+		})
+	}
+
+	for _, h := range mutualFundList {
+		holderList = append(holderList, objects.OwnershipHolder{
+			Holder:           h.Holder,
+			Source:           objects.OwnershipSourceMutualFund,
+			DateReported:     h.DateReported,
+			Shares:           h.Shares,
+			PreviousShares:   h.Shares - h.Change,
+			PortfolioPercent: h.WeightPercent,
+		})
+	}
+
+	for _, h := range etfList {
+		holderList = append(holderList, objects.OwnershipHolder{
+			Holder:           h.ETFSymbol,
+			Source:           objects.OwnershipSourceETF,
+			Shares:           int64(h.SharesNumber),
+			ChangeUnknown:    true,
+			PortfolioPercent: h.WeightPercentage,
+		})
+	}
+
+	managers := make(map[string]bool)
+	for _, cik := range cfg.ManagerCikList {
+		report, err := managerPositions(client, cik, cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load 13F of %s", cik)
+		}
+
+		name, err := managerName(client, cik)
+		if err != nil {
+			return nil, err
+		}
+
+		managers[normalizeSecurityName(name)] = true
+		for _, p := range report.PositionList {
+			holderList = append(holderList, objects.OwnershipHolder{
+				Holder:           name,
+				Source:           objects.OwnershipSourceForm13F,
+				DateReported:     report.Date,
+				Shares:           int64(p.Shares),
+				PreviousShares:   int64(p.PreviousShares),
+				PortfolioPercent: p.Weight * 100,
+			})
+		}
+	}
+
+	// Requested managers replace their institutional holder rows, so they are ranked once
+	if len(managers) > 0 {
+		list := holderList[:0]
+		for _, h := range holderList {
+			if h.Source != objects.OwnershipSourceInstitutional || !managers[normalizeSecurityName(h.Holder)] {
+				list = append(list, h)
+			}
+		}
+
+		holderList = list
+	}
+
+	ownership := BuildOwnership(cfg.Symbol, floatList, holderList)
+	if cfg.Top > 0 && len(ownership.HolderList) > cfg.Top {
+		ownership.HolderList = ownership.HolderList[:cfg.Top]
+	}
+
+	return ownership, nil
+}
+
+// managerName - name of 13F filer, CIK when name is unknown
+func managerName(client *APIClient, cik string) (string, error) {
+	fList, err := client.Form13F.GetCompanyByCIK(cik)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error load name of %s", cik)
+	}
+
+	for _, f := range fList {
+		if len(f.Name) > 0 {
+			return f.Name, nil
+		}
+	}
+
+	return cik, nil
+}
+
+// managerPositions - change of manager positions in symbol in latest filed quarter. Positions are
+// matched by ticker or CUSIP, other holdings of portfolio are not mapped with CusipMapper.
+func managerPositions(client *APIClient, cik string, cfg OwnershipConfig) (objects.Form13FReport, error) {
+	quarterDate, current, err := client.Form13F.latestThirteen(cik, cfg.Date)
+	if err != nil {
+		return objects.Form13FReport{}, err
+	}
+
+	previousDate := previousQuarterEnd(quarterDate)
+
+	previous, err := client.Form13F.ThirteenList(cik, &previousDate)
+	if err != nil {
+		return objects.Form13FReport{}, err
+	}
+
+	cusips := make(map[string]bool)
+	if len(cfg.Cusip) > 0 {
+		cusips[strings.ToUpper(cfg.Cusip)] = true
+	}
+
+	for _, list := range [][]objects.Thirteen{current, previous} {
+		for _, row := range list {
+			if strings.EqualFold(row.Tickercusip, cfg.Symbol) {
+				cusips[strings.ToUpper(row.Cusip)] = true
+			}
+		}
+	}
+
+	// Weights are relative to the whole portfolio, so positions are filtered after diff
+	report := DiffThirteen(previous, current)
+	if len(report.Date) == 0 {
+		report.Date = quarterDate.Format(layoutDate)
+	}
+
+	positionList := report.PositionList[:0]
+	for _, p := range report.PositionList {
+		if cusips[strings.ToUpper(p.Cusip)] {
+			p.Symbol = cfg.Symbol
+			positionList = append(positionList, p)
+		}
+	}
+
+	report.PositionList = positionList
+
+	return report, nil
+}
+
+// BuildOwnership - rank holders, fill changes and percent of float, summarize per source
+func BuildOwnership(symbol string, floatList []objects.SharesFloat, holderList []objects.OwnershipHolder) *objects.Ownership {
+	ownership := &objects.Ownership{Symbol: symbol}
+	for _, f := range floatList {
+		if f.Symbol == "" || strings.EqualFold(f.Symbol, symbol) {
+			ownership.FloatShares = f.FloatShares
+			ownership.OutstandingShares = f.OutstandingShares
+			break
+		}
+	}
+
+	summaries := make(map[objects.OwnershipSource]*objects.OwnershipSummary)
+	sourceList := make([]objects.OwnershipSource, 0)
+	for i := range holderList {
+		h := &holderList[i]
+		h.Change = h.Shares - h.PreviousShares
+		if h.ChangeUnknown {
+			h.PreviousShares, h.Change = 0, 0
+		}
+
+		h.ChangePercent = 0
+		if h.PreviousShares > 0 {
+			h.ChangePercent = float64(h.Change) / float64(h.PreviousShares)
+		}
+
+		h.PercentOfFloat = 0
+		if ownership.FloatShares > 0 {
+			h.PercentOfFloat = float64(h.Shares) / float64(ownership.FloatShares)
+		}
+
+		s, ok := summaries[h.Source]
+		if !ok {
+			s = &objects.OwnershipSummary{Source: h.Source}
+			summaries[h.Source] = s
+			sourceList = append(sourceList, h.Source)
+		}
+
+		if h.Shares > 0 {
+			s.HolderCount++
+		}
+
+		s.Shares += h.Shares
+		s.Change += h.Change
+		s.PercentOfFloat += h.PercentOfFloat
+
+		switch {
+		case h.ChangeUnknown:
+		case h.PreviousShares == 0 && h.Shares > 0:
+			s.NewCount++
+		case h.Shares == 0 && h.PreviousShares > 0:
+			s.ClosedCount++
+		case h.Change > 0:
+			s.IncreasedCount++
+		case h.Change < 0:
+			s.DecreasedCount++
+		}
+	}
+
+	for _, source := range sourceList {
+		ownership.SummaryList = append(ownership.SummaryList, *summaries[source])
+	}
+
+	sort.SliceStable(holderList, func(i, j int) bool {
+		return holderList[i].Shares > holderList[j].Shares
+	})
+
+	for i := range holderList {
+		holderList[i].Rank = i + 1
+	}
+
+	ownership.HolderList = holderList
+
+	return ownership
+}
+
+// WriteOwnership - write holders table as CSV
+func WriteOwnership(w io.Writer, ownership *objects.Ownership) error {
+	return gocsv.Marshal(ownership.HolderList, w)
+}
@@ -0,0 +1,73 @@
+package fmpcloud
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestOwnership(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v4/shares_float": `[{"symbol":"TEST","floatShares":1000,"outstandingShares":1200}]`,
+		"/v3/institutional-holder/TEST": `[
+			{"holder":"Big Bank","shares":200,"dateReported":"2021-12-31","change":50},
+			{"holder":"Gone Fund","shares":0,"dateReported":"2021-12-31","change":-30}]`,
+		"/v3/mutual-fund-holder/TEST": `[{"holder":"Index Fund","shares":100,"dateReported":"2021-12-31","change":100,"weightPercent":1.5}]`,
+		"/v3/etf-stock-exposure/TEST": `[{"etfSymbol":"ETF","assetExposure":"TEST","sharesNumber":50,"weightPercentage":0.4}]`,
+		"/v3/form-thirteen/0001?date=2021-12-31": `[{"date":"2021-12-31","cik":"0001","cusip":"T1","tickercusip":"TEST","shares":300,"value":30},
+			{"date":"2021-12-31","cik":"0001","cusip":"X9","shares":5000,"value":270}]`,
+		"/v3/form-thirteen/0001?date=2021-09-30": `[{"date":"2021-09-30","cik":"0001","cusip":"T1","tickercusip":"TEST","shares":400,"value":40}]`,
+		"/v3/form-thirteen/0001?date=2022-03-31": `[]`,
+		"/v3/cik/0001":                           `[{"cik":"0001","name":"BIG BANK"}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ownership, err := LoadOwnership(APIClient, OwnershipConfig{
+		Symbol: "TEST", ManagerCikList: []string{"0001"}, Date: time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Unmapped holdings of portfolio are skipped without CUSIP requests, quarter not filed yet
+	// falls back to latest filed quarter and 13F manager replaces its institutional holder row
+	if ownership.FloatShares != 1000 || len(ownership.HolderList) != 4 || len(ownership.SummaryList) != 4 ||
+		ownership.HolderList[0].PortfolioPercent != 10 {
+		t.Fatalf("unexpected ownership: %+v", ownership)
+	}
+
+	top := ownership.HolderList[0]
+	if top.Holder != "BIG BANK" || top.Source != objects.OwnershipSourceForm13F || top.Change != -100 || math.Abs(top.PercentOfFloat-0.3) > 1e-9 {
+		t.Fatalf("unexpected top holder: %+v", top)
+	}
+
+	institutional := ownership.SummaryList[0]
+	if institutional.Shares != 0 || institutional.Change != -30 || institutional.ClosedCount != 1 || institutional.IncreasedCount != 0 || institutional.HolderCount != 0 {
+		t.Fatalf("unexpected institutional summary: %+v", institutional)
+	}
+
+	if ownership.SummaryList[1].NewCount != 1 || ownership.HolderList[1].ChangePercent != 0 {
+		t.Fatalf("unexpected mutual fund summary: %+v", ownership.SummaryList[1])
+	}
+
+	// ETF exposure has no previous shares
+	if etf := ownership.SummaryList[2]; etf.HolderCount != 1 || etf.Change != 0 || etf.NewCount != 0 || !ownership.HolderList[2].ChangeUnknown {
+		t.Fatalf("unexpected ETF summary: %+v %+v", etf, ownership.HolderList[2])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOwnership(&buf, ownership); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "rank,holder,source") || !strings.HasPrefix(lines[1], "1,BIG BANK,form13F") {
+		t.Fatalf("unexpected table: %s", buf.String())
+	}
+}
@@ -0,0 +1,592 @@
+package fmpcloud
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Screener - client-side stock screener over locally ingested bulk data.
+// Rows of every dataset are joined by symbol, fields are addressed as dataset.field
+// (ratios.peRatioTTM) or by bare name when it is unique. Bare symbol and date are join keys:
+// symbol of row and latest date of its datasets. Functions:
+// rank(x), percentile(x), sector_rank(x), sector_percentile(x), group_rank(x, g),
+// group_percentile(x, g), abs(x), min(a, b), max(a, b), coalesce(a, b, ...).
+// Rank is 1 for the highest value, percentile is 0..100 ascending.
+type Screener struct {
+	symbols  []string
+	rows     map[string]map[string]interface{} // symbol => qualified field => value
+	fields   map[string]string                 // lower qualified name => qualified name
+	bare     map[string][]string               // lower bare name => qualified names
+	datasets map[string]bool
+	dates    map[string]string // dataset.symbol => date of ingested row
+	derived  map[string]exprNode
+}
+
+// ScreenerQuery ...
+type ScreenerQuery struct {
+	Filter  string   // boolean expression, empty - all symbols
+	OrderBy string   // expression, empty - by symbol
+	Desc    bool     // descending order
+	Limit   int      // 0 - without limit
+	Columns []string // expressions returned in ScreenerRow.Values
+}
+
+// NewScreener creates a new empty screener
+func NewScreener() *Screener {
+	return &Screener{
+		rows:     make(map[string]map[string]interface{}),
+		fields:   make(map[string]string),
+		bare:     make(map[string][]string),
+		datasets: make(map[string]bool),
+		dates:    make(map[string]string),
+		derived:  make(map[string]exprNode),
+	}
+}
+
+// Load - fetch and ingest all bulk datasets, key metrics are taken for year and period
+func (s *Screener) Load(client *APIClient, year int, period string) error {
+	profiles, err := client.Stock.BulkProfile()
+	if err != nil {
+		return errors.Wrap(err, "Error load bulk profile")
+	}
+
+	ratios, err := client.CompanyValuation.RatiosTTMBulk()
+	if err != nil {
+		return errors.Wrap(err, "Error load bulk ratios ttm")
+	}
+
+	metrics, err := client.CompanyValuation.BulkKeyMetrics(year, period)
+	if err != nil {
+		return errors.Wrap(err, "Error load bulk key metrics")
+	}
+
+	scores, err := client.CompanyValuation.BulkScores()
+	if err != nil {
+		return errors.Wrap(err, "Error load bulk scores")
+	}
+
+	rating, err := client.CompanyValuation.BulkRating()
+	if err != nil {
+		return errors.Wrap(err, "Error load bulk rating")
+	}
+
+	dcf, err := client.CompanyValuation.DCFBulk()
+	if err != nil {
+		return errors.Wrap(err, "Error load bulk dcf")
+	}
+
+	for dataset, rows := range map[objects.ScreenerDataset]interface{}{
+		objects.ScreenerDatasetProfile: profiles,
+		objects.ScreenerDatasetRatios:  ratios,
+		objects.ScreenerDatasetMetrics: metrics,
+		objects.ScreenerDatasetScores:  scores,
+		objects.ScreenerDatasetRating:  rating,
+		objects.ScreenerDatasetDCF:     dcf,
+	} {
+		if err = s.Ingest(dataset, rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Ingest - add slice of structs with Symbol field under dataset name. Field names are taken
+// from json (or csv) tags. When dataset has several rows per symbol the latest by date is kept.
+func (s *Screener) Ingest(dataset objects.ScreenerDataset, rows interface{}) error {
+	name := strings.ToLower(dataset.String())
+	if len(name) == 0 || strings.Contains(name, ".") {
+		return errors.Errorf("Error invalid dataset name %q", dataset)
+	}
+
+	list := reflect.ValueOf(rows)
+	if list.Kind() != reflect.Slice {
+		return errors.Errorf("Error ingest %s: rows must be a slice", dataset)
+	}
+
+	s.datasets[name] = true
+	for i := 0; i < list.Len(); i++ {
+		row := reflect.Indirect(list.Index(i))
+		if row.Kind() != reflect.Struct {
+			return errors.Errorf("Error ingest %s: rows must be structs", dataset)
+		}
+
+		symbolField := row.FieldByName("Symbol")
+		if !symbolField.IsValid() || symbolField.Kind() != reflect.String {
+			return errors.Errorf("Error ingest %s: row has no Symbol field", dataset)
+		}
+
+		symbol := strings.TrimSpace(symbolField.String())
+		if len(symbol) == 0 {
+			continue
+		}
+
+		if date := row.FieldByName("Date"); date.IsValid() && date.Kind() == reflect.String {
+			key := name + "." + symbol
+			if prev, ok := s.dates[key]; ok && prev > date.String() {
+				continue
+			}
+
+			s.dates[key] = date.String()
+		}
+
+		values, ok := s.rows[symbol]
+		if !ok {
+			values = make(map[string]interface{})
+			s.rows[symbol] = values
+			s.symbols = append(s.symbols, symbol)
+		}
+
+		rowType := row.Type()
+		for f := 0; f < row.NumField(); f++ {
+			field := rowType.Field(f)
+			if !field.IsExported() {
+				continue
+			}
+
+			value, ok := screenerValue(row.Field(f))
+			if !ok {
+				continue
+			}
+
+			qualified := name + "." + screenerFieldName(field)
+			lower := strings.ToLower(qualified)
+			if _, known := s.fields[lower]; !known {
+				s.fields[lower] = qualified
+				bare := strings.ToLower(screenerFieldName(field))
+				s.bare[bare] = append(s.bare[bare], qualified)
+			}
+
+			values[qualified] = value
+		}
+	}
+
+	return nil
+}
+
+// Define - derived metric usable by name in other expressions
+func (s *Screener) Define(name string, expression string) error {
+	node, err := parseScreenerExpression(expression)
+	if err != nil {
+		return errors.Wrapf(err, "Error parse %s", name)
+	}
+
+	lower := strings.ToLower(name)
+	s.derived[lower] = node
+	if err = s.check(node, map[string]bool{lower: true}); err != nil {
+		delete(s.derived, lower)
+		return errors.Wrapf(err, "Error define %s", name)
+	}
+
+	return nil
+}
+
+// Fields - qualified names of all ingested fields
+func (s *Screener) Fields() []string {
+	fList := make([]string, 0, len(s.fields))
+	for _, qualified := range s.fields {
+		fList = append(fList, qualified)
+	}
+
+	sort.Strings(fList)
+
+	return fList
+}
+
+// Screen - filter, order and project ingested symbols
+func (s *Screener) Screen(query ScreenerQuery) ([]objects.ScreenerRow, error) {
+	compile := func(expression string) (exprNode, error) {
+		if len(strings.TrimSpace(expression)) == 0 {
+			return nil, nil
+		}
+
+		node, err := parseScreenerExpression(expression)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parse %q", expression)
+		}
+
+		if err = s.check(node, map[string]bool{}); err != nil {
+			return nil, errors.Wrapf(err, "Error check %q", expression)
+		}
+
+		return node, nil
+	}
+
+	filter, err := compile(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, err := compile(query.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]exprNode, len(query.Columns))
+	for i, column := range query.Columns {
+		if columns[i], err = compile(column); err != nil {
+			return nil, err
+		}
+	}
+
+	symbols := append([]string(nil), s.symbols...)
+	sort.Strings(symbols)
+
+	ev := &screenerEvaluator{screener: s, symbols: symbols, cache: make(map[*exprCall][]interface{})}
+
+	var matched []int
+	for i := range symbols {
+		if filter == nil || exprTruth(ev.eval(filter, i)) {
+			matched = append(matched, i)
+		}
+	}
+
+	if orderBy != nil {
+		keys := make(map[int]interface{}, len(matched))
+		for _, i := range matched {
+			keys[i] = ev.eval(orderBy, i)
+		}
+
+		sort.SliceStable(matched, func(a, b int) bool {
+			ka, kb := keys[matched[a]], keys[matched[b]]
+			if ka == nil || kb == nil {
+				// Missing values go last in both directions
+				return ka != nil
+			}
+
+			less, greater := exprTruth(exprApplyBinary("<", ka, kb)), exprTruth(exprApplyBinary(">", ka, kb))
+			if query.Desc {
+				return greater
+			}
+
+			return less
+		})
+	}
+
+	if query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+
+	sList := make([]objects.ScreenerRow, 0, len(matched))
+	for _, i := range matched {
+		row := objects.ScreenerRow{Symbol: symbols[i], Values: make(map[string]interface{}, len(columns))}
+		for c, column := range columns {
+			if column != nil {
+				row.Values[query.Columns[c]] = ev.eval(column, i)
+			}
+		}
+
+		sList = append(sList, row)
+	}
+
+	return sList, nil
+}
+
+// Join keys addressed by bare name
+const (
+	screenerKeySymbol = "symbol"
+	screenerKeyDate   = "date"
+)
+
+// resolve - qualified field name of dataset.field, join key or unique bare name
+func (s *Screener) resolve(name string) (string, error) {
+	lower := strings.ToLower(name)
+	if lower == screenerKeySymbol || lower == screenerKeyDate {
+		return lower, nil
+	}
+
+	if qualified, ok := s.fields[lower]; ok {
+		return qualified, nil
+	}
+
+	if dot := strings.Index(lower, "."); dot > 0 && s.datasets[lower[:dot]] {
+		return "", errors.Errorf("Error unknown field %s", name)
+	}
+
+	switch candidates := s.bare[lower]; len(candidates) {
+	case 0:
+		return "", errors.Errorf("Error unknown field %s", name)
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", errors.Errorf("Error ambiguous field %s: %s", name, strings.Join(candidates, ", "))
+	}
+}
+
+// date - latest date of symbol rows, nil when datasets have no date
+func (s *Screener) date(symbol string) interface{} {
+	latest := ""
+	for dataset := range s.datasets {
+		if date := s.dates[dataset+"."+symbol]; date > latest {
+			latest = date
+		}
+	}
+
+	if len(latest) == 0 {
+		return nil
+	}
+
+	return latest
+}
+
+// check - validate fields, functions and derived metrics cycles
+func (s *Screener) check(node exprNode, visiting map[string]bool) error {
+	switch n := node.(type) {
+	case *exprField:
+		lower := strings.ToLower(n.name)
+		if derived, ok := s.derived[lower]; ok {
+			if visiting[lower] {
+				if _, err := s.resolve(n.name); err == nil {
+					return nil
+				}
+
+				return errors.Errorf("Error cyclic definition of %s", n.name)
+			}
+
+			visiting[lower] = true
+			defer delete(visiting, lower)
+
+			return s.check(derived, visiting)
+		}
+
+		_, err := s.resolve(n.name)
+		return err
+	case *exprUnary:
+		return s.check(n.operand, visiting)
+	case *exprBinary:
+		if err := s.check(n.left, visiting); err != nil {
+			return err
+		}
+
+		return s.check(n.right, visiting)
+	case *exprCall:
+		min, max := screenerFunctionArity(n.name)
+		if min < 0 {
+			return errors.Errorf("Error unknown function %s", n.name)
+		}
+
+		if len(n.args) < min || (max >= 0 && len(n.args) > max) {
+			return errors.Errorf("Error wrong number of arguments for %s", n.name)
+		}
+
+		for _, arg := range n.args {
+			if err := s.check(arg, visiting); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// screenerFunctionArity - min and max number of arguments (-1 - unlimited), min -1 - unknown function
+func screenerFunctionArity(name string) (int, int) {
+	switch name {
+	case "abs", "rank", "percentile", "sector_rank", "sector_percentile":
+		return 1, 1
+	case "group_rank", "group_percentile", "min", "max":
+		return 2, 2
+	case "coalesce":
+		return 1, -1
+	}
+
+	return -1, -1
+}
+
+type screenerEvaluator struct {
+	screener *Screener
+	symbols  []string
+	cache    map[*exprCall][]interface{}
+	visiting map[string]bool
+}
+
+func (ev *screenerEvaluator) eval(node exprNode, i int) interface{} {
+	switch n := node.(type) {
+	case *exprLiteral:
+		return n.value
+	case *exprField:
+		lower := strings.ToLower(n.name)
+		if derived, ok := ev.screener.derived[lower]; ok && !ev.visiting[lower] {
+			if ev.visiting == nil {
+				ev.visiting = make(map[string]bool)
+			}
+
+			ev.visiting[lower] = true
+			defer delete(ev.visiting, lower)
+
+			return ev.eval(derived, i)
+		}
+
+		qualified, err := ev.screener.resolve(n.name)
+		if err != nil {
+			return nil
+		}
+
+		switch qualified {
+		case screenerKeySymbol:
+			return ev.symbols[i]
+		case screenerKeyDate:
+			return ev.screener.date(ev.symbols[i])
+		}
+
+		return ev.screener.rows[ev.symbols[i]][qualified]
+	case *exprUnary:
+		return exprApplyUnary(n.op, ev.eval(n.operand, i))
+	case *exprBinary:
+		return exprApplyBinary(n.op, ev.eval(n.left, i), ev.eval(n.right, i))
+	case *exprCall:
+		return ev.call(n, i)
+	}
+
+	return nil
+}
+
+func (ev *screenerEvaluator) call(n *exprCall, i int) interface{} {
+	switch n.name {
+	case "abs":
+		if f, ok := exprNumber(ev.eval(n.args[0], i)); ok {
+			return math.Abs(f)
+		}
+
+		return nil
+	case "min", "max":
+		a, aok := exprNumber(ev.eval(n.args[0], i))
+		b, bok := exprNumber(ev.eval(n.args[1], i))
+		if !aok || !bok {
+			return nil
+		}
+
+		if n.name == "min" {
+			return math.Min(a, b)
+		}
+
+		return math.Max(a, b)
+	case "coalesce":
+		for _, arg := range n.args {
+			if v := ev.eval(arg, i); v != nil {
+				return v
+			}
+		}
+
+		return nil
+	}
+
+	// Cross-sectional functions are computed once for all symbols
+	if values, ok := ev.cache[n]; ok {
+		return values[i]
+	}
+
+	var group exprNode
+	switch n.name {
+	case "sector_rank", "sector_percentile":
+		group = &exprField{name: objects.ScreenerDatasetProfile.String() + ".sector"}
+	case "group_rank", "group_percentile":
+		group = n.args[1]
+	}
+
+	groups := make(map[string][]int)
+	for j := range ev.symbols {
+		key := ""
+		if group != nil {
+			if v := ev.eval(group, j); v != nil {
+				key = strings.ToLower(strings.TrimSpace(fmt.Sprint(v)))
+			}
+		}
+
+		groups[key] = append(groups[key], j)
+	}
+
+	percentile := strings.HasSuffix(n.name, "percentile")
+	values := make([]interface{}, len(ev.symbols))
+	for _, members := range groups {
+		type item struct {
+			index int
+			value float64
+		}
+
+		var items []item
+		for _, j := range members {
+			if f, ok := exprNumber(ev.eval(n.args[0], j)); ok {
+				items = append(items, item{index: j, value: f})
+			}
+		}
+
+		sort.Slice(items, func(a, b int) bool {
+			return items[a].value < items[b].value
+		})
+
+		// less - count of smaller values, equal - count of ties including itself
+		for start := 0; start < len(items); {
+			end := start
+			for end < len(items) && items[end].value == items[start].value {
+				end++
+			}
+
+			less, equal := start, end-start
+			for _, it := range items[start:end] {
+				switch {
+				case !percentile:
+					values[it.index] = float64(len(items) - less - equal + 1)
+				case len(items) == 1:
+					values[it.index] = 100.0
+				default:
+					values[it.index] = 100 * (float64(less) + float64(equal-1)/2) / float64(len(items)-1)
+				}
+			}
+
+			start = end
+		}
+	}
+
+	ev.cache[n] = values
+
+	return values[i]
+}
+
+// screenerValue - convert struct field to expression value
+func screenerValue(v reflect.Value) (interface{}, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, true
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return v.Bool(), true
+	}
+
+	return nil, false
+}
+
+// screenerFieldName - json tag, csv tag or field name with lower first letter
+func screenerFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "csv"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if len(name) > 0 && name != "-" {
+			return name
+		}
+	}
+
+	runes := []rune(field.Name)
+	runes[0] = unicode.ToLower(runes[0])
+
+	return string(runes)
+}
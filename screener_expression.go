@@ -0,0 +1,483 @@
+package fmpcloud
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Screener expression grammar:
+//
+//	expr    = or
+//	or      = and { ("||" | "or") and }
+//	and     = cmp { ("&&" | "and") cmp }
+//	cmp     = sum [ ("==" | "!=" | "<" | "<=" | ">" | ">=") sum ]
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = ("-" | "!" | "not") unary | primary
+//	primary = number | string | "true" | "false" | field | call | "(" expr ")"
+//	call    = name "(" [ expr { "," expr } ] ")"
+//
+// Field is dataset qualified (ratios.peRatioTTM), join key symbol or date, or bare name when it is
+// unique across datasets.
+
+type exprNode interface{}
+
+type exprLiteral struct {
+	value interface{}
+}
+
+type exprField struct {
+	name string
+}
+
+type exprUnary struct {
+	op      string
+	operand exprNode
+}
+
+type exprBinary struct {
+	op          string
+	left, right exprNode
+}
+
+type exprCall struct {
+	name string
+	args []exprNode
+}
+
+type exprToken struct {
+	kind  byte // n - number, s - string, i - identifier, o - operator
+	text  string
+	value float64
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+// parseScreenerExpression ...
+func parseScreenerExpression(s string) (exprNode, error) {
+	tokens, err := tokenizeScreenerExpression(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, errors.Errorf("Error unexpected token %q in expression", p.tokens[p.pos].text)
+	}
+
+	return node, nil
+}
+
+func tokenizeScreenerExpression(s string) (tokens []exprToken, err error) {
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E' ||
+				((runes[j] == '+' || runes[j] == '-') && (runes[j-1] == 'e' || runes[j-1] == 'E'))) {
+				j++
+			}
+
+			value, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error parse number %q", string(runes[i:j]))
+			}
+
+			tokens = append(tokens, exprToken{kind: 'n', text: string(runes[i:j]), value: value})
+			i = j
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+
+			if j == len(runes) {
+				return nil, errors.New("Error unterminated string in expression")
+			}
+
+			tokens = append(tokens, exprToken{kind: 's', text: string(runes[i+1 : j])})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+
+			tokens = append(tokens, exprToken{kind: 'i', text: string(runes[i:j])})
+			i = j
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+
+			switch op {
+			case "+", "-", "*", "/", "(", ")", ",", "<", ">", "!", "==", "!=", "<=", ">=", "&&", "||":
+			default:
+				return nil, errors.Errorf("Error unexpected character %q in expression", op)
+			}
+
+			tokens = append(tokens, exprToken{kind: 'o', text: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return tokens, nil
+}
+
+func (p *exprParser) peek() *exprToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+
+	return nil
+}
+
+// accept - consume operator or keyword
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t == nil || t.kind == 'n' || t.kind == 's' {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if (t.kind == 'o' && t.text == op) || (t.kind == 'i' && strings.EqualFold(t.text, op)) {
+			p.pos++
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *exprParser) or() (exprNode, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &exprBinary{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) and() (exprNode, error) {
+	left, err := p.cmp()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+
+		right, err := p.cmp()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &exprBinary{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) cmp() (exprNode, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	return &exprBinary{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) sum() (exprNode, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) product() (exprNode, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) unary() (exprNode, error) {
+	if op, ok := p.accept("-", "!", "not"); ok {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		if op == "not" {
+			op = "!"
+		}
+
+		return &exprUnary{op: op, operand: operand}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *exprParser) primary() (exprNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("Error unexpected end of expression")
+	}
+
+	p.pos++
+	switch t.kind {
+	case 'n':
+		return &exprLiteral{value: t.value}, nil
+	case 's':
+		return &exprLiteral{value: t.text}, nil
+	case 'i':
+		switch strings.ToLower(t.text) {
+		case "true":
+			return &exprLiteral{value: true}, nil
+		case "false":
+			return &exprLiteral{value: false}, nil
+		}
+
+		if _, ok := p.accept("("); !ok {
+			return &exprField{name: t.text}, nil
+		}
+
+		call := &exprCall{name: strings.ToLower(t.text)}
+		if _, ok := p.accept(")"); ok {
+			return call, nil
+		}
+
+		for {
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+
+			call.args = append(call.args, arg)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+
+			if _, ok := p.accept(")"); !ok {
+				return nil, errors.Errorf("Error expected ) after arguments of %s", t.text)
+			}
+
+			return call, nil
+		}
+	default:
+		if t.text == "(" {
+			node, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := p.accept(")"); !ok {
+				return nil, errors.New("Error expected ) in expression")
+			}
+
+			return node, nil
+		}
+	}
+
+	return nil, errors.Errorf("Error unexpected token %q in expression", t.text)
+}
+
+// exprNumber - numeric value of operand, missing and non-numeric values are not numbers
+func exprNumber(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, !math.IsNaN(value)
+	case bool:
+		if value {
+			return 1, true
+		}
+
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+
+	return 0, false
+}
+
+// exprTruth - boolean value of operand
+func exprTruth(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case float64:
+		return value != 0 && !math.IsNaN(value)
+	case string:
+		return len(value) > 0
+	}
+
+	return false
+}
+
+func exprApplyUnary(op string, v interface{}) interface{} {
+	if op == "!" {
+		return !exprTruth(v)
+	}
+
+	if f, ok := exprNumber(v); ok {
+		return -f
+	}
+
+	return nil
+}
+
+func exprApplyBinary(op string, left, right interface{}) interface{} {
+	switch op {
+	case "&&":
+		return exprTruth(left) && exprTruth(right)
+	case "||":
+		return exprTruth(left) || exprTruth(right)
+	}
+
+	if left == nil || right == nil {
+		if op == "!=" {
+			return left != right
+		}
+
+		if op == "==" {
+			return left == right
+		}
+
+		if isComparison(op) {
+			return false
+		}
+
+		return nil
+	}
+
+	l, lok := exprNumber(left)
+	r, rok := exprNumber(right)
+	if lok && rok {
+		switch op {
+		case "+":
+			return l + r
+		case "-":
+			return l - r
+		case "*":
+			return l * r
+		case "/":
+			if r == 0 {
+				return nil
+			}
+
+			return l / r
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		}
+	}
+
+	ls, rs := fmt.Sprint(left), fmt.Sprint(right)
+	switch op {
+	case "+":
+		return ls + rs
+	case "==":
+		return strings.EqualFold(ls, rs)
+	case "!=":
+		return !strings.EqualFold(ls, rs)
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	case ">=":
+		return ls >= rs
+	}
+
+	return nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+
+	return false
+}
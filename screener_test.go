@@ -0,0 +1,74 @@
+package fmpcloud
+
+import (
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestScreener(t *testing.T) {
+	screener := NewScreener()
+
+	err := screener.Ingest(objects.ScreenerDatasetProfile, []objects.StockCompanyProfile{
+		{Symbol: "AAA", Sector: "Technology", Price: 100, MktCap: 5e9},
+		{Symbol: "BBB", Sector: "Technology", Price: 50, MktCap: 2e9},
+		{Symbol: "CCC", Sector: "Energy", Price: 10, MktCap: 1e9},
+		{Symbol: "DDD", Sector: "Energy", Price: 20, MktCap: 3e8},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = screener.Ingest(objects.ScreenerDatasetMetrics, []objects.KeyMetrics{
+		{Symbol: "AAA", Date: "2020-12-31", Roe: 0.1, FreeCashFlowPerShare: 1},
+		{Symbol: "AAA", Date: "2021-12-31", Roe: 0.3, FreeCashFlowPerShare: 5},
+		{Symbol: "BBB", Date: "2021-12-31", Roe: 0.2, FreeCashFlowPerShare: 1},
+		{Symbol: "CCC", Date: "2021-12-31", Roe: 0.05, FreeCashFlowPerShare: 2},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err = screener.Define("fcfYield", "metrics.freeCashFlowPerShare / profile.price"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	rows, err := screener.Screen(ScreenerQuery{
+		Filter:  "mktCap > 5e8 and fcfYield > 0.02",
+		OrderBy: "sector_percentile(roe)",
+		Desc:    true,
+		Columns: []string{"sector", "rank(roe)", "sector_rank(roe)", "fcfYield"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(rows) != 2 || rows[0].Symbol != "AAA" || rows[1].Symbol != "CCC" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	if rows[0].Values["rank(roe)"] != 1.0 || rows[1].Values["rank(roe)"] != 3.0 || rows[1].Values["sector_rank(roe)"] != 1.0 {
+		t.Fatalf("unexpected ranks: %+v", rows)
+	}
+
+	if rows[1].Values["sector"] != "Energy" || rows[0].Values["fcfYield"] != 0.05 {
+		t.Fatalf("unexpected columns: %+v", rows)
+	}
+
+	// Join keys are not ambiguous, price of profile and dcf is
+	err = screener.Ingest(objects.ScreenerDatasetDCF, []objects.HistoryDiscountedCashFlow{{Symbol: "AAA", Date: "2021-06-30", Price: 99, Dcf: 120}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	rows, err = screener.Screen(ScreenerQuery{Filter: `symbol != "BBB" and date >= "2021-01-01"`, Columns: []string{"date"}})
+	if err != nil || len(rows) != 2 || rows[0].Symbol != "AAA" || rows[0].Values["date"] != "2021-12-31" || rows[1].Symbol != "CCC" {
+		t.Fatalf("unexpected join key rows: %+v %v", rows, err)
+	}
+
+	for _, expression := range []string{"unknown > 1", "price > 1", "rank(roe, 1)", "roe >"} {
+		if _, err = screener.Screen(ScreenerQuery{Filter: expression}); err == nil {
+			t.Fatalf("expected error for %q", expression)
+		}
+	}
+}
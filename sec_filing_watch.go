@@ -0,0 +1,364 @@
+package fmpcloud
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+	"golang.org/x/time/rate"
+)
+
+// SEC filing watcher defaults
+const (
+	secWatcherDefaultInterval = 5 * time.Minute
+	secWatcherDefaultLimit    = 20
+	secFetcherDefaultRate     = 10 // requests per second allowed by SEC fair access policy
+)
+
+var (
+	// secAccessionRegexp - 0000320193-20-000096
+	secAccessionRegexp = regexp.MustCompile(`\d{10}-\d{2}-\d{6}`)
+	// secAccessionPathRegexp - /edgar/data/320193/000032019320000096/
+	secAccessionPathRegexp = regexp.MustCompile(`/edgar/data/\d+/(\d{18})(?:/|$)`)
+)
+
+// SECDocumentFetcher - downloads filing document by url
+type SECDocumentFetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// SECFilingWatcherConfig for create SEC filing watcher
+type SECFilingWatcherConfig struct {
+	SymbolList []string
+	FormList   []objects.SECFormType // forms emitted, empty - all
+	Limit      int64                 // filings per symbol and poll, default 20
+	Interval   time.Duration         // poll interval of Run, default 5 minutes
+	Fetcher    SECDocumentFetcher    // default SECHTTPFetcher of UserAgent without cache
+	UserAgent  string                // contact of default fetcher, "Sample Company admin@sample.com"
+}
+
+// SECFilingWatcher - polls filings of symbols and emits each accession once
+type SECFilingWatcher struct {
+	Client *APIClient
+	Config SECFilingWatcherConfig
+
+	mu            sync.Mutex
+	seen          map[string]bool
+	subscriptions map[int]secFilingSubscriber
+	nextID        int
+}
+
+type secFilingSubscriber struct {
+	formList []objects.SECFormType
+	handler  func(objects.SECFilingEvent)
+}
+
+// NewSECFilingWatcher ...
+func NewSECFilingWatcher(client *APIClient, cfg SECFilingWatcherConfig) *SECFilingWatcher {
+	if cfg.Limit <= 0 {
+		cfg.Limit = secWatcherDefaultLimit
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = secWatcherDefaultInterval
+	}
+
+	if cfg.Fetcher == nil && len(strings.TrimSpace(cfg.UserAgent)) > 0 {
+		cfg.Fetcher, _ = NewSECHTTPFetcher(nil, cfg.UserAgent)
+	}
+
+	return &SECFilingWatcher{
+		Client:        client,
+		Config:        cfg,
+		seen:          make(map[string]bool),
+		subscriptions: make(map[int]secFilingSubscriber),
+	}
+}
+
+// Seed - mark current filings as seen, later polls emit only new filings
+func (w *SECFilingWatcher) Seed() error {
+	_, err := w.poll(false)
+	return err
+}
+
+// Subscribe - handler is called from Poll for new filings of forms, empty forms - all, returns subscription id
+func (w *SECFilingWatcher) Subscribe(formList []objects.SECFormType, handler func(objects.SECFilingEvent)) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.nextID++
+	w.subscriptions[w.nextID] = secFilingSubscriber{formList: formList, handler: handler}
+
+	return w.nextID
+}
+
+// Unsubscribe ...
+func (w *SECFilingWatcher) Unsubscribe(id int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.subscriptions, id)
+}
+
+// Poll - fetch filings of every symbol once and notify subscribers, returns new filings oldest first
+func (w *SECFilingWatcher) Poll() ([]objects.SECFilingEvent, error) {
+	return w.poll(true)
+}
+
+// Run - poll until context is done, poll errors are logged
+func (w *SECFilingWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(); err != nil && w.Client.Logger != nil {
+			w.Client.Logger.Error("SEC filings poll failed", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Document - content of filing, final link is preferred
+func (w *SECFilingWatcher) Document(ctx context.Context, event objects.SECFilingEvent) ([]byte, error) {
+	link := event.FinalLink
+	if len(link) == 0 {
+		link = event.Link
+	}
+
+	if len(link) == 0 {
+		return nil, errors.Errorf("Error empty link of filing %s", event.Accession)
+	}
+
+	if w.Config.Fetcher == nil {
+		return nil, errors.New("Error SEC document fetcher requires Fetcher or UserAgent")
+	}
+
+	data, err := w.Config.Fetcher.Fetch(ctx, link)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error load filing %s", event.Accession)
+	}
+
+	return data, nil
+}
+
+func (w *SECFilingWatcher) poll(notify bool) ([]objects.SECFilingEvent, error) {
+	var filingList []objects.SECFiling
+	for _, symbol := range w.Config.SymbolList {
+		fList, err := w.Client.CompanyValuation.SECFilings(objects.RequestSECFilings{Symbol: symbol, Limit: w.Config.Limit})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error load SEC filings of %s", symbol)
+		}
+
+		filingList = append(filingList, fList...)
+	}
+
+	w.mu.Lock()
+	var eventList []objects.SECFilingEvent
+	for _, filing := range filingList {
+		event := NewSECFilingEvent(filing)
+		if w.seen[event.Accession] {
+			continue
+		}
+
+		w.seen[event.Accession] = true
+		if len(w.Config.FormList) == 0 || secFormIn(w.Config.FormList, event.Form) {
+			eventList = append(eventList, event)
+		}
+	}
+
+	subscribers := make([]secFilingSubscriber, 0, len(w.subscriptions))
+	for id := 1; id <= w.nextID; id++ {
+		if s, ok := w.subscriptions[id]; ok {
+			subscribers = append(subscribers, s)
+		}
+	}
+	w.mu.Unlock()
+
+	sort.SliceStable(eventList, func(i, j int) bool { return eventList[i].AcceptedDate < eventList[j].AcceptedDate })
+
+	if !notify {
+		return nil, nil
+	}
+
+	for _, event := range eventList {
+		for _, s := range subscribers {
+			if len(s.formList) == 0 || secFormIn(s.formList, event.Form) {
+				s.handler(event)
+			}
+		}
+	}
+
+	return eventList, nil
+}
+
+// NewSECFilingEvent - event of filing with accession from filing links and form family of type
+func NewSECFilingEvent(filing objects.SECFiling) objects.SECFilingEvent {
+	event := objects.SECFilingEvent{
+		Accession:    SECAccession(filing),
+		Symbol:       filing.Symbol,
+		Cik:          filing.Cik,
+		Type:         filing.Type,
+		FillingDate:  filing.FillingDate,
+		AcceptedDate: filing.AcceptedDate,
+		Link:         filing.Link,
+		FinalLink:    filing.FinalLink,
+	}
+
+	event.Form, event.Amendment = SECFormOf(filing.Type)
+
+	return event
+}
+
+// SECAccession - accession number of filing, cik, type and accepted date when links have none
+func SECAccession(filing objects.SECFiling) string {
+	for _, link := range []string{filing.Link, filing.FinalLink} {
+		if m := secAccessionRegexp.FindString(link); len(m) > 0 {
+			return m
+		}
+
+		if m := secAccessionPathRegexp.FindStringSubmatch(link); m != nil {
+			return fmt.Sprintf("%s-%s-%s", m[1][:10], m[1][10:12], m[1][12:])
+		}
+	}
+
+	return filing.Cik + "|" + filing.Type + "|" + filing.AcceptedDate
+}
+
+// SECFormOf - form family and amendment flag of filing type, 10-K405 and 10-KT are 10-K
+func SECFormOf(filingType string) (objects.SECFormType, bool) {
+	t := strings.ToUpper(strings.TrimSpace(filingType))
+	amendment := strings.HasSuffix(t, "/A")
+	t = strings.TrimPrefix(strings.TrimSuffix(t, "/A"), "SC ")
+
+	switch {
+	case strings.HasPrefix(t, "10-K"):
+		return objects.SECForm10K, amendment
+	case strings.HasPrefix(t, "10-Q"):
+		return objects.SECForm10Q, amendment
+	case t == "8-K" || t == "8-K12B" || t == "8-K12G3":
+		return objects.SECForm8K, amendment
+	case t == "S-1" || t == "S-1MEF":
+		return objects.SECFormS1, amendment
+	case t == "13D":
+		return objects.SECForm13D, amendment
+	case t == "13G":
+		return objects.SECForm13G, amendment
+	}
+
+	return objects.SECFormOther, amendment
+}
+
+// SECHTTPFetcher - fetcher of sec.gov documents with declared user agent and rate limit
+type SECHTTPFetcher struct {
+	client  *resty.Client
+	limiter *rate.Limiter
+}
+
+// NewSECHTTPFetcher - SEC fair access policy requires user agent with real contact,
+// "Sample Company admin@sample.com", nil limiter - 10 requests per second
+func NewSECHTTPFetcher(limiter *rate.Limiter, userAgent string) (*SECHTTPFetcher, error) {
+	if len(strings.TrimSpace(userAgent)) == 0 {
+		return nil, errors.New("Error empty SEC user agent")
+	}
+
+	if limiter == nil {
+		limiter = rate.NewLimiter(secFetcherDefaultRate, 1)
+	}
+
+	client := resty.New().
+		SetTimeout(time.Duration(apiDefaultTimeout)*time.Second).
+		SetHeader("User-Agent", userAgent)
+
+	return &SECHTTPFetcher{client: client, limiter: limiter}, nil
+}
+
+// Fetch ...
+func (f *SECHTTPFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	response, err := f.client.R().SetContext(ctx).Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, errors.Errorf("http %s", response.Status())
+	}
+
+	return response.Body(), nil
+}
+
+// SECDocumentCache - fetcher storing documents in directory, filings do not change once accepted
+type SECDocumentCache struct {
+	Fetcher SECDocumentFetcher
+	Dir     string
+}
+
+// NewSECDocumentCache ...
+func NewSECDocumentCache(fetcher SECDocumentFetcher, dir string) (*SECDocumentCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "Error create SEC document cache")
+	}
+
+	return &SECDocumentCache{Fetcher: fetcher, Dir: dir}, nil
+}
+
+// Fetch - cached document or document of underlying fetcher
+func (c *SECDocumentCache) Fetch(ctx context.Context, url string) ([]byte, error) {
+	path := c.Path(url)
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	}
+
+	data, err := c.Fetcher.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write to temp file first so partial documents are never read from cache
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, errors.Wrap(err, "Error write SEC document cache")
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, errors.Wrap(err, "Error write SEC document cache")
+	}
+
+	return data, nil
+}
+
+// Path - cache file of url
+func (c *SECDocumentCache) Path(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+filepath.Ext(strings.SplitN(url, "?", 2)[0]))
+}
+
+func secFormIn(list []objects.SECFormType, form objects.SECFormType) bool {
+	for _, f := range list {
+		if f == form {
+			return true
+		}
+	}
+
+	return false
+}
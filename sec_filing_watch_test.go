@@ -0,0 +1,116 @@
+package fmpcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestSECFilingWatcher(t *testing.T) {
+	var hits int32
+	edgar := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.Header.Get("User-Agent") != "test admin@test.com" {
+			http.Error(w, "missing user agent", http.StatusForbidden)
+			return
+		}
+
+		_, _ = w.Write([]byte("<html>" + r.URL.Path + "</html>"))
+	}))
+	t.Cleanup(edgar.Close)
+
+	filings := `[
+		{"symbol":"AAA","acceptedDate":"2021-07-28 16:05:00","cik":"0000000001","type":"8-K",
+			"link":"https://www.sec.gov/Archives/edgar/data/1/000000000121000010/0000000001-21-000010-index.htm",
+			"finalLink":"` + edgar.URL + `/8k.htm"},
+		{"symbol":"AAA","acceptedDate":"2021-07-27 16:05:00","cik":"0000000001","type":"10-Q",
+			"link":"https://www.sec.gov/Archives/edgar/data/1/000000000121000009/"}]`
+
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/sec_filings/AAA": filings,
+		"/v3/sec_filings/BBB": `[{"symbol":"BBB","acceptedDate":"2021-07-29 09:00:00","cik":"0000000002","type":"SC 13G/A",
+			"link":"https://www.sec.gov/Archives/edgar/data/2/000000000221000001/0000000002-21-000001-index.htm"}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := NewSECHTTPFetcher(nil, " "); err == nil {
+		t.Fatal("expected empty user agent error")
+	}
+
+	fetcher, err := NewSECHTTPFetcher(nil, "test admin@test.com")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cache, err := NewSECDocumentCache(fetcher, t.TempDir())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	watcher := NewSECFilingWatcher(APIClient, SECFilingWatcherConfig{SymbolList: []string{"AAA", "BBB"}, Fetcher: cache})
+
+	var ownership []objects.SECFilingEvent
+	watcher.Subscribe([]objects.SECFormType{objects.SECForm13D, objects.SECForm13G}, func(e objects.SECFilingEvent) {
+		ownership = append(ownership, e)
+	})
+
+	eventList, err := watcher.Poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(eventList) != 3 || eventList[0].Form != objects.SECForm10Q || eventList[0].Accession != "0000000001-21-000009" {
+		t.Fatalf("unexpected events: %+v", eventList)
+	}
+
+	if len(ownership) != 1 || !ownership[0].Amendment || ownership[0].Accession != "0000000002-21-000001" {
+		t.Fatalf("unexpected ownership events: %+v", ownership)
+	}
+
+	eventList, err = watcher.Poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(eventList) != 0 || len(ownership) != 1 {
+		t.Fatalf("expected no repeated events, got %+v", eventList)
+	}
+
+	for i := 0; i < 2; i++ {
+		data, err := watcher.Document(context.Background(), objects.SECFilingEvent{Accession: "0000000001-21-000010", FinalLink: edgar.URL + "/8k.htm"})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if string(data) != "<html>/8k.htm</html>" {
+			t.Fatalf("unexpected document: %s", data)
+		}
+	}
+
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Fatalf("expected one download, got %d", hits)
+	}
+}
+
+func TestSECFormOf(t *testing.T) {
+	for filingType, expected := range map[string]objects.SECFormType{
+		"10-K":     objects.SECForm10K,
+		"10-K405":  objects.SECForm10K,
+		"10-Q/A":   objects.SECForm10Q,
+		"8-K":      objects.SECForm8K,
+		"S-1/A":    objects.SECFormS1,
+		"SC 13D":   objects.SECForm13D,
+		"SC 13G/A": objects.SECForm13G,
+		"4":        objects.SECFormOther,
+	} {
+		if form, _ := SECFormOf(filingType); form != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, filingType, form)
+		}
+	}
+}
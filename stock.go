@@ -2,6 +2,7 @@ package fmpcloud
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -25,7 +26,6 @@ const (
 	urlAPIStockSearch                    = "/v3/search"
 	urlAPIStockSearchTicker              = "/v3/search-ticker"
 	urlAPIStockSearchName                = "/v3/search-name"
-	urlAPIStockCandles                   = "/v3/historical-chart/%s/%s"
 	urlAPIStockDaily                     = "/v3/historical-price-full/%s"
 	urlAPIStockSP500List                 = "/v3/sp500_constituent"
 	urlAPIStockHistorySP500List          = "/v3/historical/sp500_constituent"
@@ -271,28 +271,9 @@ func (s *Stock) CompanyExecutive(symbol string) (companyProfile []objects.Compan
 	return companyProfile, nil
 }
 
-// Candles - historical candles
+// Candles - historical candles newest first, same as Candles[objects.StockCandle]
 func (s *Stock) Candles(req objects.RequestStockCandleList) (cList []objects.StockCandle, err error) {
-	reqParam := make(map[string]string)
-	if req.From != nil {
-		reqParam["from"] = req.From.Format("2006-01-02")
-	}
-
-	if req.To != nil {
-		reqParam["to"] = req.To.Format("2006-01-02")
-	}
-
-	data, err := s.Client.Get(fmt.Sprintf(urlAPIStockCandles, req.Period, req.Symbol), reqParam)
-	if err != nil {
-		return nil, err
-	}
-
-	err = jsoniter.Unmarshal(data.Body(), &cList)
-	if err != nil {
-		return nil, err
-	}
-
-	return cList, nil
+	return loadTypedCandles[objects.StockCandle](s.Client, candleRequest(req.Symbol, objects.Resolution(req.Period), req.From, req.To))
 }
 
 // DailyLine - daily line
@@ -682,10 +663,19 @@ func (s *Stock) HistorySectorPerformance() (eList []objects.HistorySector, err e
 // SurvivorshipBiasFree - Survivorship Bias Free end of day (only for api v4)
 func (s *Stock) SurvivorshipBiasFree(symbol string, date time.Time) (sBias *objects.SurvivorshipBiasFree, err error) {
 	data, err := s.Client.Get(fmt.Sprintf(urlAPIStockSurvivorshipBiasFree, symbol, date.Format("2006-01-02")), nil)
+	if data != nil && data.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
+	// Not found or empty list when day has no data
+	if body := strings.TrimSpace(string(data.Body())); len(body) == 0 || body == "[]" {
+		return nil, nil
+	}
+
 	err = jsoniter.Unmarshal(data.Body(), &sBias)
 	if err != nil {
 		return nil, err
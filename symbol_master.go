@@ -0,0 +1,941 @@
+package fmpcloud
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Sources of symbol master, refresh time is tracked per source
+const (
+	symbolSourceStocks     = "stocks"
+	symbolSourceETF        = "etf"
+	symbolSourceTradable   = "tradable"
+	symbolSourceStatements = "statements"
+	symbolSourceCrypto     = "crypto"
+	symbolSourceForex      = "forex"
+	symbolSourceDelisted   = "delisted"
+
+	symbolMasterDelistedLimit = 1000
+	symbolSearchMinScore      = 0.3
+)
+
+// securityNameSuffixList - legal form words ignored by search
+var securityNameSuffixList = map[string]bool{
+	"the": true, "inc": true, "incorporated": true, "corp": true, "corporation": true, "co": true,
+	"company": true, "ltd": true, "limited": true, "plc": true, "llc": true, "lp": true,
+	"sa": true, "ag": true, "nv": true, "se": true, "holdings": true, "group": true, "class": true,
+}
+
+// SymbolMasterConfig for create symbol master
+type SymbolMasterConfig struct {
+	Exchanges     []objects.StockSymbolExchange // exchange lists loaded for currency of symbols
+	SkipCrypto    bool
+	SkipForex     bool
+	DelistedLimit int64         // default 1000
+	MaxAge        time.Duration // sources refreshed within MaxAge are skipped, 0 reloads all
+}
+
+// SymbolMaster - local security master merged from symbol lists, persisted with Write
+type SymbolMaster struct {
+	Config SymbolMasterConfig
+
+	mu         sync.RWMutex
+	now        func() time.Time
+	securities map[string]*objects.Security
+	refreshed  map[string]time.Time
+	byCik      securityIndex
+	byCusip    securityIndex
+	byIsin     securityIndex
+}
+
+// securityIndex - symbols by identifier
+type securityIndex map[string]map[string]bool
+
+// symbolMasterData - persisted state of symbol master
+type symbolMasterData struct {
+	Securities []objects.Security   `json:"securities"`
+	Refreshed  map[string]time.Time `json:"refreshed"`
+}
+
+// NewSymbolMaster - empty symbol master, load with Refresh
+func NewSymbolMaster(cfg SymbolMasterConfig) *SymbolMaster {
+	if cfg.DelistedLimit <= 0 {
+		cfg.DelistedLimit = symbolMasterDelistedLimit
+	}
+
+	return &SymbolMaster{
+		Config:     cfg,
+		now:        time.Now,
+		securities: make(map[string]*objects.Security),
+		refreshed:  make(map[string]time.Time),
+		byCik:      make(securityIndex),
+		byCusip:    make(securityIndex),
+		byIsin:     make(securityIndex),
+	}
+}
+
+// ReadSymbolMaster - symbol master saved by Write
+func ReadSymbolMaster(r io.Reader, cfg SymbolMasterConfig) (*SymbolMaster, error) {
+	var data symbolMasterData
+	if err := jsoniter.NewDecoder(r).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "Error read symbol master")
+	}
+
+	m := NewSymbolMaster(cfg)
+	for i := range data.Securities {
+		s := data.Securities[i]
+		m.securities[s.Symbol] = &s
+		m.index(&s)
+	}
+
+	for source, date := range data.Refreshed {
+		m.refreshed[source] = date
+	}
+
+	return m, nil
+}
+
+// Write - encode securities and refresh times as json
+func (m *SymbolMaster) Write(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data := symbolMasterData{Securities: m.list(nil), Refreshed: m.refreshed}
+
+	return jsoniter.NewEncoder(w).Encode(data)
+}
+
+// Refresh - reload sources older than MaxAge and return changes of securities.
+// Sources loaded before an error are kept, so next Refresh continues with the rest.
+func (m *SymbolMaster) Refresh(client *APIClient) ([]objects.SecurityChange, error) {
+	type source struct {
+		name  string
+		apply func() ([]objects.SecurityChange, error)
+	}
+
+	sourceList := []source{
+		{symbolSourceStocks, func() ([]objects.SecurityChange, error) {
+			sList, err := client.Stock.AvalibleSymbols()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error load symbol list")
+			}
+
+			list := make([]objects.Security, 0, len(sList))
+			for _, s := range sList {
+				list = append(list, objects.Security{Symbol: s.Symbol, Name: s.Name, Type: securityType(s.Type),
+					Exchange: s.Exchange, ExchangeShortName: s.ExchangeShortName})
+			}
+
+			return m.applyListed(symbolSourceStocks, list, true), nil
+		}},
+	}
+
+	for _, exchange := range m.Config.Exchanges {
+		exchange := exchange
+		name := "exchange:" + exchange.String()
+		sourceList = append(sourceList, source{name, func() ([]objects.SecurityChange, error) {
+			sList, err := client.Stock.AvalibleSymbolsByExchange(exchange)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error load symbol list of %s", exchange.String())
+			}
+
+			list := make([]objects.Security, 0, len(sList))
+			for _, s := range sList {
+				security := objects.Security{Symbol: s.Symbol, Name: s.Name, Exchange: s.StockExchange, ExchangeShortName: s.ExchangeShortName}
+				if s.Currency != nil {
+					security.Currency = *s.Currency
+				}
+
+				list = append(list, security)
+			}
+
+			return m.applyListed(name, list, false), nil
+		}})
+	}
+
+	sourceList = append(sourceList,
+		source{symbolSourceETF, func() ([]objects.SecurityChange, error) {
+			fList, err := client.CompanyValuation.ETFList()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error load ETF list")
+			}
+
+			list := make([]objects.Security, 0, len(fList))
+			for _, f := range fList {
+				list = append(list, objects.Security{Symbol: f.Symbol, Name: f.Name, Type: objects.SecurityTypeETF,
+					Exchange: f.Exchange, ExchangeShortName: f.ExchangeShortName})
+			}
+
+			return m.applyListed(symbolSourceETF, list, false), nil
+		}},
+		source{symbolSourceTradable, func() ([]objects.SecurityChange, error) {
+			fList, err := client.CompanyValuation.AvailableTradedList()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error load traded list")
+			}
+
+			list := make([]objects.Security, 0, len(fList))
+			tradable := make(map[string]bool, len(fList))
+			for _, f := range fList {
+				tradable[f.Symbol] = true
+				list = append(list, objects.Security{Symbol: f.Symbol, Name: f.Name, Type: securityType(f.Type),
+					Exchange: f.Exchange, ExchangeShortName: f.ExchangeShortName})
+			}
+
+			changes := m.applyListed(symbolSourceTradable, list, false)
+			m.mark(func(s *objects.Security) { s.Tradable = tradable[s.Symbol] })
+
+			return changes, nil
+		}},
+		source{symbolSourceStatements, func() ([]objects.SecurityChange, error) {
+			fsList, err := client.CompanyValuation.FinancialStatementList()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error load financial statement list")
+			}
+
+			statements := make(map[string]bool, len(fsList))
+			for _, symbol := range fsList {
+				statements[symbol] = true
+			}
+
+			m.mark(func(s *objects.Security) { s.HasStatements = statements[s.Symbol] })
+
+			return nil, nil
+		}},
+	)
+
+	if !m.Config.SkipCrypto {
+		sourceList = append(sourceList, source{symbolSourceCrypto, func() ([]objects.SecurityChange, error) {
+			sList, err := client.Crypto.AvalibleSymbols()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error load crypto symbol list")
+			}
+
+			list := make([]objects.Security, 0, len(sList))
+			for _, s := range sList {
+				list = append(list, objects.Security{Symbol: s.Symbol, Name: s.Name, Type: objects.SecurityTypeCrypto,
+					Currency: s.Currency, Exchange: s.StockExchange, ExchangeShortName: s.ExchangeShortName})
+			}
+
+			return m.applyListed(symbolSourceCrypto, list, true), nil
+		}})
+	}
+
+	if !m.Config.SkipForex {
+		sourceList = append(sourceList, source{symbolSourceForex, func() ([]objects.SecurityChange, error) {
+			sList, err := client.Forex.AvalibleSymbols()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error load forex symbol list")
+			}
+
+			list := make([]objects.Security, 0, len(sList))
+			for _, s := range sList {
+				list = append(list, objects.Security{Symbol: s.Symbol, Name: s.Name, Type: objects.SecurityTypeForex,
+					Currency: s.Currency, Exchange: s.StockExchange, ExchangeShortName: s.ExchangeShortName})
+			}
+
+			return m.applyListed(symbolSourceForex, list, true), nil
+		}})
+	}
+
+	sourceList = append(sourceList, source{symbolSourceDelisted, func() ([]objects.SecurityChange, error) {
+		cList, err := client.CompanyValuation.DelstedCompanies(m.Config.DelistedLimit)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error load delisted companies")
+		}
+
+		return m.applyDelisted(cList), nil
+	}})
+
+	var changes []objects.SecurityChange
+	for _, s := range sourceList {
+		m.mu.RLock()
+		refreshed, ok := m.refreshed[s.name]
+		m.mu.RUnlock()
+		if ok && m.Config.MaxAge > 0 && m.now().Sub(refreshed) < m.Config.MaxAge {
+			continue
+		}
+
+		sourceChanges, err := s.apply()
+		if err != nil {
+			return sortSecurityChanges(changes), err
+		}
+
+		m.mu.Lock()
+		m.refreshed[s.name] = m.now()
+		m.mu.Unlock()
+
+		changes = append(changes, sourceChanges...)
+	}
+
+	return sortSecurityChanges(changes), nil
+}
+
+// Identify - load missing CIK, CUSIP and ISIN of symbols from company profile and CIK mapper
+func (m *SymbolMaster) Identify(client *APIClient, symbols ...string) error {
+	for _, symbol := range symbols {
+		s, ok := m.Lookup(symbol)
+		if !ok {
+			return errors.Errorf("Error unknown security %s", symbol)
+		}
+
+		if s.Type == objects.SecurityTypeCrypto || s.Type == objects.SecurityTypeForex ||
+			(len(s.Cik) > 0 && len(s.Cusip) > 0 && len(s.Isin) > 0) {
+			continue
+		}
+
+		profileList, err := client.Stock.CompanyProfile(symbol)
+		if err != nil {
+			return errors.Wrapf(err, "Error load profile of %s", symbol)
+		}
+
+		for _, p := range profileList {
+			s.Cik = firstNonEmpty(s.Cik, normalizeCik(p.Cik))
+			s.Cusip = firstNonEmpty(s.Cusip, strings.ToUpper(p.Cusip))
+			s.Isin = firstNonEmpty(s.Isin, strings.ToUpper(p.Isin))
+		}
+
+		if len(s.Cik) == 0 {
+			cList, err := client.InsiderTrading.MapperCikCompany(symbol)
+			if err != nil {
+				return errors.Wrapf(err, "Error load CIK of %s", symbol)
+			}
+
+			for _, c := range cList {
+				if c.Symbol == symbol {
+					s.Cik = normalizeCik(c.CompanyCik)
+				}
+			}
+		}
+
+		m.mu.Lock()
+		if current, ok := m.securities[symbol]; ok {
+			m.setIdentifiers(current, s.Cik, s.Cusip, s.Isin)
+			current.UpdatedAt = m.now()
+		}
+		m.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Resolve - security by ticker, CIK, CUSIP or ISIN. Unknown CUSIP and US/CA ISIN
+// are mapped online with Form13F.CusipMapper when client is set.
+func (m *SymbolMaster) Resolve(client *APIClient, id string) (objects.Security, error) {
+	id = strings.TrimSpace(id)
+	switch SecurityIdentifierOf(id) {
+	case objects.SecurityIdentifierCik:
+		if list := m.ByCik(id); len(list) > 0 {
+			return list[0], nil
+		}
+	case objects.SecurityIdentifierCusip:
+		if s, ok := m.ByCusip(id); ok {
+			return s, nil
+		}
+
+		if client != nil {
+			return m.resolveCusip(client, strings.ToUpper(id), "")
+		}
+	case objects.SecurityIdentifierIsin:
+		if s, ok := m.ByIsin(id); ok {
+			return s, nil
+		}
+
+		isin := strings.ToUpper(id)
+		if client != nil && (strings.HasPrefix(isin, "US") || strings.HasPrefix(isin, "CA")) {
+			return m.resolveCusip(client, isin[2:11], isin)
+		}
+	default:
+		if s, ok := m.Lookup(strings.ToUpper(id)); ok {
+			return s, nil
+		}
+	}
+
+	return objects.Security{}, errors.Errorf("Error unknown security %s", id)
+}
+
+// Lookup - security by symbol
+func (m *SymbolMaster) Lookup(symbol string) (objects.Security, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.securities[symbol]
+	if !ok {
+		return objects.Security{}, false
+	}
+
+	return *s, true
+}
+
+// ByCik - securities of company, share classes share CIK, active first
+func (m *SymbolMaster) ByCik(cik string) []objects.Security {
+	cik = normalizeCik(cik)
+	if len(cik) == 0 {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.indexed(m.byCik, cik)
+}
+
+// ByCusip - security by CUSIP
+func (m *SymbolMaster) ByCusip(cusip string) (objects.Security, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := m.indexed(m.byCusip, strings.ToUpper(cusip))
+	if len(list) == 0 {
+		return objects.Security{}, false
+	}
+
+	return list[0], true
+}
+
+// ByIsin - security by ISIN
+func (m *SymbolMaster) ByIsin(isin string) (objects.Security, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := m.indexed(m.byIsin, strings.ToUpper(isin))
+	if len(list) == 0 {
+		return objects.Security{}, false
+	}
+
+	return list[0], true
+}
+
+// Securities - securities of types sorted by symbol, all types when empty
+func (m *SymbolMaster) Securities(types ...objects.SecurityType) []objects.Security {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(types) == 0 {
+		return m.list(nil)
+	}
+
+	return m.list(func(s *objects.Security) bool {
+		for _, t := range types {
+			if s.Type == t {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Search - offline fuzzy search by symbol and name, delisted and removed securities rank lower
+func (m *SymbolMaster) Search(query string, limit int) []objects.SecurityMatch {
+	symbol := strings.ToUpper(strings.TrimSpace(query))
+	name := normalizeSecurityName(query)
+	if len(symbol) == 0 {
+		return nil
+	}
+
+	nameTrigrams := trigrams(name)
+
+	m.mu.RLock()
+	var matchList []objects.SecurityMatch
+	for _, s := range m.securities {
+		score := securityMatchScore(s, symbol, name, nameTrigrams)
+		if !s.Active || s.Delisted {
+			score *= 0.9
+		}
+
+		if score >= symbolSearchMinScore {
+			matchList = append(matchList, objects.SecurityMatch{Security: *s, Score: score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matchList, func(i, j int) bool {
+		if matchList[i].Score != matchList[j].Score {
+			return matchList[i].Score > matchList[j].Score
+		}
+
+		return matchList[i].Security.Symbol < matchList[j].Security.Symbol
+	})
+
+	if limit > 0 && len(matchList) > limit {
+		matchList = matchList[:limit]
+	}
+
+	return matchList
+}
+
+// SecurityIdentifierOf - kind of identifier by format and check digit
+func SecurityIdentifierOf(id string) objects.SecurityIdentifier {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	switch {
+	case len(id) == 12 && unicode.IsLetter(rune(id[0])) && unicode.IsLetter(rune(id[1])) && isinCheckDigit(id[:11]) == int(id[11]-'0'):
+		return objects.SecurityIdentifierIsin
+	case len(id) == 9 && cusipCheckDigit(id[:8]) == int(id[8]-'0'):
+		return objects.SecurityIdentifierCusip
+	case len(id) > 0 && len(id) <= 10 && strings.Trim(id, "0123456789") == "":
+		return objects.SecurityIdentifierCik
+	}
+
+	return objects.SecurityIdentifierTicker
+}
+
+// resolveCusip - map CUSIP to ticker online and store identifiers
+func (m *SymbolMaster) resolveCusip(client *APIClient, cusip, isin string) (objects.Security, error) {
+	cList, err := client.Form13F.CusipMapper(cusip)
+	if err != nil {
+		return objects.Security{}, errors.Wrapf(err, "Error load CUSIP %s", cusip)
+	}
+
+	for _, c := range cList {
+		if len(c.Ticker) == 0 {
+			continue
+		}
+
+		m.mu.Lock()
+		s, ok := m.securities[c.Ticker]
+		if !ok {
+			s = &objects.Security{Symbol: c.Ticker, Name: c.Company, Type: objects.SecurityTypeStock, FirstSeen: m.now()}
+			m.securities[c.Ticker] = s
+		}
+
+		m.setIdentifiers(s, s.Cik, cusip, firstNonEmpty(isin, s.Isin))
+		s.UpdatedAt = m.now()
+		security := *s
+		m.mu.Unlock()
+
+		return security, nil
+	}
+
+	return objects.Security{}, errors.Errorf("Error unknown security %s", firstNonEmpty(isin, cusip))
+}
+
+// apply - merge securities listed by source, primary sources set names and report renames.
+// Securities listed by any source are active unless delisted, primary sources listing
+// security after delisting date clear delisting.
+func (m *SymbolMaster) apply(source string, list []objects.Security, primary bool) []objects.SecurityChange {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var changes []objects.SecurityChange
+	for _, item := range list {
+		if len(item.Symbol) == 0 {
+			continue
+		}
+
+		s, ok := m.securities[item.Symbol]
+		if !ok {
+			s = &objects.Security{Symbol: item.Symbol, Name: item.Name, Type: item.Type, Active: true, FirstSeen: now}
+			m.securities[item.Symbol] = s
+			changes = append(changes, objects.SecurityChange{Symbol: item.Symbol, Kind: objects.SecurityChangeAdded, Name: item.Name})
+		}
+
+		if primary && len(item.Name) > 0 && len(s.Name) > 0 && item.Name != s.Name {
+			changes = append(changes, objects.SecurityChange{Symbol: item.Symbol, Kind: objects.SecurityChangeRenamed, Name: item.Name, Previous: s.Name})
+			s.Name = item.Name
+		}
+
+		if primary && len(item.Type) > 0 {
+			s.Type = item.Type
+		}
+
+		s.Name = firstNonEmpty(s.Name, item.Name)
+		s.Type = objects.SecurityType(firstNonEmpty(string(s.Type), string(item.Type)))
+		s.Exchange = firstNonEmpty(item.Exchange, s.Exchange)
+		s.ExchangeShortName = firstNonEmpty(item.ExchangeShortName, s.ExchangeShortName)
+		s.Currency = firstNonEmpty(item.Currency, s.Currency)
+		s.Sources = addSecuritySource(s.Sources, source)
+		s.UpdatedAt = now
+		if s.Delisted && primary && now.Format(layoutDate) > s.DelistedDate {
+			s.Delisted = false
+			s.DelistedDate = ""
+			changes = append(changes, objects.SecurityChange{Symbol: item.Symbol, Kind: objects.SecurityChangeRelisted, Name: s.Name})
+		}
+
+		if !s.Delisted {
+			s.Active = true
+		}
+	}
+
+	return changes
+}
+
+// applyListed - merge full list of source, securities missing from list lose the source
+// and are removed when no other source lists them
+func (m *SymbolMaster) applyListed(source string, list []objects.Security, primary bool) []objects.SecurityChange {
+	changes := m.apply(source, list, primary)
+
+	seen := make(map[string]bool, len(list))
+	for _, s := range list {
+		seen[s.Symbol] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.securities {
+		if seen[s.Symbol] || !hasSecuritySource(s.Sources, source) {
+			continue
+		}
+
+		s.Sources = removeSecuritySource(s.Sources, source)
+		if s.Active && len(s.Sources) == 0 {
+			s.Active = false
+			s.UpdatedAt = m.now()
+			changes = append(changes, objects.SecurityChange{Symbol: s.Symbol, Kind: objects.SecurityChangeRemoved, Name: s.Name})
+		}
+	}
+
+	return changes
+}
+
+// applyDelisted - mark delisted companies inactive, companies stock list has listed after delisting date are skipped
+func (m *SymbolMaster) applyDelisted(cList []objects.DelstedCompany) []objects.SecurityChange {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var changes []objects.SecurityChange
+	for _, c := range cList {
+		if len(c.Symbol) == 0 {
+			continue
+		}
+
+		s, ok := m.securities[c.Symbol]
+		if !ok {
+			s = &objects.Security{Symbol: c.Symbol, Name: c.CompanyName, Type: objects.SecurityTypeStock, ExchangeShortName: c.Exchange, FirstSeen: now}
+			m.securities[c.Symbol] = s
+		}
+
+		if s.Delisted && s.DelistedDate == c.DelistedDate {
+			continue
+		}
+
+		if !s.Delisted && hasSecuritySource(s.Sources, symbolSourceStocks) && s.UpdatedAt.Format(layoutDate) > c.DelistedDate {
+			continue
+		}
+
+		s.Delisted = true
+		s.Active = false
+		s.IpoDate = firstNonEmpty(s.IpoDate, c.IpoDate)
+		s.DelistedDate = c.DelistedDate
+		s.UpdatedAt = now
+		changes = append(changes, objects.SecurityChange{Symbol: c.Symbol, Kind: objects.SecurityChangeDelisted, Name: firstNonEmpty(s.Name, c.CompanyName)})
+	}
+
+	return changes
+}
+
+// mark - update flag of every security
+func (m *SymbolMaster) mark(update func(s *objects.Security)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.securities {
+		update(s)
+	}
+}
+
+// index - add identifiers of security to indexes, caller holds lock
+func (m *SymbolMaster) index(s *objects.Security) {
+	m.byCik.add(s.Cik, s.Symbol)
+	m.byCusip.add(s.Cusip, s.Symbol)
+	m.byIsin.add(s.Isin, s.Symbol)
+}
+
+// setIdentifiers - update identifiers of security and indexes, caller holds lock
+func (m *SymbolMaster) setIdentifiers(s *objects.Security, cik, cusip, isin string) {
+	m.byCik.remove(s.Cik, s.Symbol)
+	m.byCusip.remove(s.Cusip, s.Symbol)
+	m.byIsin.remove(s.Isin, s.Symbol)
+	s.Cik, s.Cusip, s.Isin = cik, cusip, isin
+	m.index(s)
+}
+
+// indexed - copies of securities with identifier, active first then by symbol, caller holds lock
+func (m *SymbolMaster) indexed(idx securityIndex, id string) []objects.Security {
+	list := make([]objects.Security, 0, len(idx[id]))
+	for symbol := range idx[id] {
+		if s, ok := m.securities[symbol]; ok {
+			list = append(list, *s)
+		}
+	}
+
+	return sortSecurities(list)
+}
+
+// list - copies of matched securities, active first then by symbol, caller holds lock
+func (m *SymbolMaster) list(match func(s *objects.Security) bool) []objects.Security {
+	list := make([]objects.Security, 0)
+	for _, s := range m.securities {
+		if match == nil || match(s) {
+			list = append(list, *s)
+		}
+	}
+
+	return sortSecurities(list)
+}
+
+func (idx securityIndex) add(id, symbol string) {
+	if len(id) == 0 {
+		return
+	}
+
+	if idx[id] == nil {
+		idx[id] = make(map[string]bool)
+	}
+
+	idx[id][symbol] = true
+}
+
+func (idx securityIndex) remove(id, symbol string) {
+	delete(idx[id], symbol)
+	if len(idx[id]) == 0 {
+		delete(idx, id)
+	}
+}
+
+func sortSecurities(list []objects.Security) []objects.Security {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Active != list[j].Active {
+			return list[i].Active
+		}
+
+		return list[i].Symbol < list[j].Symbol
+	})
+
+	return list
+}
+
+// addSecuritySource - sorted sources with source, new slice when added
+func addSecuritySource(sources []string, source string) []string {
+	if hasSecuritySource(sources, source) {
+		return sources
+	}
+
+	list := append(append(make([]string, 0, len(sources)+1), sources...), source)
+	sort.Strings(list)
+
+	return list
+}
+
+// removeSecuritySource - sources without source as new slice
+func removeSecuritySource(sources []string, source string) []string {
+	var list []string
+	for _, s := range sources {
+		if s != source {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
+
+func hasSecuritySource(sources []string, source string) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+
+	return false
+}
+
+func securityType(t string) objects.SecurityType {
+	if len(t) == 0 {
+		return objects.SecurityTypeStock
+	}
+
+	return objects.SecurityType(strings.ToLower(t))
+}
+
+// securityMatchScore - exact symbol 1, symbol prefix 0.9, name 0.95/0.85/0.75 for equal/prefix/words,
+// otherwise trigram similarity of names
+func securityMatchScore(s *objects.Security, symbol, name string, nameTrigrams map[string]bool) float64 {
+	if s.Symbol == symbol {
+		return 1
+	}
+
+	score := 0.0
+	if strings.HasPrefix(s.Symbol, symbol) {
+		score = 0.9 - 0.02*float64(len(s.Symbol)-len(symbol))
+	}
+
+	securityName := normalizeSecurityName(s.Name)
+	if len(name) == 0 || len(securityName) == 0 {
+		return score
+	}
+
+	switch {
+	case securityName == name:
+		return maxFloat(score, 0.95)
+	case strings.HasPrefix(securityName, name):
+		return maxFloat(score, 0.85)
+	case containsWordPrefixes(securityName, name):
+		return maxFloat(score, 0.75)
+	}
+
+	return maxFloat(score, 0.8*dice(nameTrigrams, trigrams(securityName)))
+}
+
+// normalizeSecurityName - lower case words without punctuation and legal form
+func normalizeSecurityName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	list := make([]string, 0, len(words))
+	for _, w := range words {
+		if !securityNameSuffixList[w] {
+			list = append(list, w)
+		}
+	}
+
+	return strings.Join(list, " ")
+}
+
+// containsWordPrefixes - every word of query starts a word of name
+func containsWordPrefixes(name, query string) bool {
+	nameWords := strings.Fields(name)
+	for _, q := range strings.Fields(query) {
+		found := false
+		for _, w := range nameWords {
+			if strings.HasPrefix(w, q) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	padded := []rune("  " + s + " ")
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = true
+	}
+
+	return set
+}
+
+// dice - Sørensen–Dice coefficient of sets
+func dice(a, b map[string]bool) float64 {
+	if len(a)+len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// normalizeCik - 10 digits with leading zeros, empty for invalid CIK
+func normalizeCik(cik string) string {
+	cik = strings.TrimLeft(strings.TrimSpace(cik), "0")
+	if len(cik) == 0 || len(cik) > 10 || strings.Trim(cik, "0123456789") != "" {
+		return ""
+	}
+
+	return strings.Repeat("0", 10-len(cik)) + cik
+}
+
+// cusipCheckDigit - check digit of first 8 CUSIP characters, -1 for invalid characters
+func cusipCheckDigit(s string) int {
+	sum := 0
+	for i, r := range s {
+		var v int
+		switch {
+		case r >= '0' && r <= '9':
+			v = int(r - '0')
+		case r >= 'A' && r <= 'Z':
+			v = int(r-'A') + 10
+		case r == '*':
+			v = 36
+		case r == '@':
+			v = 37
+		case r == '#':
+			v = 38
+		default:
+			return -1
+		}
+
+		if i%2 == 1 {
+			v *= 2
+		}
+
+		sum += v/10 + v%10
+	}
+
+	return (10 - sum%10) % 10
+}
+
+// isinCheckDigit - Luhn check digit of first 11 ISIN characters with letters as numbers
+func isinCheckDigit(s string) int {
+	var digits []int
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, int(r-'0'))
+		case r >= 'A' && r <= 'Z':
+			v := int(r-'A') + 10
+			digits = append(digits, v/10, v%10)
+		default:
+			return -1
+		}
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		v := digits[i]
+		if (len(digits)-1-i)%2 == 0 {
+			v *= 2
+		}
+
+		sum += v/10 + v%10
+	}
+
+	return (10 - sum%10) % 10
+}
+
+func sortSecurityChanges(changes []objects.SecurityChange) []objects.SecurityChange {
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Symbol < changes[j].Symbol })
+	return changes
+}
+
+func firstNonEmpty(list ...string) string {
+	for _, s := range list {
+		if len(s) > 0 {
+			return s
+		}
+	}
+
+	return ""
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
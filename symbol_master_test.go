@@ -0,0 +1,145 @@
+package fmpcloud
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestSymbolMaster(t *testing.T) {
+	routes := map[string]string{
+		"/v3/stock/list": `[{"symbol":"AAPL","name":"Apple Inc.","exchange":"NASDAQ Global Select","exchangeShortName":"NASDAQ","type":"stock"},
+			{"symbol":"MSFT","name":"Microsoft Corporation","exchange":"NASDAQ Global Select","exchangeShortName":"NASDAQ","type":"stock"},
+			{"symbol":"SPY","name":"SPDR S&P 500 ETF Trust","exchange":"New York Stock Exchange Arca","exchangeShortName":"AMEX","type":"etf"}]`,
+		"/v3/etf/list": `[{"symbol":"SPY","name":"SPDR S&P 500","exchange":"New York Stock Exchange Arca","exchangeShortName":"AMEX"}]`,
+		"/v3/available-traded/list": `[{"symbol":"AAPL","name":"Apple Inc.","exchangeShortName":"NASDAQ","type":"stock"},
+			{"symbol":"QQQ","name":"Invesco QQQ Trust","exchangeShortName":"NASDAQ","type":"etf"}]`,
+		"/v3/financial-statement-symbol-lists":      `["AAPL","MSFT"]`,
+		"/v3/symbol/available-cryptocurrencies":     `[{"symbol":"BTCUSD","name":"Bitcoin USD","currency":"USD","stockExchange":"CCC","exchangeShortName":"CRYPTO"}]`,
+		"/v3/symbol/available-forex-currency-pairs": `[{"symbol":"EURUSD","name":"EUR/USD","currency":"USD","stockExchange":"CCY","exchangeShortName":"FOREX"}]`,
+		"/v3/delisted-companies":                    `[{"symbol":"TWTR","companyName":"Twitter, Inc.","exchange":"NYSE","ipoDate":"2013-11-07","delistedDate":"2022-10-27"}]`,
+		"/v3/profile/AAPL":                          `[{"symbol":"AAPL","cik":"0000320193","isin":"US0378331005","cusip":"037833100"}]`,
+		"/v3/profile/MSFT":                          `[{"symbol":"MSFT"}]`,
+		"/v4/mapper-cik-company/MSFT":               `[{"symbol":"MSFT","companyCik":"789019"}]`,
+		"/v3/cusip/02079K305":                       `[{"ticker":"GOOG","cusip":"02079K305","company":"ALPHABET INC"}]`,
+	}
+
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, routes))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	master := NewSymbolMaster(SymbolMasterConfig{MaxAge: time.Hour})
+	master.now = func() time.Time { return now }
+
+	changes, err := master.Refresh(APIClient)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(changes) != 7 || changes[6].Symbol != "TWTR" || changes[6].Kind != objects.SecurityChangeDelisted {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	spy, _ := master.Lookup("SPY")
+	aapl, _ := master.Lookup("AAPL")
+	if spy.Name != "SPDR S&P 500 ETF Trust" || spy.Type != objects.SecurityTypeETF || !aapl.Tradable || !aapl.HasStatements || !aapl.Active {
+		t.Fatalf("unexpected securities: %+v %+v", spy, aapl)
+	}
+
+	if err := master.Identify(APIClient, "AAPL", "MSFT"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if s, err := master.Resolve(nil, "US0378331005"); err != nil || s.Symbol != "AAPL" {
+		t.Fatalf("unexpected ISIN resolve: %+v %v", s, err)
+	}
+
+	if s, err := master.Resolve(nil, "320193"); err != nil || s.Symbol != "AAPL" {
+		t.Fatalf("unexpected CIK resolve: %+v %v", s, err)
+	}
+
+	if list := master.ByCik("0000789019"); len(list) != 1 || list[0].Symbol != "MSFT" {
+		t.Fatalf("unexpected CIK mapper: %+v", list)
+	}
+
+	if s, err := master.Resolve(APIClient, "US02079K3059"); err != nil || s.Symbol != "GOOG" || s.Cusip != "02079K305" {
+		t.Fatalf("unexpected online ISIN resolve: %+v %v", s, err)
+	}
+
+	if matchList := master.Search("mircosoft", 3); len(matchList) == 0 || matchList[0].Security.Symbol != "MSFT" {
+		t.Fatalf("unexpected fuzzy search: %+v", matchList)
+	}
+
+	if matchList := master.Search("twitter", 1); len(matchList) != 1 || !matchList[0].Security.Delisted {
+		t.Fatalf("unexpected delisted search: %+v", matchList)
+	}
+
+	var buf bytes.Buffer
+	if err := master.Write(&buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Fresh sources are skipped, stale stock list reports removed, renamed and relisted securities,
+	// securities of other lists stay active
+	routes["/v3/stock/list"] = `[{"symbol":"AAPL","name":"Apple Inc","exchangeShortName":"NASDAQ","type":"stock"},
+		{"symbol":"SPY","name":"SPDR S&P 500 ETF Trust","exchangeShortName":"AMEX","type":"etf"},
+		{"symbol":"TWTR","name":"Twitter, Inc.","exchangeShortName":"NYSE","type":"stock"}]`
+
+	restored, err := ReadSymbolMaster(&buf, SymbolMasterConfig{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	restored.now = func() time.Time { return now.Add(30 * time.Minute) }
+	if changes, err := restored.Refresh(APIClient); err != nil || len(changes) != 0 {
+		t.Fatalf("expected no changes of fresh sources: %+v %v", changes, err)
+	}
+
+	if s, _ := restored.Lookup("AAPL"); s.Cik != "0000320193" || s.Isin != "US0378331005" {
+		t.Fatalf("unexpected restored identifiers: %+v", s)
+	}
+
+	restored.now = func() time.Time { return now.Add(2 * time.Hour) }
+	changes, err = restored.Refresh(APIClient)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(changes) != 3 || changes[0].Kind != objects.SecurityChangeRenamed || changes[0].Previous != "Apple Inc." ||
+		changes[1].Symbol != "MSFT" || changes[1].Kind != objects.SecurityChangeRemoved ||
+		changes[2].Symbol != "TWTR" || changes[2].Kind != objects.SecurityChangeRelisted {
+		t.Fatalf("unexpected incremental changes: %+v", changes)
+	}
+
+	// Delisted list still has company, listing after delisting date wins
+	if s, _ := restored.Lookup("TWTR"); !s.Active || s.Delisted || len(s.DelistedDate) > 0 {
+		t.Fatalf("unexpected relisted security: %+v", s)
+	}
+
+	if s, _ := restored.Lookup("QQQ"); !s.Active || len(s.Sources) != 1 || s.Sources[0] != symbolSourceTradable {
+		t.Fatalf("unexpected security of tradable list: %+v", s)
+	}
+
+	if s, ok := restored.ByCusip("037833100"); !ok || s.Symbol != "AAPL" {
+		t.Fatalf("unexpected restored CUSIP index: %+v", s)
+	}
+}
+
+func TestSecurityIdentifierOf(t *testing.T) {
+	for id, kind := range map[string]objects.SecurityIdentifier{
+		"AAPL":         objects.SecurityIdentifierTicker,
+		"037833100":    objects.SecurityIdentifierCusip,
+		"US0378331005": objects.SecurityIdentifierIsin,
+		"US0378331006": objects.SecurityIdentifierTicker,
+		"0000320193":   objects.SecurityIdentifierCik,
+		"BRK-B":        objects.SecurityIdentifierTicker,
+	} {
+		if got := SecurityIdentifierOf(id); got != kind {
+			t.Fatalf("expected %s for %s, got %s", kind, id, got)
+		}
+	}
+}
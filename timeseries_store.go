@@ -0,0 +1,596 @@
+package fmpcloud
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Time series store defaults
+const (
+	timeSeriesFundamentalsMaxAge = 24 * time.Hour
+	timeSeriesMaxLine            = 16 << 20
+)
+
+// TimeSeriesStoreConfig for create time series store
+type TimeSeriesStoreConfig struct {
+	Dir                string
+	FundamentalsMaxAge time.Duration  // stored fundamentals younger are not reloaded, default 24h
+	Location           *time.Location // time zone of stored intraday bars, default America/New_York
+}
+
+// TimeSeriesStore - file store of bars, quotes and fundamentals read through before API.
+// Series are JSON lines partitioned by symbol and year, updates are appended and later lines win.
+// Without Client only stored data is returned. Series are locked one by one, so loading
+// one series does not block others.
+type TimeSeriesStore struct {
+	Client *APIClient
+	Config TimeSeriesStoreConfig
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex // by series dir
+	now   func() time.Time
+}
+
+// NewTimeSeriesStore ...
+func NewTimeSeriesStore(client *APIClient, cfg TimeSeriesStoreConfig) (*TimeSeriesStore, error) {
+	if len(cfg.Dir) == 0 {
+		return nil, errors.New("Error empty time series store dir")
+	}
+
+	if cfg.FundamentalsMaxAge <= 0 {
+		cfg.FundamentalsMaxAge = timeSeriesFundamentalsMaxAge
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "Error create time series store")
+	}
+
+	return &TimeSeriesStore{Client: client, Config: cfg, locks: make(map[string]*sync.Mutex), now: time.Now}, nil
+}
+
+// History - bars oldest first, only dates missing from store are loaded from API.
+// Bars of today are loaded again on each call until the day is over.
+func (s *TimeSeriesStore) History(req objects.RequestCandles) ([]objects.Bar, error) {
+	if len(req.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	if _, ok := candleMaxDays[req.Resolution]; !ok {
+		return nil, errors.Errorf("Error unknown resolution %s", req.Resolution)
+	}
+
+	if req.From.IsZero() {
+		return nil, errors.New("Error empty history period")
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = s.now()
+	}
+
+	location, err := s.barLocation(req.Resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	// Candle dates are wall clock of store unless request sets their time zone
+	source := req.Location
+	if source == nil {
+		source = location
+	}
+
+	from, last := req.From.Format(layoutDate), to.Format(layoutDate)
+
+	dir := s.seriesDir("bars", req.Symbol, string(req.Resolution))
+	defer s.lock(dir)()
+
+	meta, err := readTimeSeriesMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Client != nil {
+		today := s.now().Format(layoutDate)
+		missing := missingRanges(meta.Ranges, from, last)
+		for _, r := range missing {
+			rFrom, _ := time.Parse(layoutDate, r.From)
+			rTo, _ := time.Parse(layoutDate, r.To)
+			bars, err := History(s.Client, objects.RequestCandles{Symbol: req.Symbol, Resolution: req.Resolution, From: rFrom, To: rTo, Location: source})
+			if err != nil {
+				return nil, err
+			}
+
+			if err := appendBars(dir, bars, location); err != nil {
+				return nil, err
+			}
+
+			if r.To >= today {
+				r.To = shiftDate(today, -1)
+			}
+
+			if r.From <= r.To {
+				meta.Ranges = mergeRanges(append(meta.Ranges, r))
+			}
+		}
+
+		if len(missing) > 0 {
+			meta.Updated = s.now()
+			if err := writeTimeSeriesMeta(dir, meta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return readBars(dir, from, last, location)
+}
+
+// Bars - stored bars of dates from..to oldest first
+func (s *TimeSeriesStore) Bars(symbol string, resolution objects.Resolution, from, to time.Time) ([]objects.Bar, error) {
+	location, err := s.barLocation(resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := s.seriesDir("bars", symbol, string(resolution))
+	defer s.lock(dir)()
+
+	return readBars(dir, from.Format(layoutDate), to.Format(layoutDate), location)
+}
+
+// AppendBars - store bars loaded elsewhere, intraday downloads, and mark their dates as loaded.
+// Bars of any time zone are stored as same bars loaded with History.
+func (s *TimeSeriesStore) AppendBars(symbol string, resolution objects.Resolution, bars []objects.Bar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	location, err := s.barLocation(resolution)
+	if err != nil {
+		return err
+	}
+
+	dir := s.seriesDir("bars", symbol, string(resolution))
+	defer s.lock(dir)()
+
+	if err := appendBars(dir, bars, location); err != nil {
+		return err
+	}
+
+	meta, err := readTimeSeriesMeta(dir)
+	if err != nil {
+		return err
+	}
+
+	first := bars[0].Date.In(location).Format(layoutDate)
+	r := objects.TimeSeriesRange{From: first, To: first}
+	for _, bar := range bars {
+		date := bar.Date.In(location).Format(layoutDate)
+		if date < r.From {
+			r.From = date
+		}
+
+		if date > r.To {
+			r.To = date
+		}
+	}
+
+	meta.Ranges = mergeRanges(append(meta.Ranges, r))
+	meta.Updated = s.now()
+
+	return writeTimeSeriesMeta(dir, meta)
+}
+
+// Missing - ranges of dates from..to not loaded into store
+func (s *TimeSeriesStore) Missing(symbol string, resolution objects.Resolution, from, to time.Time) ([]objects.TimeSeriesRange, error) {
+	dir := s.seriesDir("bars", symbol, string(resolution))
+	defer s.lock(dir)()
+
+	meta, err := readTimeSeriesMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return missingRanges(meta.Ranges, from.Format(layoutDate), to.Format(layoutDate)), nil
+}
+
+// Quotes - real-time quotes of symbols, every snapshot is appended to store
+func (s *TimeSeriesStore) Quotes(symbols ...string) ([]objects.StockQuote, error) {
+	if s.Client == nil {
+		return nil, errors.New("Error time series store without client")
+	}
+
+	qList, err := s.Client.Stock.BatchQuote(symbols)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load quotes")
+	}
+
+	return qList, s.AppendQuotes(qList)
+}
+
+// AppendQuotes - store quotes by symbol and timestamp
+func (s *TimeSeriesStore) AppendQuotes(qList []objects.StockQuote) error {
+	partitions := make(map[string][]objects.StockQuote)
+	for _, q := range qList {
+		path := filepath.Join(s.seriesDir("quotes", q.Symbol), fmt.Sprintf("%d.jsonl", time.Unix(q.Timestamp, 0).UTC().Year()))
+		partitions[path] = append(partitions[path], q)
+	}
+
+	for path, list := range partitions {
+		unlock := s.lock(filepath.Dir(path))
+		err := appendJSONLines(path, list, quoteKey)
+		unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QuoteHistory - stored quotes of symbol with timestamp in from..to oldest first
+func (s *TimeSeriesStore) QuoteHistory(symbol string, from, to time.Time) ([]objects.StockQuote, error) {
+	dir := s.seriesDir("quotes", symbol)
+	defer s.lock(dir)()
+
+	var list []objects.StockQuote
+	for year := from.UTC().Year(); year <= to.UTC().Year(); year++ {
+		byKey, err := readJSONLines(filepath.Join(dir, fmt.Sprintf("%d.jsonl", year)), quoteKey)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, q := range byKey {
+			if q.Timestamp >= from.Unix() && q.Timestamp <= to.Unix() {
+				list = append(list, q)
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Timestamp < list[j].Timestamp })
+
+	return list, nil
+}
+
+// Fundamentals - stored fundamentals newest first, reloaded from API when older than FundamentalsMaxAge
+func (s *TimeSeriesStore) Fundamentals(req objects.RequestFundamentals) ([]objects.Fundamentals, error) {
+	if len(req.Symbol) == 0 {
+		return nil, errors.New("Error empty symbol")
+	}
+
+	dir := s.seriesDir("fundamentals", req.Symbol, fundamentalsSeries(req))
+	defer s.lock(dir)()
+
+	meta, err := readTimeSeriesMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Client != nil && s.now().Sub(meta.Updated) >= s.Config.FundamentalsMaxAge {
+		fList, err := s.Client.CompanyValuation.Fundamentals(objects.RequestFundamentals{
+			Symbol: req.Symbol, Period: req.Period, AsReported: req.AsReported,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := appendJSONLines(filepath.Join(dir, "fundamentals.jsonl"), fList, fundamentalsStoreKey); err != nil {
+			return nil, err
+		}
+
+		meta.Updated = s.now()
+		if err := writeTimeSeriesMeta(dir, meta); err != nil {
+			return nil, err
+		}
+	}
+
+	byKey, err := readJSONLines(filepath.Join(dir, "fundamentals.jsonl"), fundamentalsStoreKey)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]objects.Fundamentals, 0, len(byKey))
+	for _, f := range byKey {
+		list = append(list, f)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Date > list[j].Date })
+	if req.Limit > 0 && int64(len(list)) > req.Limit {
+		list = list[:req.Limit]
+	}
+
+	return list, nil
+}
+
+// lock - lock series dir and return unlock
+func (s *TimeSeriesStore) lock(dir string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[dir]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[dir] = l
+	}
+	s.mu.Unlock()
+
+	l.Lock()
+
+	return l.Unlock
+}
+
+// barLocation - time zone of stored bars, daily bars are dates at midnight UTC
+func (s *TimeSeriesStore) barLocation(resolution objects.Resolution) (*time.Location, error) {
+	if resolution == objects.Resolution1Day {
+		return time.UTC, nil
+	}
+
+	return candleLocation(objects.RequestCandles{Resolution: resolution, Location: s.Config.Location})
+}
+
+// seriesDir - directory of series, symbols are upper case and path safe
+func (s *TimeSeriesStore) seriesDir(kind, symbol string, parts ...string) string {
+	symbol = strings.NewReplacer("/", "_", "\\", "_").Replace(strings.ToUpper(symbol))
+	return filepath.Join(append([]string{s.Config.Dir, kind, symbol}, parts...)...)
+}
+
+func fundamentalsSeries(req objects.RequestFundamentals) string {
+	period := string(req.Period)
+	if len(period) == 0 {
+		period = string(objects.CompanyValuationPeriodAnnual)
+	}
+
+	if req.AsReported {
+		period += "_asreported"
+	}
+
+	return period
+}
+
+func quoteKey(q objects.StockQuote) string {
+	return fmt.Sprintf("%d", q.Timestamp)
+}
+
+func fundamentalsStoreKey(f objects.Fundamentals) string {
+	return f.Date + "_" + f.Period
+}
+
+// barKey - instant of bar, same for dates of any time zone
+func barKey(bar objects.Bar) string {
+	return bar.Date.UTC().Format(time.RFC3339)
+}
+
+// appendBars - append bars to year partitions of wall clock dates of location
+func appendBars(dir string, bars []objects.Bar, location *time.Location) error {
+	partitions := make(map[string][]objects.Bar)
+	for _, bar := range bars {
+		bar.Date = bar.Date.In(location)
+		path := filepath.Join(dir, fmt.Sprintf("%d.jsonl", bar.Date.Year()))
+		partitions[path] = append(partitions[path], bar)
+	}
+
+	for path, list := range partitions {
+		if err := appendJSONLines(path, list, barKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readBars - bars of wall clock dates of location from..to oldest first
+func readBars(dir, from, to string, location *time.Location) ([]objects.Bar, error) {
+	fromDate, err := time.Parse(layoutDate, from)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error read time series")
+	}
+
+	toDate, err := time.Parse(layoutDate, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error read time series")
+	}
+
+	var bars []objects.Bar
+	for year := fromDate.Year(); year <= toDate.Year(); year++ {
+		byKey, err := readJSONLines(filepath.Join(dir, fmt.Sprintf("%d.jsonl", year)), barKey)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bar := range byKey {
+			bar.Date = bar.Date.In(location)
+			if date := bar.Date.Format(layoutDate); date >= from && date <= to {
+				bars = append(bars, bar)
+			}
+		}
+	}
+
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date.Before(bars[j].Date) })
+
+	return bars, nil
+}
+
+// appendJSONLines - append records new to file or changed since last line of key
+func appendJSONLines[T any](path string, list []T, key func(T) string) error {
+	stored, err := readJSONLines(path, key)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, item := range list {
+		data, err := jsoniter.Marshal(item)
+		if err != nil {
+			return errors.Wrap(err, "Error write time series")
+		}
+
+		if old, ok := stored[key(item)]; ok {
+			if oldData, err := jsoniter.Marshal(old); err == nil && bytes.Equal(oldData, data) {
+				continue
+			}
+		}
+
+		stored[key(item)] = item
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "Error write time series")
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "Error write time series")
+	}
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return errors.Wrap(err, "Error write time series")
+	}
+
+	return errors.Wrap(file.Close(), "Error write time series")
+}
+
+// readJSONLines - records by key, later lines win, missing file is empty.
+// Partial last line of interrupted append is skipped.
+func readJSONLines[T any](path string, key func(T) string) (map[string]T, error) {
+	byKey := make(map[string]T)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return byKey, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "Error read time series")
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), timeSeriesMaxLine)
+	for scanner.Scan() {
+		var item T
+		if err := jsoniter.Unmarshal(scanner.Bytes(), &item); err != nil {
+			continue
+		}
+
+		byKey[key(item)] = item
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "Error read time series")
+	}
+
+	return byKey, nil
+}
+
+func readTimeSeriesMeta(dir string) (objects.TimeSeriesMeta, error) {
+	var meta objects.TimeSeriesMeta
+	data, err := os.ReadFile(filepath.Join(dir, "meta.json"))
+	if os.IsNotExist(err) {
+		return meta, nil
+	}
+
+	if err != nil {
+		return meta, errors.Wrap(err, "Error read time series meta")
+	}
+
+	if err := jsoniter.Unmarshal(data, &meta); err != nil {
+		return meta, errors.Wrap(err, "Error read time series meta")
+	}
+
+	return meta, nil
+}
+
+func writeTimeSeriesMeta(dir string, meta objects.TimeSeriesMeta) error {
+	data, err := jsoniter.Marshal(meta)
+	if err != nil {
+		return errors.Wrap(err, "Error write time series meta")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrap(err, "Error write time series meta")
+	}
+
+	// Write to temp file first so a crash never leaves partial meta
+	path := filepath.Join(dir, "meta.json")
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return errors.Wrap(err, "Error write time series meta")
+	}
+
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return errors.Wrap(err, "Error write time series meta")
+	}
+
+	return nil
+}
+
+// mergeRanges - sorted ranges joining overlapping and adjacent dates
+func mergeRanges(list []objects.TimeSeriesRange) []objects.TimeSeriesRange {
+	sort.Slice(list, func(i, j int) bool { return list[i].From < list[j].From })
+
+	var merged []objects.TimeSeriesRange
+	for _, r := range list {
+		if n := len(merged); n > 0 && r.From <= shiftDate(merged[n-1].To, 1) {
+			if r.To > merged[n-1].To {
+				merged[n-1].To = r.To
+			}
+
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// missingRanges - dates from..to outside of sorted ranges
+func missingRanges(ranges []objects.TimeSeriesRange, from, to string) []objects.TimeSeriesRange {
+	var list []objects.TimeSeriesRange
+	cursor := from
+	for _, r := range ranges {
+		if r.To < cursor {
+			continue
+		}
+
+		if r.From > to {
+			break
+		}
+
+		if r.From > cursor {
+			list = append(list, objects.TimeSeriesRange{From: cursor, To: shiftDate(r.From, -1)})
+		}
+
+		cursor = shiftDate(r.To, 1)
+	}
+
+	if cursor <= to {
+		list = append(list, objects.TimeSeriesRange{From: cursor, To: to})
+	}
+
+	return list
+}
+
+// shiftDate - date days later, earlier for negative days
+func shiftDate(date string, days int) string {
+	t, err := time.Parse(layoutDate, date)
+	if err != nil {
+		return date
+	}
+
+	return t.AddDate(0, 0, days).Format(layoutDate)
+}
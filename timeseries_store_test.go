@@ -0,0 +1,155 @@
+package fmpcloud
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestTimeSeriesStore(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-price-full/AAPL?from=2021-01-04&to=2021-01-06": `{"historical":[{"date":"2021-01-06","close":3},{"date":"2021-01-04","close":1}]}`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dir := t.TempDir()
+	store, err := NewTimeSeriesStore(APIClient, TimeSeriesStoreConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	store.now = func() time.Time { return time.Date(2021, 1, 8, 12, 0, 0, 0, time.UTC) }
+
+	req := objects.RequestCandles{
+		Symbol:     "AAPL",
+		Resolution: objects.Resolution1Day,
+		From:       time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2021, 1, 6, 0, 0, 0, 0, time.UTC),
+	}
+
+	bars, err := store.History(req)
+	if err != nil || len(bars) != 2 || bars[0].Close != 1 {
+		t.Fatalf("unexpected history: %+v %v", bars, err)
+	}
+
+	// Wider range loads only dates missing from store
+	APIClient, err = NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-price-full/AAPL?from=2021-01-07&to=2021-01-08": `{"historical":[{"date":"2021-01-08","close":5},{"date":"2021-01-07","close":4}]}`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	store.Client = APIClient
+	req.To = time.Date(2021, 1, 8, 0, 0, 0, 0, time.UTC)
+	bars, err = store.History(req)
+	if err != nil || len(bars) != 4 || bars[3].Close != 5 {
+		t.Fatalf("unexpected read through history: %+v %v", bars, err)
+	}
+
+	// Today is loaded again until the day is over
+	missing, err := store.Missing("AAPL", objects.Resolution1Day, req.From, req.To)
+	if err != nil || len(missing) != 1 || missing[0] != (objects.TimeSeriesRange{From: "2021-01-08", To: "2021-01-08"}) {
+		t.Fatalf("unexpected missing ranges: %+v %v", missing, err)
+	}
+
+	// Unchanged bars are not appended again, changed bars win
+	path := filepath.Join(dir, "bars", "AAPL", "1day", "2021.jsonl")
+	before, _ := os.ReadFile(path)
+	if err := store.AppendBars("AAPL", objects.Resolution1Day, []objects.Bar{bars[0], {Symbol: "AAPL", Date: bars[1].Date, Close: 2.5}}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	after, _ := os.ReadFile(path)
+	if strings.Count(string(after), "\n") != strings.Count(string(before), "\n")+1 {
+		t.Fatalf("expected one appended line:\n%s", after)
+	}
+
+	offline, err := NewTimeSeriesStore(nil, TimeSeriesStoreConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	bars, err = offline.Bars("aapl", objects.Resolution1Day, req.From, req.To)
+	if err != nil || len(bars) != 4 || bars[1].Close != 2.5 {
+		t.Fatalf("unexpected stored bars: %+v %v", bars, err)
+	}
+
+	store.Client, _ = NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/quote/AAPL,MSFT":              `[{"symbol":"AAPL","price":131,"timestamp":1609948800},{"symbol":"MSFT","price":217,"timestamp":1609948800}]`,
+		"/v3/income-statement/TEST":        `[{"date":"2021-12-31","symbol":"TEST","period":"FY","revenue":40}]`,
+		"/v3/balance-sheet-statement/TEST": `[]`,
+		"/v3/cash-flow-statement/TEST":     `[]`,
+	}))
+
+	if _, err := store.Quotes("AAPL", "MSFT"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	qList, err := offline.QuoteHistory("MSFT", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), req.To)
+	if err != nil || len(qList) != 1 || qList[0].Price != 217 {
+		t.Fatalf("unexpected quote history: %+v %v", qList, err)
+	}
+
+	if _, err := store.Fundamentals(objects.RequestFundamentals{Symbol: "TEST"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Fresh fundamentals are served from store
+	store.Client = APIClient
+	fList, err := store.Fundamentals(objects.RequestFundamentals{Symbol: "TEST"})
+	if err != nil || len(fList) != 1 || fList[0].Income == nil || fList[0].Income.Revenue != 40 {
+		t.Fatalf("unexpected stored fundamentals: %+v %v", fList, err)
+	}
+}
+
+func TestTimeSeriesStoreIntraday(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/historical-chart/1hour/AAPL?from=2021-11-29&to=2021-11-29": `[{"date":"2021-11-29 10:30:00","close":2},{"date":"2021-11-29 09:30:00","close":1}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dir := t.TempDir()
+	store, err := NewTimeSeriesStore(APIClient, TimeSeriesStoreConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	store.now = func() time.Time { return time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC) }
+
+	session := time.Date(2021, 11, 29, 0, 0, 0, 0, time.UTC)
+	bars, err := store.History(objects.RequestCandles{Symbol: "AAPL", Resolution: objects.Resolution1Hour, From: session, To: session})
+	if err != nil || len(bars) != 2 {
+		t.Fatalf("unexpected history: %+v %v", bars, err)
+	}
+
+	// Same session with UTC dates does not duplicate bars
+	path := filepath.Join(dir, "bars", "AAPL", "1hour", "2021.jsonl")
+	before, _ := os.ReadFile(path)
+	utcBars := []objects.Bar{
+		{Symbol: "AAPL", Date: time.Date(2021, 11, 29, 14, 30, 0, 0, time.UTC), Close: 1},
+		{Symbol: "AAPL", Date: time.Date(2021, 11, 29, 15, 30, 0, 0, time.UTC), Close: 2},
+	}
+
+	if err := store.AppendBars("AAPL", objects.Resolution1Hour, utcBars); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	after, _ := os.ReadFile(path)
+	if len(before) == 0 || string(after) != string(before) {
+		t.Fatalf("unexpected appended lines:\n%s", after)
+	}
+
+	bars, err = store.Bars("AAPL", objects.Resolution1Hour, session, session)
+	if err != nil || len(bars) != 2 || bars[0].Date.Location() != bars[1].Date.Location() || bars[0].Date.Hour() != 9 {
+		t.Fatalf("unexpected stored bars: %+v %v", bars, err)
+	}
+}
@@ -0,0 +1,459 @@
+package fmpcloud
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // sessions are correct without system zoneinfo
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// Exchange codes of trading calendar
+const (
+	TradingExchangeNYSE   = "NYSE"
+	TradingExchangeNASDAQ = "NASDAQ"
+	TradingExchangeAMEX   = "AMEX"
+)
+
+// usMarketClosureList - unscheduled full day closures of US equity markets
+var usMarketClosureList = map[string]string{
+	"2001-09-11": "September 11",
+	"2001-09-12": "September 11",
+	"2001-09-13": "September 11",
+	"2001-09-14": "September 11",
+	"2004-06-11": "Day of Mourning for Ronald Reagan",
+	"2007-01-02": "Day of Mourning for Gerald Ford",
+	"2012-10-29": "Hurricane Sandy",
+	"2012-10-30": "Hurricane Sandy",
+	"2018-12-05": "Day of Mourning for George H.W. Bush",
+	"2025-01-09": "Day of Mourning for Jimmy Carter",
+}
+
+// TradingCalendar - sessions of exchanges, US holidays are generated from exchange rules
+// and merged with holidays of Stock.ExchangeTradingHours by Refresh
+type TradingCalendar struct {
+	mu        sync.RWMutex
+	exchanges map[string]*ExchangeCalendar
+}
+
+// ExchangeCalendar - sessions of one exchange
+type ExchangeCalendar struct {
+	Exchange string
+	Location *time.Location
+
+	mu             sync.Mutex
+	open           time.Duration // from midnight in exchange time zone
+	close          time.Duration
+	earlyClose     time.Duration
+	announced      map[string]string // holidays from endpoint, "2021 Christmas" => date
+	years          map[int]bool      // years of generated holidays
+	holidays       map[string]string
+	earlyCloseDays map[string]string
+}
+
+// NewTradingCalendar - calendar of US exchanges, works offline
+func NewTradingCalendar() (*TradingCalendar, error) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load exchange time zone")
+	}
+
+	c := &TradingCalendar{exchanges: make(map[string]*ExchangeCalendar)}
+	for _, exchange := range []string{TradingExchangeNYSE, TradingExchangeNASDAQ, TradingExchangeAMEX} {
+		c.exchanges[exchange] = &ExchangeCalendar{
+			Exchange:       exchange,
+			Location:       location,
+			open:           9*time.Hour + 30*time.Minute,
+			close:          16 * time.Hour,
+			earlyClose:     13 * time.Hour,
+			announced:      make(map[string]string),
+			years:          make(map[int]bool),
+			holidays:       make(map[string]string),
+			earlyCloseDays: make(map[string]string),
+		}
+	}
+
+	return c, nil
+}
+
+// Exchange - calendar of exchange code
+func (c *TradingCalendar) Exchange(exchange string) (*ExchangeCalendar, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.exchanges[strings.ToUpper(exchange)]
+	if !ok {
+		return nil, errors.Errorf("Error unknown exchange %s", exchange)
+	}
+
+	return e, nil
+}
+
+// IsOpen - exchange is in regular session at t
+func (c *TradingCalendar) IsOpen(exchange string, t time.Time) (bool, error) {
+	e, err := c.Exchange(exchange)
+	if err != nil {
+		return false, err
+	}
+
+	return e.IsOpen(t), nil
+}
+
+// NextOpen - first session open of exchange after t
+func (c *TradingCalendar) NextOpen(exchange string, t time.Time) (time.Time, error) {
+	e, err := c.Exchange(exchange)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return e.NextOpen(t), nil
+}
+
+// PreviousClose - last session close of exchange at or before t
+func (c *TradingCalendar) PreviousClose(exchange string, t time.Time) (time.Time, error) {
+	e, err := c.Exchange(exchange)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return e.PreviousClose(t), nil
+}
+
+// Refresh - session hours and announced holidays of US exchanges from Stock.ExchangeTradingHours
+func (c *TradingCalendar) Refresh(client *APIClient) error {
+	eList, err := client.Stock.ExchangeTradingHours()
+	if err != nil {
+		return errors.Wrap(err, "Error load exchange trading hours")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, exchange := range eList {
+		open, errOpen := parseExchangeHour(exchange.StockMarketHours.OpeningHour)
+		close, errClose := parseExchangeHour(exchange.StockMarketHours.ClosingHour)
+
+		holidays := make(map[string]string)
+		for _, h := range exchange.StockMarketHolidays {
+			for name, date := range h.Dates() {
+				if _, err := time.Parse(layoutDate, date); err == nil {
+					holidays[date] = name
+				}
+			}
+		}
+
+		// Endpoint describes US stock market as a whole
+		for _, e := range c.exchanges {
+			e.mu.Lock()
+			if errOpen == nil && errClose == nil && open < close {
+				e.open, e.close = open, close
+			}
+
+			for date, name := range holidays {
+				e.announced[date[:4]+" "+name] = date
+			}
+
+			// Generated years are rebuilt with announced dates
+			e.years = make(map[int]bool)
+			e.holidays = make(map[string]string)
+			e.earlyCloseDays = make(map[string]string)
+			e.mu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+// Session - regular session of date in exchange time zone, false when exchange is closed
+func (e *ExchangeCalendar) Session(date time.Time) (objects.TradingSession, bool) {
+	local := date.In(e.Location)
+	day := local.Format(layoutDate)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return objects.TradingSession{}, false
+	}
+
+	e.mu.Lock()
+	e.generate(local.Year())
+	_, holiday := e.holidays[day]
+	_, early := e.earlyCloseDays[day]
+	open, close := e.open, e.close
+	if early {
+		close = e.earlyClose
+	}
+	e.mu.Unlock()
+
+	if holiday {
+		return objects.TradingSession{}, false
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, e.Location)
+
+	return objects.TradingSession{
+		Date:       day,
+		Open:       addClock(midnight, open),
+		Close:      addClock(midnight, close),
+		EarlyClose: early,
+	}, true
+}
+
+// IsTradingDay - exchange has session on date
+func (e *ExchangeCalendar) IsTradingDay(date time.Time) bool {
+	_, ok := e.Session(date)
+	return ok
+}
+
+// IsOpen - t is within regular session, open inclusive and close exclusive
+func (e *ExchangeCalendar) IsOpen(t time.Time) bool {
+	s, ok := e.Session(t)
+	return ok && !t.Before(s.Open) && t.Before(s.Close)
+}
+
+// NextOpen - first session open after t
+func (e *ExchangeCalendar) NextOpen(t time.Time) time.Time {
+	date := t.In(e.Location)
+	for {
+		if s, ok := e.Session(date); ok && s.Open.After(t) {
+			return s.Open
+		}
+
+		date = nextDate(date, 1)
+	}
+}
+
+// PreviousClose - last session close at or before t
+func (e *ExchangeCalendar) PreviousClose(t time.Time) time.Time {
+	date := t.In(e.Location)
+	for {
+		if s, ok := e.Session(date); ok && !s.Close.After(t) {
+			return s.Close
+		}
+
+		date = nextDate(date, -1)
+	}
+}
+
+// Sessions - sessions of dates from..to inclusive in exchange time zone
+func (e *ExchangeCalendar) Sessions(from, to time.Time) []objects.TradingSession {
+	var list []objects.TradingSession
+	last := to.In(e.Location).Format(layoutDate)
+	for date := from.In(e.Location); date.Format(layoutDate) <= last; date = nextDate(date, 1) {
+		if s, ok := e.Session(date); ok {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
+
+// TradingDays - count of sessions of dates from..to inclusive
+func (e *ExchangeCalendar) TradingDays(from, to time.Time) int {
+	return len(e.Sessions(from, to))
+}
+
+// AddTradingDays - session n trading days after date, before date for negative n,
+// date itself does not count
+func (e *ExchangeCalendar) AddTradingDays(date time.Time, n int) objects.TradingSession {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	day := date.In(e.Location)
+	for {
+		day = nextDate(day, step)
+		if s, ok := e.Session(day); ok {
+			n--
+			if n <= 0 {
+				return s
+			}
+		}
+	}
+}
+
+// Holidays - closures and early closes of year sorted by date
+func (e *ExchangeCalendar) Holidays(year int) []objects.TradingHoliday {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.generate(year)
+
+	prefix := fmt.Sprintf("%04d-", year)
+	var list []objects.TradingHoliday
+	for date, name := range e.holidays {
+		if strings.HasPrefix(date, prefix) {
+			list = append(list, objects.TradingHoliday{Date: date, Name: name})
+		}
+	}
+
+	for date, name := range e.earlyCloseDays {
+		if strings.HasPrefix(date, prefix) {
+			list = append(list, objects.TradingHoliday{Date: date, Name: name, EarlyClose: true})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+
+	return list
+}
+
+// generate - NYSE holiday rules of year, caller holds lock
+func (e *ExchangeCalendar) generate(year int) {
+	if e.years[year] {
+		return
+	}
+
+	e.years[year] = true
+	for date, name := range usMarketHolidays(year) {
+		if _, ok := e.announced[strconv.Itoa(year)+" "+name]; !ok {
+			e.holidays[date] = name
+		}
+	}
+
+	for key, date := range e.announced {
+		if strings.HasPrefix(key, strconv.Itoa(year)+" ") {
+			e.holidays[date] = strings.TrimPrefix(key, strconv.Itoa(year)+" ")
+		}
+	}
+
+	for date, name := range usMarketClosureList {
+		if strings.HasPrefix(date, strconv.Itoa(year)) {
+			e.holidays[date] = name
+		}
+	}
+
+	for date, name := range usMarketEarlyCloses(year) {
+		if _, ok := e.holidays[date]; !ok {
+			e.earlyCloseDays[date] = name
+		}
+	}
+}
+
+// usMarketHolidays - observed full day holidays of NYSE, Saturday holidays move to Friday
+// and Sunday holidays to Monday, except New Year's Day on Saturday
+func usMarketHolidays(year int) map[string]string {
+	holidays := make(map[string]string)
+	add := func(date time.Time, name string) {
+		holidays[date.Format(layoutDate)] = name
+	}
+
+	if newYear := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); newYear.Weekday() != time.Saturday {
+		add(observedDate(newYear), "New Years Day")
+	}
+
+	if year >= 1998 {
+		add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King, Jr. Day")
+	}
+
+	add(nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday")
+	add(easterSunday(year).AddDate(0, 0, -2), "Good Friday")
+	add(nthWeekday(year, time.May, time.Monday, -1), "Memorial Day")
+	if year >= 2022 {
+		add(observedDate(time.Date(year, time.June, 19, 0, 0, 0, 0, time.UTC)), "Juneteenth National Independence Day")
+	}
+
+	add(observedDate(time.Date(year, time.July, 4, 0, 0, 0, 0, time.UTC)), "Independence Day")
+	add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+	add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+	add(observedDate(time.Date(year, time.December, 25, 0, 0, 0, 0, time.UTC)), "Christmas")
+
+	return holidays
+}
+
+// usMarketEarlyCloses - 1 p.m. closes before Independence Day, after Thanksgiving and on Christmas Eve
+func usMarketEarlyCloses(year int) map[string]string {
+	closes := make(map[string]string)
+	if july3 := time.Date(year, time.July, 3, 0, 0, 0, 0, time.UTC); july3.Weekday() >= time.Monday && july3.Weekday() <= time.Thursday {
+		closes[july3.Format(layoutDate)] = "Independence Day Eve"
+	}
+
+	closes[nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1).Format(layoutDate)] = "Day after Thanksgiving"
+	if eve := time.Date(year, time.December, 24, 0, 0, 0, 0, time.UTC); eve.Weekday() >= time.Monday && eve.Weekday() <= time.Thursday {
+		closes[eve.Format(layoutDate)] = "Christmas Eve"
+	}
+
+	return closes
+}
+
+func observedDate(date time.Time) time.Time {
+	switch date.Weekday() {
+	case time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+
+	return date
+}
+
+// nthWeekday - n-th weekday of month, negative n counts from month end
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday())-int(weekday)+7)%7)+(n+1)*7)
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+(n-1)*7)
+}
+
+// easterSunday - anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// parseExchangeHour - "09:30 a.m. ET" or "16:00" into time from midnight
+func parseExchangeHour(s string) (time.Duration, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return 0, errors.Errorf("Error parse exchange hour %q", s)
+	}
+
+	parts := strings.SplitN(fields[0], ":", 2)
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error parse exchange hour %q", s)
+	}
+
+	minute := 0
+	if len(parts) == 2 {
+		if minute, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, errors.Wrapf(err, "Error parse exchange hour %q", s)
+		}
+	}
+
+	if len(fields) > 1 && strings.HasPrefix(fields[1], "p") && hour < 12 {
+		hour += 12
+	}
+
+	if len(fields) > 1 && strings.HasPrefix(fields[1], "a") && hour == 12 {
+		hour = 0
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// addClock - wall clock time of day, correct on daylight saving days
+func addClock(midnight time.Time, clock time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+func nextDate(date time.Time, days int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day()+days, 12, 0, 0, 0, date.Location())
+}
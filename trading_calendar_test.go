@@ -0,0 +1,129 @@
+package fmpcloud
+
+import (
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestTradingCalendar(t *testing.T) {
+	calendar, err := NewTradingCalendar()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	nyse, err := calendar.Exchange("nyse")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	et := nyse.Location
+	session, ok := nyse.Session(time.Date(2021, 11, 26, 0, 0, 0, 0, et))
+	if !ok || !session.EarlyClose || session.Close.Hour() != 13 {
+		t.Fatalf("unexpected session after Thanksgiving: %+v", session)
+	}
+
+	// Christmas on Saturday is observed on Friday, New Year 2022 on Saturday is not observed
+	if nyse.IsTradingDay(time.Date(2021, 12, 24, 0, 0, 0, 0, et)) || !nyse.IsTradingDay(time.Date(2021, 12, 31, 0, 0, 0, 0, et)) ||
+		nyse.IsTradingDay(time.Date(2021, 4, 2, 0, 0, 0, 0, et)) || nyse.IsTradingDay(time.Date(2012, 10, 29, 0, 0, 0, 0, et)) {
+		t.Fatal("unexpected holidays")
+	}
+
+	if open, err := calendar.IsOpen(TradingExchangeNASDAQ, time.Date(2021, 11, 26, 17, 0, 0, 0, time.UTC)); err != nil || !open {
+		t.Fatalf("expected open market at 12:00 ET, got %v %v", open, err)
+	}
+
+	if open, _ := calendar.IsOpen(TradingExchangeNASDAQ, time.Date(2021, 11, 26, 18, 30, 0, 0, time.UTC)); open {
+		t.Fatal("expected early close at 13:00 ET")
+	}
+
+	// Friday before Good Friday to Monday after Easter
+	next, err := calendar.NextOpen(TradingExchangeNYSE, time.Date(2021, 4, 1, 16, 0, 0, 0, et))
+	if err != nil || !next.Equal(time.Date(2021, 4, 5, 9, 30, 0, 0, et)) {
+		t.Fatalf("unexpected next open: %v %v", next, err)
+	}
+
+	previous, err := calendar.PreviousClose(TradingExchangeNYSE, time.Date(2021, 4, 5, 9, 0, 0, 0, et))
+	if err != nil || !previous.Equal(time.Date(2021, 4, 1, 16, 0, 0, 0, et)) {
+		t.Fatalf("unexpected previous close: %v %v", previous, err)
+	}
+
+	if days := nyse.TradingDays(time.Date(2021, 1, 1, 0, 0, 0, 0, et), time.Date(2021, 12, 31, 0, 0, 0, 0, et)); days != 252 {
+		t.Fatalf("expected 252 trading days in 2021, got %d", days)
+	}
+
+	if s := nyse.AddTradingDays(time.Date(2021, 12, 23, 0, 0, 0, 0, et), 1); s.Date != "2021-12-27" {
+		t.Fatalf("unexpected next trading day: %+v", s)
+	}
+
+	if _, err := calendar.Exchange("LSE"); err == nil {
+		t.Fatal("expected unknown exchange error")
+	}
+}
+
+func TestTradingCalendarRefresh(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v3/market-hours": `[{"stockExchangeName":"New York Stock Exchange",
+			"stockMarketHours":{"openingHour":"09:30 a.m. ET","closingHour":"04:00 p.m. ET"},
+			"stockMarketHolidays":[{"year":2030,"Christmas":"2030-12-26","Good Friday":"not announced",
+				"Martin Luther King, Jr. Day":"2030-01-22","Washington's Birthday":"2030-02-19"}]}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	calendar, err := NewTradingCalendar()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	nyse, _ := calendar.Exchange(TradingExchangeNYSE)
+	if nyse.IsTradingDay(time.Date(2030, 12, 25, 0, 0, 0, 0, nyse.Location)) {
+		t.Fatal("expected Christmas holiday from rules")
+	}
+
+	if err := calendar.Refresh(APIClient); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !nyse.IsTradingDay(time.Date(2030, 12, 25, 0, 0, 0, 0, nyse.Location)) ||
+		nyse.IsTradingDay(time.Date(2030, 12, 26, 0, 0, 0, 0, nyse.Location)) ||
+		nyse.IsTradingDay(time.Date(2030, 4, 19, 0, 0, 0, 0, nyse.Location)) {
+		t.Fatal("expected announced Christmas and Good Friday from rules")
+	}
+
+	if !nyse.IsTradingDay(time.Date(2030, 1, 21, 0, 0, 0, 0, nyse.Location)) || nyse.IsTradingDay(time.Date(2030, 1, 22, 0, 0, 0, 0, nyse.Location)) ||
+		!nyse.IsTradingDay(time.Date(2030, 2, 18, 0, 0, 0, 0, nyse.Location)) || nyse.IsTradingDay(time.Date(2030, 2, 19, 0, 0, 0, 0, nyse.Location)) {
+		t.Fatal("expected announced MLK and Washington's Birthday")
+	}
+
+	session, _ := nyse.Session(time.Date(2030, 12, 27, 0, 0, 0, 0, nyse.Location))
+	if session.Close.Hour() != 16 || session.Open.Minute() != 30 {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+}
+
+func TestExchangeHolidaysJSON(t *testing.T) {
+	var h objects.ExchangeHolidays
+	payload := `{"year":2021,"New Years Day":"2021-01-01","Martin Luther King, Jr. Day":"2021-01-18",
+		"Washington's Birthday":"2021-02-15","Good Friday":"2021-04-02","Christmas":"2021-12-24"}`
+	if err := jsoniter.Unmarshal([]byte(payload), &h); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if h.Year != 2021 || h.MartinLutherKingJrDay != "2021-01-18" || h.WashingtonsBirthday != "2021-02-15" || h.Christmas != "2021-12-24" {
+		t.Fatalf("unexpected holidays: %+v", h)
+	}
+
+	data, err := jsoniter.Marshal(h)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var decoded objects.ExchangeHolidays
+	if err := jsoniter.Unmarshal(data, &decoded); err != nil || decoded != h {
+		t.Fatalf("unexpected round trip: %s %v", data, err)
+	}
+}
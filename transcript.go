@@ -0,0 +1,484 @@
+package fmpcloud
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+const transcriptSnippetWords = 8
+
+// transcriptSpeakerRegexp - "Timothy D. Cook: text", up to 6 capitalized words before colon
+var transcriptSpeakerRegexp = regexp.MustCompile(`^([A-Z][\w.'&-]*(?:\s+(?:[A-Z][\w.'&-]*|de|van|von|der|del|da|di|la|le)){0,5})\s*:\s*(.*)$`)
+
+// transcriptQAKeywordList - phrases which open question and answer section,
+// operator opens it by any mention of questions
+var transcriptQAKeywordList = []string{
+	"question-and-answer",
+	"question and answer",
+	"q&a",
+	"first question",
+}
+
+// TranscriptFilter - restrict search and keyword counts to turns, empty fields match any
+type TranscriptFilter struct {
+	Symbol  string
+	Role    objects.TranscriptRole
+	Section objects.TranscriptSection
+}
+
+// LoadTranscripts - parsed transcripts of symbol for every quarter between fromYear and toYear, oldest first
+func LoadTranscripts(client *APIClient, symbol string, fromYear, toYear int) ([]objects.ParsedTranscript, error) {
+	var list []objects.ParsedTranscript
+	for year := fromYear; year <= toYear; year++ {
+		for quarter := 1; quarter <= 4; quarter++ {
+			tList, err := client.CompanyValuation.EarningCallTranscript(objects.RequestEarningCallTranscript{
+				Symbol: symbol, Quarter: int64(quarter), Year: int64(year),
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error load transcript %s Q%d %d", symbol, quarter, year)
+			}
+
+			for _, t := range tList {
+				list = append(list, ParseTranscript(t))
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// ParseTranscript - split transcript content into speaker turns.
+// Speakers of prepared remarks are executives, Q&A starts when operator mentions questions
+// after prepared remarks, new Q&A speakers introduced by operator are analysts.
+func ParseTranscript(t objects.EarningCallTranscript) objects.ParsedTranscript {
+	p := objects.ParsedTranscript{Symbol: t.Symbol, Quarter: t.Quarter, Year: t.Year, Date: t.Date}
+
+	var turns []objects.TranscriptTurn
+	for _, line := range strings.Split(t.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if m := transcriptSpeakerRegexp.FindStringSubmatch(line); m != nil {
+			turns = append(turns, objects.TranscriptTurn{Speaker: m[1], Text: m[2]})
+			continue
+		}
+
+		if len(turns) == 0 {
+			turns = append(turns, objects.TranscriptTurn{})
+		}
+
+		last := &turns[len(turns)-1]
+		if len(last.Text) > 0 {
+			last.Text += "\n"
+		}
+
+		last.Text += line
+	}
+
+	roles := make(map[string]objects.TranscriptRole)
+	section := objects.TranscriptSectionPrepared
+	remarks := false
+	for i := range turns {
+		turn := &turns[i]
+		turn.Index = i
+
+		operator := strings.EqualFold(turn.Speaker, "operator")
+		if section == objects.TranscriptSectionPrepared && remarks && isQAOpening(turn.Text, operator) {
+			section = objects.TranscriptSectionQA
+		}
+
+		turn.Section = section
+		switch {
+		case operator:
+			turn.Role = objects.TranscriptRoleOperator
+		case len(roles[turn.Speaker]) > 0:
+			turn.Role = roles[turn.Speaker]
+		case section == objects.TranscriptSectionPrepared:
+			turn.Role = objects.TranscriptRoleExecutive
+		case strings.Contains(strings.ToLower(turn.Speaker), "analyst"),
+			i > 0 && turns[i-1].Role == objects.TranscriptRoleOperator:
+			turn.Role = objects.TranscriptRoleAnalyst
+		default:
+			turn.Role = objects.TranscriptRoleExecutive
+		}
+
+		if len(turn.Speaker) > 0 && !operator {
+			roles[turn.Speaker] = turn.Role
+			remarks = true
+		}
+	}
+
+	p.TurnList = turns
+
+	return p
+}
+
+// TranscriptKeywordTrend - count of each keyword or phrase in every transcript, sorted by year and quarter
+func TranscriptKeywordTrend(transcriptList []objects.ParsedTranscript, keywords []string, filter TranscriptFilter) []objects.TranscriptKeywordCount {
+	list := make([]objects.ParsedTranscript, 0, len(transcriptList))
+	for _, t := range transcriptList {
+		if len(filter.Symbol) == 0 || strings.EqualFold(filter.Symbol, t.Symbol) {
+			list = append(list, t)
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Year != list[j].Year {
+			return list[i].Year < list[j].Year
+		}
+
+		return list[i].Quarter < list[j].Quarter
+	})
+
+	var countList []objects.TranscriptKeywordCount
+	for _, t := range list {
+		var turnTokens [][]transcriptToken
+		wordCount := 0
+		for _, turn := range t.TurnList {
+			if filter.match(t, turn) {
+				tokens := tokenizeTranscript(turn.Text)
+				turnTokens = append(turnTokens, tokens)
+				wordCount += len(tokens)
+			}
+		}
+
+		for _, keyword := range keywords {
+			terms := transcriptTerms(keyword)
+			c := objects.TranscriptKeywordCount{
+				Symbol:    t.Symbol,
+				Quarter:   t.Quarter,
+				Year:      t.Year,
+				Date:      t.Date,
+				Keyword:   keyword,
+				WordCount: wordCount,
+			}
+
+			for _, tokens := range turnTokens {
+				c.Count += len(phrasePositions(tokens, terms))
+			}
+
+			if wordCount > 0 {
+				c.Frequency = float64(c.Count) / float64(wordCount) * 10000
+			}
+
+			countList = append(countList, c)
+		}
+	}
+
+	return countList
+}
+
+// TranscriptIndex - in memory full text index over parsed transcripts
+type TranscriptIndex struct {
+	mu       sync.RWMutex
+	docs     []objects.ParsedTranscript
+	keys     map[string]bool
+	turns    []transcriptIndexTurn
+	postings map[string][]int // term => indexes of turns containing term
+}
+
+type transcriptIndexTurn struct {
+	doc    int
+	turn   int
+	tokens []transcriptToken
+}
+
+type transcriptToken struct {
+	term       string
+	start, end int // byte offsets in turn text
+}
+
+// NewTranscriptIndex ...
+func NewTranscriptIndex() *TranscriptIndex {
+	return &TranscriptIndex{
+		keys:     make(map[string]bool),
+		postings: make(map[string][]int),
+	}
+}
+
+// ReadTranscriptIndex - index from transcripts written by TranscriptIndex.Write
+func ReadTranscriptIndex(r io.Reader) (*TranscriptIndex, error) {
+	var list []objects.ParsedTranscript
+	if err := jsoniter.NewDecoder(r).Decode(&list); err != nil {
+		return nil, errors.Wrap(err, "Error read transcript index")
+	}
+
+	idx := NewTranscriptIndex()
+	idx.Add(list...)
+
+	return idx, nil
+}
+
+// Write - store indexed transcripts as json, index is rebuilt by ReadTranscriptIndex
+func (idx *TranscriptIndex) Write(w io.Writer) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return jsoniter.NewEncoder(w).Encode(idx.docs)
+}
+
+// Add - index transcripts, transcripts of already indexed symbol and quarter are skipped
+func (idx *TranscriptIndex) Add(transcriptList ...objects.ParsedTranscript) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, t := range transcriptList {
+		key := transcriptKey(t)
+		if idx.keys[key] {
+			continue
+		}
+
+		idx.keys[key] = true
+		idx.docs = append(idx.docs, t)
+		doc := len(idx.docs) - 1
+		for n, turn := range t.TurnList {
+			tokens := tokenizeTranscript(turn.Text)
+			idx.turns = append(idx.turns, transcriptIndexTurn{doc: doc, turn: n, tokens: tokens})
+
+			seen := make(map[string]bool)
+			for _, token := range tokens {
+				if !seen[token.term] {
+					seen[token.term] = true
+					idx.postings[token.term] = append(idx.postings[token.term], len(idx.turns)-1)
+				}
+			}
+		}
+	}
+}
+
+// Len - count of indexed transcripts
+func (idx *TranscriptIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+// SearchPhrase - turns containing words of phrase in order, case and punctuation are ignored
+func (idx *TranscriptIndex) SearchPhrase(phrase string, filter TranscriptFilter) []objects.TranscriptHit {
+	terms := transcriptTerms(phrase)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	return idx.search(terms, filter, func(tokens []transcriptToken) ([]int, int) {
+		return phrasePositions(tokens, terms), len(terms)
+	})
+}
+
+// SearchNear - turns where phrases first and second start within distance words of each other
+func (idx *TranscriptIndex) SearchNear(first, second string, distance int, filter TranscriptFilter) []objects.TranscriptHit {
+	firstTerms, secondTerms := transcriptTerms(first), transcriptTerms(second)
+	if len(firstTerms) == 0 || len(secondTerms) == 0 {
+		return nil
+	}
+
+	return idx.search(append(firstTerms, secondTerms...), filter, func(tokens []transcriptToken) ([]int, int) {
+		secondList := phrasePositions(tokens, secondTerms)
+
+		var positions []int
+		width := 0
+		for _, a := range phrasePositions(tokens, firstTerms) {
+			for _, b := range secondList {
+				if b-a > distance || a-b > distance {
+					continue
+				}
+
+				start, end := a, a+len(firstTerms)
+				if b < start {
+					start = b
+				}
+
+				if e := b + len(secondTerms); e > end {
+					end = e
+				}
+
+				positions = append(positions, start)
+				if end-start > width {
+					width = end - start
+				}
+
+				break
+			}
+		}
+
+		return positions, width
+	})
+}
+
+// search - candidate turns contain every term, match returns positions and matched width in words
+func (idx *TranscriptIndex) search(terms []string, filter TranscriptFilter, match func([]transcriptToken) ([]int, int)) []objects.TranscriptHit {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	candidates := idx.postings[terms[0]]
+	for _, term := range terms[1:] {
+		if list := idx.postings[term]; len(list) < len(candidates) {
+			candidates = list
+		}
+	}
+
+	var hitList []objects.TranscriptHit
+	for _, n := range candidates {
+		it := idx.turns[n]
+		doc := idx.docs[it.doc]
+		turn := doc.TurnList[it.turn]
+		if !filter.match(doc, turn) {
+			continue
+		}
+
+		positions, width := match(it.tokens)
+		for _, pos := range positions {
+			hitList = append(hitList, objects.TranscriptHit{
+				Symbol:    doc.Symbol,
+				Quarter:   doc.Quarter,
+				Year:      doc.Year,
+				Date:      doc.Date,
+				TurnIndex: turn.Index,
+				Speaker:   turn.Speaker,
+				Role:      turn.Role,
+				Section:   turn.Section,
+				Position:  pos,
+				Snippet:   transcriptSnippet(turn.Text, it.tokens, pos, pos+width),
+			})
+		}
+	}
+
+	sort.SliceStable(hitList, func(i, j int) bool {
+		a, b := hitList[i], hitList[j]
+		switch {
+		case a.Symbol != b.Symbol:
+			return a.Symbol < b.Symbol
+		case a.Year != b.Year:
+			return a.Year < b.Year
+		case a.Quarter != b.Quarter:
+			return a.Quarter < b.Quarter
+		case a.TurnIndex != b.TurnIndex:
+			return a.TurnIndex < b.TurnIndex
+		}
+
+		return a.Position < b.Position
+	})
+
+	return hitList
+}
+
+func (f TranscriptFilter) match(t objects.ParsedTranscript, turn objects.TranscriptTurn) bool {
+	return (len(f.Symbol) == 0 || strings.EqualFold(f.Symbol, t.Symbol)) &&
+		(len(f.Role) == 0 || f.Role == turn.Role) &&
+		(len(f.Section) == 0 || f.Section == turn.Section)
+}
+
+func isQAOpening(text string, operator bool) bool {
+	text = strings.ToLower(text)
+	if operator {
+		return strings.Contains(text, "question")
+	}
+
+	for _, keyword := range transcriptQAKeywordList {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tokenizeTranscript - lower case words of letters and digits with byte offsets
+func tokenizeTranscript(text string) []transcriptToken {
+	var tokens []transcriptToken
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, transcriptToken{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, transcriptToken{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+
+	return tokens
+}
+
+func transcriptTerms(text string) []string {
+	tokens := tokenizeTranscript(text)
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = token.term
+	}
+
+	return terms
+}
+
+// phrasePositions - start positions of terms in tokens
+func phrasePositions(tokens []transcriptToken, terms []string) []int {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var positions []int
+	for i := 0; i+len(terms) <= len(tokens); i++ {
+		matched := true
+		for k, term := range terms {
+			if tokens[i+k].term != term {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+// transcriptSnippet - text of words from..to with surrounding words
+func transcriptSnippet(text string, tokens []transcriptToken, from, to int) string {
+	first, last := from-transcriptSnippetWords, to-1+transcriptSnippetWords
+	if first < 0 {
+		first = 0
+	}
+
+	if last >= len(tokens) {
+		last = len(tokens) - 1
+	}
+
+	end := len(text)
+	if last < len(tokens)-1 {
+		end = tokens[last].end
+	}
+
+	snippet := strings.Join(strings.Fields(text[tokens[first].start:end]), " ")
+	if first > 0 {
+		snippet = "..." + snippet
+	}
+
+	if end < len(text) {
+		snippet += "..."
+	}
+
+	return snippet
+}
+
+func transcriptKey(t objects.ParsedTranscript) string {
+	return fmt.Sprintf("%s|%d|%d", strings.ToUpper(t.Symbol), t.Year, t.Quarter)
+}
@@ -0,0 +1,103 @@
+package fmpcloud
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+const testTranscriptContent = `Operator: Good day and welcome to the Test Inc. earnings call. Later we will conduct a question-and-answer session.
+Jane Roe: Thank you. Revenue grew on strong demand and supply chain improvements.
+We expect gross margin to expand next year.
+John Smith: Supply chain costs declined and gross margin reached 40%.
+Operator: Thank you. Our first question comes from Mark Lee with Big Bank.
+Mark Lee: Can you talk about the supply chain and pricing?
+Jane Roe: Pricing remained firm while supply chain constraints eased.
+Operator: Next question from Unidentified Analyst.
+Unidentified Analyst: What about margin?
+Alex Doe: Margin was in line with guidance.`
+
+func TestParseTranscript(t *testing.T) {
+	p := ParseTranscript(objects.EarningCallTranscript{Symbol: "TEST", Quarter: 2, Year: 2021, Content: testTranscriptContent})
+	if len(p.TurnList) != 9 {
+		t.Fatalf("expected 9 turns, got %+v", p.TurnList)
+	}
+
+	expected := []struct {
+		speaker string
+		role    objects.TranscriptRole
+		section objects.TranscriptSection
+	}{
+		{"Operator", objects.TranscriptRoleOperator, objects.TranscriptSectionPrepared},
+		{"Jane Roe", objects.TranscriptRoleExecutive, objects.TranscriptSectionPrepared},
+		{"John Smith", objects.TranscriptRoleExecutive, objects.TranscriptSectionPrepared},
+		{"Operator", objects.TranscriptRoleOperator, objects.TranscriptSectionQA},
+		{"Mark Lee", objects.TranscriptRoleAnalyst, objects.TranscriptSectionQA},
+		{"Jane Roe", objects.TranscriptRoleExecutive, objects.TranscriptSectionQA},
+		{"Operator", objects.TranscriptRoleOperator, objects.TranscriptSectionQA},
+		{"Unidentified Analyst", objects.TranscriptRoleAnalyst, objects.TranscriptSectionQA},
+		{"Alex Doe", objects.TranscriptRoleExecutive, objects.TranscriptSectionQA},
+	}
+
+	for i, e := range expected {
+		turn := p.TurnList[i]
+		if turn.Speaker != e.speaker || turn.Role != e.role || turn.Section != e.section {
+			t.Fatalf("unexpected turn %d: %+v", i, turn)
+		}
+	}
+
+	if p.TurnList[1].Text != "Thank you. Revenue grew on strong demand and supply chain improvements.\nWe expect gross margin to expand next year." {
+		t.Fatalf("unexpected continuation text: %q", p.TurnList[1].Text)
+	}
+}
+
+func TestTranscriptIndex(t *testing.T) {
+	q1 := ParseTranscript(objects.EarningCallTranscript{Symbol: "TEST", Quarter: 1, Year: 2021, Content: "Jane Roe: Supply chain issues hurt gross margin."})
+	q2 := ParseTranscript(objects.EarningCallTranscript{Symbol: "TEST", Quarter: 2, Year: 2021, Content: testTranscriptContent})
+
+	idx := NewTranscriptIndex()
+	idx.Add(q2, q1, q1)
+	if idx.Len() != 2 {
+		t.Fatalf("expected 2 transcripts, got %d", idx.Len())
+	}
+
+	hitList := idx.SearchPhrase("Supply-Chain", TranscriptFilter{})
+	if len(hitList) != 5 || hitList[0].Quarter != 1 || hitList[0].Snippet != "Supply chain issues hurt gross margin." {
+		t.Fatalf("unexpected phrase hits: %+v", hitList)
+	}
+
+	hitList = idx.SearchPhrase("supply chain", TranscriptFilter{Role: objects.TranscriptRoleAnalyst})
+	if len(hitList) != 1 || hitList[0].Speaker != "Mark Lee" || hitList[0].Section != objects.TranscriptSectionQA {
+		t.Fatalf("unexpected analyst hits: %+v", hitList)
+	}
+
+	hitList = idx.SearchNear("supply chain", "gross margin", 5, TranscriptFilter{Symbol: "TEST"})
+	if len(hitList) != 3 || hitList[0].Quarter != 1 || hitList[2].Speaker != "John Smith" {
+		t.Fatalf("unexpected proximity hits: %+v", hitList)
+	}
+
+	var buf bytes.Buffer
+	if err := idx.Write(&buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	restored, err := ReadTranscriptIndex(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(restored.SearchPhrase("pricing", TranscriptFilter{})) != 2 {
+		t.Fatal("expected pricing hits in restored index")
+	}
+
+	countList := TranscriptKeywordTrend([]objects.ParsedTranscript{q2, q1}, []string{"supply chain", "margin"}, TranscriptFilter{})
+	if len(countList) != 4 || countList[0].Quarter != 1 || countList[0].Count != 1 || countList[2].Count != 4 || countList[3].Count != 4 {
+		t.Fatalf("unexpected keyword trend: %+v", countList)
+	}
+
+	if countList[0].WordCount != 6 || math.Abs(countList[0].Frequency-10000.0/6) > 1e-9 {
+		t.Fatalf("unexpected frequency: %+v", countList[0])
+	}
+}
@@ -0,0 +1,345 @@
+package fmpcloud
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+// YieldCurve - treasury curve of one date. Rates are in percent and treated as
+// continuously compounded for discount factors and forward rates.
+type YieldCurve struct {
+	Date      string
+	Method    objects.YieldCurveMethod
+	PointList []objects.YieldCurvePoint // sorted by tenor
+	NSS       *objects.YieldCurveNSS    // fitted parameters for Nelson-Siegel-Svensson method
+
+	spline []float64 // second derivatives at points for cubic spline method
+}
+
+// YieldCurve - curve from latest treasury rates on or before date
+func (e *Economics) YieldCurve(date time.Time, method objects.YieldCurveMethod) (*YieldCurve, error) {
+	rateList, err := e.TreasuryRates(date.AddDate(0, 0, -14), date)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rateList) == 0 {
+		return nil, errors.New("Error empty treasury rates")
+	}
+
+	sort.Slice(rateList, func(i, j int) bool { return rateList[i].Date > rateList[j].Date })
+
+	return NewYieldCurve(rateList[0], method)
+}
+
+// NewYieldCurve - curve from treasury rates row, tenors listed in Missing are skipped
+func NewYieldCurve(rates objects.EconomicsTreasuryRates, method objects.YieldCurveMethod) (*YieldCurve, error) {
+	missing := make(map[string]bool, len(rates.Missing))
+	for _, name := range rates.Missing {
+		missing[name] = true
+	}
+
+	c := &YieldCurve{Date: rates.Date, Method: method}
+	for i, p := range []objects.YieldCurvePoint{
+		{Tenor: 1.0 / 12, Rate: rates.Month1},
+		{Tenor: 2.0 / 12, Rate: rates.Month2},
+		{Tenor: 3.0 / 12, Rate: rates.Month3},
+		{Tenor: 6.0 / 12, Rate: rates.Month6},
+		{Tenor: 1, Rate: rates.Year1},
+		{Tenor: 2, Rate: rates.Year2},
+		{Tenor: 3, Rate: rates.Year3},
+		{Tenor: 5, Rate: rates.Year5},
+		{Tenor: 7, Rate: rates.Year7},
+		{Tenor: 10, Rate: rates.Year10},
+		{Tenor: 20, Rate: rates.Year20},
+		{Tenor: 30, Rate: rates.Year30},
+	} {
+		if !missing[objects.TreasuryRateTenorList[i]] {
+			c.PointList = append(c.PointList, p)
+		}
+	}
+
+	if err := c.fit(); err != nil {
+		return nil, errors.Wrapf(err, "Error build yield curve %s", rates.Date)
+	}
+
+	return c, nil
+}
+
+// NewYieldCurveFromPoints - curve from arbitrary tenors
+func NewYieldCurveFromPoints(date string, pointList []objects.YieldCurvePoint, method objects.YieldCurveMethod) (*YieldCurve, error) {
+	c := &YieldCurve{Date: date, Method: method, PointList: mergeYieldCurvePoints(pointList)}
+
+	if err := c.fit(); err != nil {
+		return nil, errors.Wrapf(err, "Error build yield curve %s", date)
+	}
+
+	return c, nil
+}
+
+// mergeYieldCurvePoints - points sorted by tenor, rates of duplicate tenors are averaged
+func mergeYieldCurvePoints(pointList []objects.YieldCurvePoint) []objects.YieldCurvePoint {
+	sorted := make([]objects.YieldCurvePoint, len(pointList))
+	copy(sorted, pointList)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Tenor < sorted[j].Tenor })
+
+	var merged []objects.YieldCurvePoint
+	for i := 0; i < len(sorted); {
+		j, total := i, 0.0
+		for ; j < len(sorted) && sorted[j].Tenor == sorted[i].Tenor; j++ {
+			total += sorted[j].Rate
+		}
+
+		merged = append(merged, objects.YieldCurvePoint{Tenor: sorted[i].Tenor, Rate: total / float64(j-i)})
+		i = j
+	}
+
+	return merged
+}
+
+// Rate - rate of tenor in years, flat beyond observed tenors for linear and spline methods
+func (c *YieldCurve) Rate(tenor float64) float64 {
+	if c.Method == objects.YieldCurveMethodNelsonSiegelSvensson {
+		return nssRate(*c.NSS, tenor)
+	}
+
+	points := c.PointList
+	if tenor <= points[0].Tenor {
+		return points[0].Rate
+	}
+
+	last := len(points) - 1
+	if tenor >= points[last].Tenor {
+		return points[last].Rate
+	}
+
+	i := sort.Search(len(points), func(i int) bool { return points[i].Tenor >= tenor })
+	if points[i].Tenor == tenor {
+		return points[i].Rate
+	}
+
+	lo, hi := points[i-1], points[i]
+	h := hi.Tenor - lo.Tenor
+	a := (hi.Tenor - tenor) / h
+	b := (tenor - lo.Tenor) / h
+
+	rate := a*lo.Rate + b*hi.Rate
+	if c.Method == objects.YieldCurveMethodCubicSpline {
+		rate += ((a*a*a-a)*c.spline[i-1] + (b*b*b-b)*c.spline[i]) * h * h / 6
+	}
+
+	return rate
+}
+
+// DiscountFactor - present value of 1 paid at tenor
+func (c *YieldCurve) DiscountFactor(tenor float64) float64 {
+	return math.Exp(-c.Rate(tenor) / 100 * tenor)
+}
+
+// ForwardRate - rate in percent between tenors from and to
+func (c *YieldCurve) ForwardRate(from, to float64) float64 {
+	if to <= from {
+		return c.Rate(from)
+	}
+
+	return (c.Rate(to)*to - c.Rate(from)*from) / (to - from)
+}
+
+// Shape - spreads and inversion flags of curve
+func (c *YieldCurve) Shape() objects.YieldCurveShape {
+	s := objects.YieldCurveShape{
+		Date:   c.Date,
+		Month3: c.Rate(0.25),
+		Year2:  c.Rate(2),
+		Year10: c.Rate(10),
+		Year30: c.Rate(30),
+	}
+
+	s.Spread2s10s = s.Year10 - s.Year2
+	s.Spread3m10y = s.Year10 - s.Month3
+	s.Spread10s30s = s.Year30 - s.Year10
+	s.Butterfly = 2*s.Year10 - s.Year2 - s.Year30
+	s.Inverted2s10s = s.Spread2s10s < 0
+	s.Inverted3m10y = s.Spread3m10y < 0
+
+	return s
+}
+
+// YieldCurveShapes - curve shape of each treasury rates row, oldest first
+func YieldCurveShapes(rateList []objects.EconomicsTreasuryRates, method objects.YieldCurveMethod) ([]objects.YieldCurveShape, error) {
+	list := make([]objects.YieldCurveShape, 0, len(rateList))
+	for _, rates := range rateList {
+		c, err := NewYieldCurve(rates, method)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, c.Shape())
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+
+	return list, nil
+}
+
+func (c *YieldCurve) fit() error {
+	switch c.Method {
+	case objects.YieldCurveMethodLinear:
+		if len(c.PointList) < 2 {
+			return errors.New("Error need at least 2 tenors")
+		}
+	case objects.YieldCurveMethodCubicSpline:
+		if len(c.PointList) < 3 {
+			return errors.New("Error need at least 3 tenors")
+		}
+
+		c.spline = naturalSpline(c.PointList)
+	case objects.YieldCurveMethodNelsonSiegelSvensson:
+		if len(c.PointList) < 6 {
+			return errors.New("Error need at least 6 tenors")
+		}
+
+		nss, err := fitNSS(c.PointList)
+		if err != nil {
+			return err
+		}
+
+		c.NSS = &nss
+	default:
+		return errors.Errorf("Error unknown yield curve method %s", c.Method)
+	}
+
+	return nil
+}
+
+// naturalSpline - second derivatives of natural cubic spline through points
+func naturalSpline(points []objects.YieldCurvePoint) []float64 {
+	n := len(points)
+	y2 := make([]float64, n)
+	u := make([]float64, n)
+
+	for i := 1; i < n-1; i++ {
+		sig := (points[i].Tenor - points[i-1].Tenor) / (points[i+1].Tenor - points[i-1].Tenor)
+		p := sig*y2[i-1] + 2
+		y2[i] = (sig - 1) / p
+		d := (points[i+1].Rate-points[i].Rate)/(points[i+1].Tenor-points[i].Tenor) -
+			(points[i].Rate-points[i-1].Rate)/(points[i].Tenor-points[i-1].Tenor)
+		u[i] = (6*d/(points[i+1].Tenor-points[i-1].Tenor) - sig*u[i-1]) / p
+	}
+
+	y2[n-1] = 0
+	for i := n - 2; i >= 0; i-- {
+		y2[i] = y2[i]*y2[i+1] + u[i]
+	}
+
+	return y2
+}
+
+// fitNSS - grid search over decay parameters, betas by least squares for each pair
+func fitNSS(points []objects.YieldCurvePoint) (objects.YieldCurveNSS, error) {
+	var taus []float64
+	for tau := 0.1; tau <= 30; tau *= 1.15 {
+		taus = append(taus, tau)
+	}
+
+	best := objects.YieldCurveNSS{RMSE: math.Inf(1)}
+	for i, tau1 := range taus {
+		for _, tau2 := range taus[i+1:] {
+			betas, ok := nssBetas(points, tau1, tau2)
+			if !ok {
+				continue
+			}
+
+			nss := objects.YieldCurveNSS{Beta0: betas[0], Beta1: betas[1], Beta2: betas[2], Beta3: betas[3], Tau1: tau1, Tau2: tau2}
+			sse := 0.0
+			for _, p := range points {
+				d := nssRate(nss, p.Tenor) - p.Rate
+				sse += d * d
+			}
+
+			nss.RMSE = math.Sqrt(sse / float64(len(points)))
+			if nss.RMSE < best.RMSE {
+				best = nss
+			}
+		}
+	}
+
+	if math.IsInf(best.RMSE, 1) {
+		return best, errors.New("Error fit Nelson-Siegel-Svensson curve")
+	}
+
+	return best, nil
+}
+
+// nssBetas - least squares betas for fixed decay parameters
+func nssBetas(points []objects.YieldCurvePoint, tau1, tau2 float64) ([]float64, bool) {
+	var ata [4][4]float64
+	var aty [4]float64
+	for _, p := range points {
+		row := nssLoadings(p.Tenor, tau1, tau2)
+		for i := 0; i < 4; i++ {
+			aty[i] += row[i] * p.Rate
+			for j := 0; j < 4; j++ {
+				ata[i][j] += row[i] * row[j]
+			}
+		}
+	}
+
+	// Gaussian elimination with partial pivoting
+	for col := 0; col < 4; col++ {
+		pivot := col
+		for r := col + 1; r < 4; r++ {
+			if math.Abs(ata[r][col]) > math.Abs(ata[pivot][col]) {
+				pivot = r
+			}
+		}
+
+		if math.Abs(ata[pivot][col]) < 1e-10 {
+			return nil, false
+		}
+
+		ata[col], ata[pivot] = ata[pivot], ata[col]
+		aty[col], aty[pivot] = aty[pivot], aty[col]
+		for r := col + 1; r < 4; r++ {
+			f := ata[r][col] / ata[col][col]
+			for k := col; k < 4; k++ {
+				ata[r][k] -= f * ata[col][k]
+			}
+
+			aty[r] -= f * aty[col]
+		}
+	}
+
+	betas := make([]float64, 4)
+	for i := 3; i >= 0; i-- {
+		v := aty[i]
+		for k := i + 1; k < 4; k++ {
+			v -= ata[i][k] * betas[k]
+		}
+
+		betas[i] = v / ata[i][i]
+	}
+
+	return betas, true
+}
+
+func nssRate(nss objects.YieldCurveNSS, tenor float64) float64 {
+	l := nssLoadings(tenor, nss.Tau1, nss.Tau2)
+	return nss.Beta0*l[0] + nss.Beta1*l[1] + nss.Beta2*l[2] + nss.Beta3*l[3]
+}
+
+func nssLoadings(tenor, tau1, tau2 float64) [4]float64 {
+	if tenor <= 0 {
+		return [4]float64{1, 1, 0, 0}
+	}
+
+	x1, x2 := tenor/tau1, tenor/tau2
+	f1 := (1 - math.Exp(-x1)) / x1
+	f2 := (1 - math.Exp(-x2)) / x2
+
+	return [4]float64{1, f1, f1 - math.Exp(-x1), f2 - math.Exp(-x2)}
+}
@@ -0,0 +1,99 @@
+package fmpcloud
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spacecodewor/fmpcloud-go/objects"
+)
+
+func TestYieldCurve(t *testing.T) {
+	APIClient, err := NewAPIClient(testCaseMockConfig(t, map[string]string{
+		"/v4/treasury": `[
+			{"date":"2023-03-01","month1":4.6,"month2":4.7,"month3":4.8,"month6":5.0,"year1":5.0,"year2":4.9,"year3":4.6,"year5":4.3,"year7":4.2,"year10":4.0,"year20":4.2,"year30":4.0},
+			{"date":"2023-02-28","month1":4.5,"month2":4.6,"month3":4.7,"month6":4.9,"year1":4.9,"year2":4.8,"year3":4.5,"year5":4.2,"year7":4.1,"year10":3.9,"year20":4.1,"year30":3.9}]`,
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	curve, err := APIClient.Economics.YieldCurve(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), objects.YieldCurveMethodLinear)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if curve.Date != "2023-03-01" || math.Abs(curve.Rate(4)-4.45) > 1e-9 || curve.Rate(50) != 4.0 {
+		t.Fatalf("unexpected linear curve: %v %v", curve.Date, curve.Rate(4))
+	}
+
+	if math.Abs(curve.DiscountFactor(2)-math.Exp(-0.098)) > 1e-12 || math.Abs(curve.ForwardRate(2, 3)-(4.6*3-4.9*2)) > 1e-9 {
+		t.Fatalf("unexpected discount factor or forward rate")
+	}
+
+	shape := curve.Shape()
+	if !shape.Inverted2s10s || math.Abs(shape.Spread2s10s+0.9) > 1e-9 || !shape.Inverted3m10y {
+		t.Fatalf("unexpected shape: %+v", shape)
+	}
+
+	var rates objects.EconomicsTreasuryRates
+	if err := jsoniter.UnmarshalFromString(`{"date":"2023-03-01","year1":1,"year2":2,"year3":null,"year5":3,"year10":3.5}`, &rates); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	spline, err := NewYieldCurve(rates, objects.YieldCurveMethodCubicSpline)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if spline.Rate(2) != 2 || spline.Rate(3) <= 2 || spline.Rate(3) >= 3 {
+		t.Fatalf("unexpected spline rates: %v %v", spline.Rate(2), spline.Rate(3))
+	}
+
+	nss := objects.YieldCurveNSS{Beta0: 4, Beta1: -2, Beta2: 1, Beta3: 0.5, Tau1: 1.5, Tau2: 8}
+	var points []objects.YieldCurvePoint
+	for _, tenor := range []float64{0.25, 0.5, 1, 2, 3, 5, 7, 10, 20, 30} {
+		points = append(points, objects.YieldCurvePoint{Tenor: tenor, Rate: nssRate(nss, tenor)})
+	}
+
+	fitted, err := NewYieldCurveFromPoints("2023-03-01", points, objects.YieldCurveMethodNelsonSiegelSvensson)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if fitted.NSS.RMSE > 0.01 || math.Abs(fitted.Rate(4)-nssRate(nss, 4)) > 0.02 {
+		t.Fatalf("unexpected NSS fit: %+v", fitted.NSS)
+	}
+
+	// Duplicate tenors are averaged instead of dividing by zero interval
+	duplicate, err := NewYieldCurveFromPoints("2023-03-01", []objects.YieldCurvePoint{
+		{Tenor: 5, Rate: 4}, {Tenor: 2, Rate: 3}, {Tenor: 1, Rate: 2}, {Tenor: 2, Rate: 5},
+	}, objects.YieldCurveMethodCubicSpline)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(duplicate.PointList) != 3 || duplicate.Rate(2) != 4 || math.IsNaN(duplicate.Rate(3)) || math.IsInf(duplicate.Rate(3), 0) {
+		t.Fatalf("unexpected duplicate tenor curve: %+v %v", duplicate.PointList, duplicate.Rate(3))
+	}
+
+	// Zero rates of zero interest rate era are tenors of curve
+	zero, err := NewYieldCurve(objects.EconomicsTreasuryRates{Date: "2021-01-04", Month1: 0, Month3: 0.05, Year2: 0.1, Year10: 0.9,
+		Missing: []string{"month2", "month6", "year1", "year3", "year5", "year7", "year20", "year30"}}, objects.YieldCurveMethodLinear)
+	if err != nil || len(zero.PointList) != 4 || zero.Rate(1.0/12) != 0 || math.Abs(zero.Rate(2.0/12)-0.025) > 1e-9 {
+		t.Fatalf("unexpected zero rate curve: %+v %v", zero, err)
+	}
+
+	shapes, err := YieldCurveShapes([]objects.EconomicsTreasuryRates{
+		{Date: "2023-03-01", Month3: 5, Year2: 4, Year10: 4.5, Year30: 4.6},
+		{Date: "2023-02-28", Month3: 5, Year2: 5, Year10: 4.5, Year30: 4.6},
+	}, objects.YieldCurveMethodLinear)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if shapes[0].Date != "2023-02-28" || !shapes[0].Inverted2s10s || shapes[1].Inverted2s10s {
+		t.Fatalf("unexpected shapes: %+v", shapes)
+	}
+}